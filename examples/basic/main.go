@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/jacobdavidalcock/consolekit/pkg/command"
 	"github.com/jacobdavidalcock/consolekit/pkg/config"
 	"github.com/jacobdavidalcock/consolekit/pkg/console"
+	"github.com/jacobdavidalcock/consolekit/pkg/httpclient"
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
 )
 
 // Example CLI application demonstrating ConsoleKit usage
@@ -24,6 +28,7 @@ func main() {
 
 	// Create application state
 	state := config.NewState()
+	app.SetState(state) // enables ${key} substitution, e.g. "scan ${target}"
 
 	// Register commands
 	registerCommands(app, state)
@@ -50,11 +55,48 @@ func main() {
 func registerCommands(app *console.Console, state *config.State) {
 	
 	// SET command - mimics firescan's set functionality
-	app.AddCommand("set", &SetCommand{state: state}, "Set a configuration variable")
+	app.AddCommandWithArgs("set", &SetCommand{state: state}, "Set a configuration variable",
+		command.ArgSpec{Min: 2, Names: []string{"key", "value"}})
+	
+	// GET command - prints one variable; both complete the current state keys
+	app.AddCommandWithArgs("get", command.HandlerFunc(func(args []string) error {
+		value, exists := state.Get(args[0])
+		if !exists {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+		fmt.Printf("[*] %s => %v\n", args[0], value)
+		return nil
+	}), "Print a configuration variable", command.ArgSpec{Min: 1, Max: 1, Names: []string{"key"}})
+	for _, name := range []string{"set", "get"} {
+		app.Commands.SetCompletion(name, command.NewCompletionBuilder().AddStateKeys(state, "target", "threads", "proxy"))
+	}
 	
 	// SHOW command - mimics firescan's show functionality  
 	app.AddCommand("show", &ShowCommand{state: state}, "Display current configuration")
 	
+	// CONFIG command - snapshot the variables and diff against a snapshot
+	app.AddCommandWithArgs("config", &ConfigCommand{state: state}, "Save or diff configuration snapshots",
+		command.ArgSpec{Min: 2, Names: []string{"save|diff", "file"}})
+	app.Commands.SetCompletion("config", command.NewCompletionBuilder().
+		AddPosition(0, "save", "diff").
+		AddDynamicPosition(1, command.FileCompletion(".yaml")))
+	
+	// VARS command - returns a result rendered as a table, or JSON with --json
+	app.AddResultCommand("vars", command.ResultHandlerFunc(func(ctx *command.CommandContext) (*command.CommandResult, error) {
+		keys := state.Keys()
+		sort.Strings(keys)
+		rows := make([][]string, 0, len(keys))
+		for _, key := range keys {
+			value, _ := state.Get(key)
+			text := fmt.Sprintf("%v", value)
+			if state.IsSensitive(key) {
+				text = utils.MaskString(text, 4, 4)
+			}
+			rows = append(rows, []string{key, text})
+		}
+		return command.NewTableResult([]string{"Variable", "Value"}, rows), nil
+	}), "List variables (supports --json)", nil)
+	
 	// GREET command - simple example command
 	app.AddCommand("greet", command.HandlerFunc(func(args []string) error {
 		name := "World"
@@ -130,10 +172,6 @@ type SetCommand struct {
 }
 
 func (c *SetCommand) Execute(args []string) error {
-	if len(args) != 2 {
-		return fmt.Errorf("usage: set <key> <value>")
-	}
-	
 	key := args[0]
 	value := args[1]
 	
@@ -164,6 +202,39 @@ func (c *ShowCommand) Description() string {
 	return "Display current configuration"
 }
 
+// ConfigCommand saves the variables to a snapshot file and shows what
+// changed since one was saved
+type ConfigCommand struct {
+	state *config.State
+}
+
+func (c *ConfigCommand) Execute(args []string) error {
+	switch args[0] {
+	case "save":
+		if err := c.state.Save(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Snapshot saved to %s\n", args[1])
+	case "diff":
+		diff, err := c.state.Diff(args[1])
+		if err != nil {
+			return err
+		}
+		if diff == "" {
+			fmt.Printf("No changes since %s\n", args[1])
+			return nil
+		}
+		fmt.Print(diff)
+	default:
+		return fmt.Errorf("usage: config save|diff <file>")
+	}
+	return nil
+}
+
+func (c *ConfigCommand) Description() string {
+	return "Save or diff configuration snapshots"
+}
+
 // DemoCommand demonstrates progress indicators
 type DemoCommand struct{}
 
@@ -291,7 +362,41 @@ type RequestCommand struct {
 }
 
 func (c *RequestCommand) Execute(args []string) error {
-	fmt.Printf("🌐 HTTP request: %v\n", args)
+	// "set proxy http://127.0.0.1:8080" routes requests through a proxy
+	base := httpclient.DefaultConfig()
+	base.Proxy = c.state.GetStringDefault("proxy", "")
+
+	inv, err := httpclient.ParseArgs(args, base)
+	if err != nil {
+		return fmt.Errorf("%w\nusage: request [METHOD] <url> [--header \"K: V\"] [--data BODY]", err)
+	}
+	client, err := httpclient.New(inv.Config)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(context.Background(), inv.Request)
+	if err != nil {
+		return err
+	}
+	c.state.Set("last_request_url", resp.URL)
+
+	if inv.Output == "json" {
+		return output.JSON(resp.Evidence())
+	}
+
+	status := output.Green(resp.Status)
+	if !resp.OK() {
+		status = output.Red(resp.Status)
+	}
+	fmt.Printf("🌐 %s %s → %s (%s, %d bytes)\n", resp.Method, resp.URL, status,
+		resp.Duration.Round(time.Millisecond), len(resp.Body))
+	for key := range resp.Headers {
+		fmt.Printf("%s%s:%s %s\n", output.DimColor, key, output.Reset, resp.Headers.Get(key))
+	}
+	if len(resp.Body) > 0 && resp.Method != "HEAD" {
+		fmt.Printf("\n%s\n", resp.Text())
+	}
 	return nil
 }
 
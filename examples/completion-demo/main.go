@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/jacobdavidalcock/consolekit/pkg/command"
 	"github.com/jacobdavidalcock/consolekit/pkg/console"
+	"github.com/jacobdavidalcock/consolekit/pkg/httpclient"
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
 )
 
@@ -151,7 +154,21 @@ func (c *ScanCommand) Description() string {
 type RequestCommand struct{}
 
 func (c *RequestCommand) Execute(args []string) error {
-	fmt.Printf("🌐 Making request: %v\n", args)
+	inv, err := httpclient.ParseArgs(args, httpclient.DefaultConfig())
+	if err != nil {
+		return err
+	}
+	client, err := httpclient.New(inv.Config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🌐 Making request: %s %s\n", inv.Request.Method, inv.Request.URL)
+	resp, err := client.Do(context.Background(), inv.Request)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s (%d bytes in %s)\n", resp.Status, len(resp.Body), resp.Duration.Round(time.Millisecond))
 	return nil
 }
 
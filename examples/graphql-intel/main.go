@@ -19,7 +19,7 @@ type GraphQLSession struct {
 	Endpoints    []string
 	Queries      []string
 	Mutations    []string
-	Discoveries  []intel.Finding
+	Findings     intel.FindingStore
 	Authenticated bool
 	Token        string
 }
@@ -73,7 +73,7 @@ func (g *GraphQLContextProvider) GetContext() (*intel.ContextData, error) {
 		Domain:      "graphql",
 		Session:     make(map[string]interface{}),
 		History:     []intel.Action{},
-		Discoveries: g.session.Discoveries,
+		Discoveries: g.session.Findings.All(),
 		State:       g.GetCurrentState(),
 		Timestamp:   time.Now(),
 	}
@@ -84,7 +84,7 @@ func (g *GraphQLContextProvider) GetContext() (*intel.ContextData, error) {
 	context.Session["endpoints_count"] = len(g.session.Endpoints)
 	context.Session["queries_found"] = len(g.session.Queries)
 	context.Session["mutations_found"] = len(g.session.Mutations)
-	context.Session["discoveries_count"] = len(g.session.Discoveries)
+	context.Session["discoveries_count"] = g.session.Findings.Len()
 
 	return context, nil
 }
@@ -97,23 +97,13 @@ func (g *GraphQLContextProvider) GetCurrentState() map[string]interface{} {
 	state["authenticated"] = g.session.Authenticated
 	state["schema_discovered"] = len(g.session.Schema) > 0
 	state["endpoints"] = g.session.Endpoints
-	state["total_discoveries"] = len(g.session.Discoveries)
+	state["total_discoveries"] = g.session.Findings.Len()
 	
-	if len(g.session.Discoveries) > 0 {
-		var highSeverity, mediumSeverity, lowSeverity int
-		for _, finding := range g.session.Discoveries {
-			switch finding.Severity {
-			case "high", "critical":
-				highSeverity++
-			case "medium":
-				mediumSeverity++
-			case "low", "info":
-				lowSeverity++
-			}
-		}
-		state["high_severity_findings"] = highSeverity
-		state["medium_severity_findings"] = mediumSeverity
-		state["low_severity_findings"] = lowSeverity
+	if g.session.Findings.Len() > 0 {
+		counts := g.session.Findings.CountBySeverity()
+		state["high_severity_findings"] = counts["critical"] + counts["high"]
+		state["medium_severity_findings"] = counts["medium"]
+		state["low_severity_findings"] = counts["low"] + counts["info"]
 	}
 
 	return state
@@ -126,10 +116,9 @@ func main() {
 
 	// Initialize session
 	session := &GraphQLSession{
-		Endpoints:   []string{},
-		Queries:     []string{},
-		Mutations:   []string{},
-		Discoveries: []intel.Finding{},
+		Endpoints: []string{},
+		Queries:   []string{},
+		Mutations: []string{},
 	}
 
 	// Create state for configuration
@@ -194,6 +183,10 @@ func registerCommands(app *console.Console, session *GraphQLSession, state *conf
 	
 	// AUTH command - authentication management
 	app.AddCommand("auth", &AuthCommand{session: session}, "Manage authentication")
+	
+	// FINDINGS command - summarize and export findings (CSV, SARIF, ...)
+	session.Findings.ToolName = "GraphQLStrike"
+	intel.RegisterFindingsCommand(app, &session.Findings)
 }
 
 // TargetCommand handles setting the GraphQL endpoint
@@ -211,7 +204,7 @@ func (c *TargetCommand) Execute(args []string) error {
 	c.session.Target = url
 	c.state.Set("target", url)
 	
-	fmt.Printf("Target set to: %s%s%s\n", output.CyanColor, url, output.Reset)
+	fmt.Printf("Target set to: %s\n", output.Cyan(output.Hyperlink(url, url)))
 	fmt.Printf("Try: %sintrospect%s to discover the schema\n", output.YellowColor, output.Reset)
 	return nil
 }
@@ -230,7 +223,7 @@ func (c *IntrospectCommand) Execute(args []string) error {
 		return fmt.Errorf("no target set. Use 'target <url>' first")
 	}
 	
-	fmt.Printf("Running introspection on %s...\n", c.session.Target)
+	fmt.Printf("Running introspection on %s...\n", output.Hyperlink(c.session.Target, c.session.Target))
 	
 	// Simulate introspection results
 	c.session.Schema = map[string]interface{}{
@@ -256,12 +249,14 @@ func (c *IntrospectCommand) Execute(args []string) error {
 		},
 		Timestamp: time.Now(),
 	}
-	c.session.Discoveries = append(c.session.Discoveries, finding)
+	c.session.Findings.Add(finding)
 	
 	fmt.Printf("✓ Schema discovered!\n")
-	fmt.Printf("  • Types: %d\n", 3)
-	fmt.Printf("  • Queries: %d\n", 4)
-	fmt.Printf("  • Mutations: %d\n", 3)
+	tree := output.NewTree("schema")
+	tree.AddChild("types").AddChildren("User", "Post", "Comment")
+	tree.AddChild("queries").AddChildren(c.session.Queries...)
+	tree.AddChild("mutations").AddChildren(c.session.Mutations...)
+	tree.Print()
 	fmt.Printf("\nTry: %sintel analyze%s for AI insights\n", output.YellowColor, output.Reset)
 	
 	return nil
@@ -301,7 +296,10 @@ func (c *ScanCommand) Execute(args []string) error {
 		},
 		Timestamp: time.Now(),
 	}
-	c.session.Discoveries = append(c.session.Discoveries, finding)
+	if !c.session.Findings.Add(finding) {
+		fmt.Printf("No new findings (%d already recorded)\n", c.session.Findings.Len())
+		return nil
+	}
 	
 	fmt.Printf("%s! High severity vulnerability found%s\n", output.RedColor, output.Reset)
 	fmt.Printf("Try: %sintel explain authorization bypass%s\n", output.YellowColor, output.Reset)
@@ -311,21 +309,40 @@ func (c *ScanCommand) Description() string { return "Run automated GraphQL secur
 
 type ShowCommand struct{ session *GraphQLSession; state *config.State }
 func (c *ShowCommand) Execute(args []string) error {
+	if _, asJSON := output.WantsJSON(args); asJSON {
+		return output.JSON(map[string]interface{}{
+			"target":            c.session.Target,
+			"schema_discovered": len(c.session.Schema) > 0,
+			"authenticated":     c.session.Authenticated,
+			"findings":          c.session.Findings.All(),
+		})
+	}
 	fmt.Printf("\n%sSession Status%s\n", output.BoldColor, output.Reset)
 	fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 14), output.Reset)
-	fmt.Printf("Target: %s\n", c.session.Target)
+	fmt.Printf("Target: %s\n", output.Hyperlink(c.session.Target, c.session.Target))
 	fmt.Printf("Schema: %s\n", map[bool]string{true: "✅ Discovered", false: "❌ Not discovered"}[len(c.session.Schema) > 0])
-	fmt.Printf("Findings: %d\n", len(c.session.Discoveries))
+	fmt.Printf("Findings: %d\n", c.session.Findings.Len())
 	return nil
 }
 func (c *ShowCommand) Description() string { return "Display session information" }
 
 type AuthCommand struct{ session *GraphQLSession }
 func (c *AuthCommand) Execute(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: auth <token>")
+	// Prompt without echo so the token stays out of history and scrollback
+	token := ""
+	if len(args) > 0 {
+		token = args[0]
+	} else {
+		secret, err := output.ReadSecret("Token: ")
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(secret)
+	}
+	if token == "" {
+		return fmt.Errorf("usage: auth [token]")
 	}
-	c.session.Token = args[0]
+	c.session.Token = token
 	c.session.Authenticated = true
 	fmt.Printf("✓ Authentication token set\n")
 	return nil
@@ -3,11 +3,17 @@ module github.com/jacobdavidalcock/consolekit
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/chzyer/readline v1.5.1
 	github.com/ollama/ollama v0.3.14
+	github.com/pkoukk/tiktoken-go v0.1.8
+	github.com/pkoukk/tiktoken-go-loader v0.0.2
+	golang.org/x/sys v0.20.0
+	golang.org/x/term v0.20.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	golang.org/x/sys v0.0.0-20220310020820-b874c991c1a5 // indirect
-)
\ No newline at end of file
+	github.com/dlclark/regexp2 v1.10.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+)
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArgSpec declares the positional arguments a command accepts. The registry
+// checks it before calling the handler, so handlers don't need to repeat
+// length checks.
+type ArgSpec struct {
+	Min   int      // minimum number of arguments
+	Max   int      // maximum number of arguments; 0 means len(Names), -1 means unlimited
+	Names []string // positional argument names used in the generated usage
+	Usage string   // overrides the generated usage line
+}
+
+// Usager can be implemented by handlers to supply their own usage line
+type Usager interface {
+	Usage() string
+}
+
+// maxArgs returns the effective maximum, or -1 for unlimited
+func (s *ArgSpec) maxArgs() int {
+	if s.Max == 0 {
+		return len(s.Names)
+	}
+	return s.Max
+}
+
+// Validate checks the argument count against the spec
+func (s *ArgSpec) Validate(args []string) error {
+	if len(args) < s.Min {
+		return fmt.Errorf("expected at least %d argument(s), got %d", s.Min, len(args))
+	}
+	if max := s.maxArgs(); max >= 0 && len(args) > max {
+		return fmt.Errorf("expected at most %d argument(s), got %d", max, len(args))
+	}
+	return nil
+}
+
+// usageFor builds a usage line such as "set <key> <value> [scope]".
+// Required arguments are shown in angle brackets, optional ones in square
+// brackets, and "..." marks an unlimited argument list.
+func (s *ArgSpec) usageFor(name string) string {
+	if s.Usage != "" {
+		return s.Usage
+	}
+
+	parts := []string{name}
+	for i, arg := range s.Names {
+		if i < s.Min {
+			parts = append(parts, "<"+arg+">")
+		} else {
+			parts = append(parts, "["+arg+"]")
+		}
+	}
+	if s.maxArgs() < 0 {
+		if len(s.Names) > 0 {
+			parts[len(parts)-1] += "..."
+		} else {
+			parts = append(parts, "[args...]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// RegisterWithArgs adds a command whose arguments are validated against spec
+func (r *Registry) RegisterWithArgs(name string, handler Handler, description string, spec ArgSpec) {
+	r.Register(name, handler, description)
+	r.commands[name].Args = &spec
+}
+
+// SetArgs attaches an argument spec to an already registered command
+func (r *Registry) SetArgs(name string, spec ArgSpec) error {
+	cmd, exists := r.commands[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	cmd.Args = &spec
+	return nil
+}
+
+// Usage returns the usage line for a command. An explicit ArgSpec usage wins,
+// then a handler implementing Usager, then a line generated from the spec.
+func (c *Command) Usage() string {
+	if c.Args != nil && c.Args.Usage != "" {
+		return c.Args.Usage
+	}
+	if u, ok := unwrapHandler(c.Handler).(Usager); ok {
+		if usage := u.Usage(); usage != "" {
+			return usage
+		}
+	}
+	if c.Args != nil {
+		return c.Args.usageFor(c.Name)
+	}
+	return c.Name
+}
+
+// validateArgs checks args against the command's spec, returning a
+// consistent usage error on mismatch
+func (c *Command) validateArgs(args []string) error {
+	if c.Args == nil {
+		return nil
+	}
+	if err := c.Args.Validate(args); err != nil {
+		return fmt.Errorf("usage: %s (%v)", c.Usage(), err)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+type usageContextHandler struct{}
+
+func (usageContextHandler) ExecuteContext(*CommandContext) error { return nil }
+func (usageContextHandler) Description() string                 { return "Fetch a URL" }
+func (usageContextHandler) Usage() string                       { return "fetch <url> [--method M]" }
+
+type usageResultHandler struct{}
+
+func (usageResultHandler) ExecuteResult(*CommandContext) (*CommandResult, error) {
+	return &CommandResult{Text: "ok"}, nil
+}
+func (usageResultHandler) Description() string { return "List targets" }
+func (usageResultHandler) Usage() string       { return "targets <scope>" }
+
+type usageLegacyHandler struct{}
+
+func (usageLegacyHandler) Execute([]string) error { return nil }
+func (usageLegacyHandler) Description() string    { return "Probe a host" }
+func (usageLegacyHandler) Usage() string          { return "probe <host>" }
+
+func TestUsageThroughAdapters(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterContext("fetch", usageContextHandler{}, "Fetch a URL", nil)
+	r.RegisterResult("targets", usageResultHandler{}, "List targets", nil)
+	r.RegisterResult("probe", AdaptResult(usageLegacyHandler{}), "Probe a host", nil)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"fetch", "fetch <url> [--method M]"},
+		{"targets", "targets <scope>"},
+		{"probe", "probe <host>"},
+	}
+	for _, tt := range tests {
+		if err := r.SetArgs(tt.name, ArgSpec{Min: 1, Names: []string{"arg"}}); err != nil {
+			t.Fatalf("SetArgs(%s): %v", tt.name, err)
+		}
+		cmd, _ := r.GetCommand(tt.name)
+		if got := cmd.Usage(); got != tt.want {
+			t.Errorf("%s: Usage() = %q, want %q", tt.name, got, tt.want)
+		}
+
+		err := r.Execute(tt.name, nil)
+		if err == nil || !strings.Contains(err.Error(), "usage: "+tt.want) {
+			t.Errorf("%s with no arguments returned %v, want the handler's usage", tt.name, err)
+		}
+
+		help, _ := output.CaptureStdout(func() error { return r.ShowCommandHelp(tt.name) })
+		if !strings.Contains(help, tt.want) {
+			t.Errorf("help %s is missing %q:\n%s", tt.name, tt.want, help)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,169 @@
+package command
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+)
+
+// filterCompleter is the root of the completion tree. Unlike readline's own
+// PrefixCompleter it matches what has been typed case-insensitively and hands
+// the arguments typed so far to Completer commands.
+type filterCompleter struct {
+	*readline.PrefixCompleter
+}
+
+// Do completes the word under the cursor
+func (f *filterCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	return completeLine(f, line, pos)
+}
+
+// completerNode is a command whose arguments come from its Completer
+type completerNode struct {
+	*readline.PrefixCompleter
+	completer Completer
+}
+
+// complete asks the command's Completer for options at the current word
+func (n *completerNode) complete(args []string, current string) ([][]rune, int) {
+	args = append(append([]string(nil), args...), current)
+	return matchCandidates(n.completer.Complete(args, len(args)-1), current)
+}
+
+// rulesNode is a command whose flag constraints hide conflicting flags
+type rulesNode struct {
+	*readline.PrefixCompleter
+	rules *FlagRules
+}
+
+// completeLine walks the completion tree along the words before the cursor
+// and returns the children of the node reached that start with the word under
+// the cursor. Candidates are returned as readline expects: the remainder of
+// each option after the typed prefix.
+func completeLine(root readline.PrefixCompleterInterface, line []rune, pos int) ([][]rune, int) {
+	text := string(line[:pos])
+	words := strings.Fields(text)
+	current := ""
+	if len(words) > 0 && !unicode.IsSpace(line[pos-1]) {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	// Below a command with flag rules, a finished flag or value returns to
+	// the command so further flags can be completed
+	var ruled *rulesNode
+	present := make(map[string]bool)
+	node := root
+	for n := 0; n < len(words); n++ {
+		if cn, ok := node.(*completerNode); ok {
+			return cn.complete(words[n:], current)
+		}
+		if strings.HasPrefix(words[n], "-") {
+			name, _, _ := strings.Cut(strings.TrimLeft(words[n], "-"), "=")
+			present[name] = true
+		}
+		node = findChild(node, words[n], text)
+		if rn, ok := node.(*rulesNode); ok {
+			ruled = rn
+		}
+		if ruled != nil && (node == nil || len(node.GetChildren()) == 0) {
+			node = ruled
+		}
+		if node == nil {
+			return nil, 0
+		}
+	}
+	if cn, ok := node.(*completerNode); ok {
+		return cn.complete(nil, current)
+	}
+
+	// Complete the value of --flag=partial from the flag's options
+	if name, partial, ok := strings.Cut(current, "="); ok && strings.HasPrefix(name, "-") {
+		flagNode := findChild(node, name, text)
+		if flagNode == nil {
+			return nil, 0
+		}
+		return matchCandidates(childNames(flagNode, text), partial)
+	}
+
+	// Stop offering flags that conflict with ones already given
+	names := childNames(node, text)
+	if ruled != nil {
+		kept := names[:0]
+		for _, name := range names {
+			if !strings.HasPrefix(name, "-") || !ruled.rules.excluded(name, present) {
+				kept = append(kept, name)
+			}
+		}
+		names = kept
+	}
+
+	return matchCandidates(names, current)
+}
+
+// findChild returns the child of node named word, ignoring case
+func findChild(node readline.PrefixCompleterInterface, word, line string) readline.PrefixCompleterInterface {
+	for _, child := range node.GetChildren() {
+		for _, name := range nodeNames(child, line) {
+			if strings.EqualFold(name, word) {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// childNames lists the completion options below node
+func childNames(node readline.PrefixCompleterInterface, line string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, child := range node.GetChildren() {
+		for _, name := range nodeNames(child, line) {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// nodeNames returns the name of a node, or the generated names of a dynamic one
+func nodeNames(node readline.PrefixCompleterInterface, line string) []string {
+	if dynamic, ok := node.(readline.DynamicPrefixCompleterInterface); ok && dynamic.IsDynamic() {
+		var names []string
+		for _, name := range dynamic.GetDynamicNames([]rune(line)) {
+			names = append(names, strings.TrimSpace(string(name)))
+		}
+		return names
+	}
+	return []string{strings.TrimSpace(string(node.GetName()))}
+}
+
+// matchCandidates filters options by prefix and converts them to readline
+// candidates. Each candidate ends with a space so the next word can follow.
+func matchCandidates(options []string, prefix string) ([][]rune, int) {
+	offset := len([]rune(prefix))
+	var candidates [][]rune
+	for _, option := range filterByPrefix(options, prefix) {
+		runes := []rune(option)
+		if len(runes) < offset {
+			continue
+		}
+		candidates = append(candidates, append(runes[offset:], ' '))
+	}
+	return candidates, offset
+}
+
+// filterByPrefix keeps the options starting with prefix, ignoring case
+func filterByPrefix(options []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	var filtered []string
+	for _, option := range options {
+		if strings.HasPrefix(strings.ToLower(option), prefix) {
+			filtered = append(filtered, option)
+		}
+	}
+	return filtered
+}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+// completeAt completes line with the cursor at pos and returns the full
+// options offered in order, with the typed prefix put back
+func completeAt(t *testing.T, r *Registry, line string, pos int) []string {
+	t.Helper()
+	runes := []rune(line)
+	candidates, offset := r.BuildCompleter().Do(runes, len([]rune(line[:pos])))
+
+	typed := string(runes[len([]rune(line[:pos]))-offset : len([]rune(line[:pos]))])
+	var options []string
+	for _, candidate := range candidates {
+		options = append(options, typed+string(candidate))
+	}
+	return options
+}
+
+func newCompletionRegistry() *Registry {
+	r := NewRegistry()
+	r.RegisterFunc("intel", func([]string) error { return nil }, "AI assistance")
+	r.RegisterFunc("scan", func([]string) error { return nil }, "Scan a target")
+	return r
+}
+
+func TestFilterByPrefix(t *testing.T) {
+	options := []string{"analyze", "Analysis", "suggest", "status"}
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"ana", []string{"analyze", "Analysis"}},
+		{"ANA", []string{"analyze", "Analysis"}},
+		{"st", []string{"status"}},
+		{"", options},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		if got := filterByPrefix(options, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterByPrefix(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionFiltersCaseInsensitively(t *testing.T) {
+	r := newCompletionRegistry()
+	for _, line := range []string{"intel ana", "intel ANA", "INTEL Ana"} {
+		got := completeAt(t, r, line, len(line))
+		if len(got) != 1 || got[0] != line[len(line)-3:]+"lyze " {
+			t.Errorf("%q completed to %q, want only analyze", line, got)
+		}
+	}
+}
+
+func TestCompletionEmptyPrefix(t *testing.T) {
+	r := newCompletionRegistry()
+
+	top := completeAt(t, r, "", 0)
+	for _, want := range []string{"help ", "intel ", "scan "} {
+		if !containsString(top, want) {
+			t.Errorf("empty line offered %q, missing %q", top, want)
+		}
+	}
+
+	sub := completeAt(t, r, "intel ", len("intel "))
+	for _, want := range []string{"analyze ", "suggest ", "explain ", "status "} {
+		if !containsString(sub, want) {
+			t.Errorf("'intel ' offered %q, missing %q", sub, want)
+		}
+	}
+	if containsString(sub, "scan ") {
+		t.Errorf("'intel ' offered top-level command scan: %q", sub)
+	}
+}
+
+func TestCompletionCursorMidLine(t *testing.T) {
+	r := newCompletionRegistry()
+
+	line := "intel ana --json"
+	got := completeAt(t, r, line, len("intel ana"))
+	if !reflect.DeepEqual(got, []string{"analyze "}) {
+		t.Errorf("cursor after 'intel ana' completed to %q, want [analyze]", got)
+	}
+
+	got = completeAt(t, r, line, len("int"))
+	if !reflect.DeepEqual(got, []string{"intel "}) {
+		t.Errorf("cursor after 'int' completed to %q, want [intel]", got)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
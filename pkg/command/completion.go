@@ -1,11 +1,16 @@
 package command
 
 import (
+	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/chzyer/readline"
+	"github.com/jacobdavidalcock/consolekit/pkg/config"
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
 )
 
 // Completer interface allows commands to provide custom tab completion
@@ -15,10 +20,11 @@ type Completer interface {
 
 // ArgumentCompletion defines completion options for command arguments
 type ArgumentCompletion struct {
-	Position int                    // Argument position (0-based)
-	Options  []string              // Static completion options
-	Dynamic  func() []string       // Dynamic option generator
-	Flags    map[string][]string   // Flag completions (--flag -> options)
+	Position     int                        // Argument position (0-based)
+	Options      []string                   // Static completion options
+	Dynamic      func() []string            // Dynamic option generator, called on Tab
+	Flags        map[string][]string        // Flag completions (--flag -> options)
+	DynamicFlags map[string]func() []string // Flag completions generated on Tab
 }
 
 // CompletionContext provides context for completion
@@ -41,15 +47,92 @@ func StaticCompletion(options ...string) func() []string {
 	}
 }
 
-// FileCompletion provides file path completion
+// StateKeyCompletion completes the keys currently set in state, plus known
+// keys that haven't been set yet. It reads the state on every Tab.
+func StateKeyCompletion(state *config.State, known ...string) func() []string {
+	return func() []string {
+		var keys []string
+		if state != nil {
+			keys = state.Keys()
+		}
+		return mergeSorted(keys, known)
+	}
+}
+
+// ConfigKeyCompletion completes the keys of a configuration, nested keys in
+// dotted form
+func ConfigKeyCompletion(cfg *config.Config) func() []string {
+	return func() []string {
+		if cfg == nil {
+			return nil
+		}
+		return cfg.Keys()
+	}
+}
+
+// mergeSorted returns the distinct strings of a and b in sorted order
+func mergeSorted(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var merged []string
+	for _, list := range [][]string{a, b} {
+		for _, item := range list {
+			if !seen[item] {
+				seen[item] = true
+				merged = append(merged, item)
+			}
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// FileCompletion completes files in the current directory, limited to the
+// given extensions when any are passed. Subdirectories are offered with a
+// trailing separator.
 func FileCompletion(extensions ...string) func() []string {
 	return func() []string {
-		// Simple file completion - in a real implementation this would
-		// integrate with readline's file completion
-		return []string{"file1.txt", "file2.json", "config.yaml"}
+		entries, err := utils.Glob("*")
+		if err != nil {
+			return nil
+		}
+
+		var options []string
+		for _, entry := range entries {
+			if info, err := os.Stat(entry); err == nil && info.IsDir() {
+				options = append(options, entry+string(filepath.Separator))
+				continue
+			}
+			if len(extensions) == 0 || hasExtension(entry, extensions) {
+				options = append(options, entry)
+			}
+		}
+		return options
 	}
 }
 
+// GlobCompletion completes the files matching a pattern such as
+// "wordlists/*.txt" or "~/lists/**/*.txt" (see utils.Glob)
+func GlobCompletion(pattern string) func() []string {
+	return func() []string {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil
+		}
+		return matches
+	}
+}
+
+// hasExtension reports whether path ends in one of extensions, ignoring case
+func hasExtension(path string, extensions []string) bool {
+	ext := filepath.Ext(path)
+	for _, want := range extensions {
+		if strings.EqualFold(ext, "."+strings.TrimPrefix(want, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
 // NumberCompletion provides number range completion
 func NumberCompletion(min, max, step int) func() []string {
 	return func() []string {
@@ -70,15 +153,20 @@ func BoolCompletion() func() []string {
 
 // CompletionBuilder helps build completion configurations
 type CompletionBuilder struct {
-	completions map[int]ArgumentCompletion
-	flags       map[string][]string
+	completions  map[int]ArgumentCompletion
+	flags        map[string][]string
+	dynamicFlags map[string]func() []string
+	aliases      map[string]string
+	rules        *FlagRules
 }
 
 // NewCompletionBuilder creates a new completion builder
 func NewCompletionBuilder() *CompletionBuilder {
 	return &CompletionBuilder{
-		completions: make(map[int]ArgumentCompletion),
-		flags:       make(map[string][]string),
+		completions:  make(map[int]ArgumentCompletion),
+		flags:        make(map[string][]string),
+		dynamicFlags: make(map[string]func() []string),
+		aliases:      make(map[string]string),
 	}
 }
 
@@ -91,29 +179,87 @@ func (cb *CompletionBuilder) AddPosition(pos int, options ...string) *Completion
 	return cb
 }
 
-// AddDynamicPosition adds dynamic completion for a specific argument position
+// AddDynamicPosition adds dynamic completion for a specific argument position.
+// The generator runs on each Tab, bounded by GeneratorTimeout.
 func (cb *CompletionBuilder) AddDynamicPosition(pos int, generator func() []string) *CompletionBuilder {
 	cb.completions[pos] = ArgumentCompletion{
 		Position: pos,
-		Dynamic:  generator,
+		Dynamic:  CachedGenerator(generator, 0),
 	}
 	return cb
 }
 
+// AddStateKeys completes the first argument with the live keys of state
+// and any known keys, for commands such as set, get and unset
+func (cb *CompletionBuilder) AddStateKeys(state *config.State, known ...string) *CompletionBuilder {
+	return cb.AddDynamicPosition(0, StateKeyCompletion(state, known...))
+}
+
 // AddFlag adds completion for a flag
 func (cb *CompletionBuilder) AddFlag(flag string, options ...string) *CompletionBuilder {
 	cb.flags[flag] = options
 	return cb
 }
 
-// AddDynamicFlag adds dynamic completion for a flag
+// AddDynamicFlag adds dynamic completion for a flag. The generator runs on
+// each Tab, bounded by GeneratorTimeout.
 func (cb *CompletionBuilder) AddDynamicFlag(flag string, generator func() []string) *CompletionBuilder {
-	cb.flags[flag] = generator()
+	return cb.AddCachedDynamicFlag(flag, 0, generator)
+}
+
+// AddCachedDynamicFlag adds dynamic completion for a flag whose options are
+// reused for ttl, for generators backed by slow or remote data sources
+func (cb *CompletionBuilder) AddCachedDynamicFlag(flag string, ttl time.Duration, generator func() []string) *CompletionBuilder {
+	cb.dynamicFlags[flag] = CachedGenerator(generator, ttl)
 	return cb
 }
 
+// AddFlagAlias makes a short flag complete like its long form, e.g.
+// AddFlagAlias("-t", "--threads")
+func (cb *CompletionBuilder) AddFlagAlias(short, long string) *CompletionBuilder {
+	cb.aliases[short] = long
+	return cb
+}
+
+// Exclusive declares flags that can't be used together, e.g.
+// Exclusive("--quiet", "--verbose")
+func (cb *CompletionBuilder) Exclusive(flags ...string) *CompletionBuilder {
+	if cb.rules == nil {
+		cb.rules = &FlagRules{}
+	}
+	cb.rules.Exclusive = append(cb.rules.Exclusive, flags)
+	return cb
+}
+
+// Requires declares that flag may only be used together with required, e.g.
+// Requires("--wordlist", "--target")
+func (cb *CompletionBuilder) Requires(flag string, required ...string) *CompletionBuilder {
+	if cb.rules == nil {
+		cb.rules = &FlagRules{}
+	}
+	if cb.rules.Requires == nil {
+		cb.rules.Requires = make(map[string][]string)
+	}
+	cb.rules.Requires[flag] = append(cb.rules.Requires[flag], required...)
+	return cb
+}
+
+// Rules returns the flag constraints declared on the builder, or nil
+func (cb *CompletionBuilder) Rules() *FlagRules {
+	return cb.rules
+}
+
 // Build creates the final completion configuration
 func (cb *CompletionBuilder) Build() map[int]ArgumentCompletion {
+	for short, long := range cb.aliases {
+		if options, ok := cb.flags[long]; ok {
+			cb.flags[short] = options
+		}
+		if generator, ok := cb.dynamicFlags[long]; ok {
+			cb.dynamicFlags[short] = generator
+		}
+	}
+
 	// Apply flags to all completions
 	for pos, completion := range cb.completions {
 		if completion.Flags == nil {
@@ -122,6 +268,12 @@ func (cb *CompletionBuilder) Build() map[int]ArgumentCompletion {
 		for flag, options := range cb.flags {
 			completion.Flags[flag] = options
 		}
+		if len(cb.dynamicFlags) > 0 && completion.DynamicFlags == nil {
+			completion.DynamicFlags = make(map[string]func() []string)
+		}
+		for flag, generator := range cb.dynamicFlags {
+			completion.DynamicFlags[flag] = generator
+		}
 		cb.completions[pos] = completion
 	}
 	return cb.completions
@@ -204,13 +356,7 @@ func (ch *CompletionHelper) ExtractFlagName(arg string) string {
 
 // FilterByPrefix filters options by a given prefix
 func (ch *CompletionHelper) FilterByPrefix(options []string, prefix string) []string {
-	var filtered []string
-	for _, option := range options {
-		if strings.HasPrefix(strings.ToLower(option), strings.ToLower(prefix)) {
-			filtered = append(filtered, option)
-		}
-	}
-	return filtered
+	return filterByPrefix(options, prefix)
 }
 
 // BuildPrefixCompleter creates a readline PrefixCompleter from argument completions
@@ -226,7 +372,7 @@ func (ch *CompletionHelper) BuildPrefixCompleter(commandName string, completions
 		}
 		
 		// Add flag completions
-		for flag := range completion.Flags {
+		for _, flag := range sortedKeys(completion.Flags) {
 			items = append(items, readline.PcItem(flag))
 		}
 	}
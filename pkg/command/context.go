@@ -0,0 +1,257 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/config"
+)
+
+// CommandContext carries everything a command needs for one execution
+type CommandContext struct {
+	Name  string        // command name as registered
+	Args  []string      // positional arguments with flags removed
+	Flags *flag.FlagSet // parsed flags, nil if the command declares none
+	State *config.State // shared state set with Registry.SetState, may be nil
+	History []string    // recent command lines, oldest first, ending with this one; nil without a history source
+	Stdin io.Reader     // output of the command piped into this one, nil when not piped
+
+	set  map[string]bool // flags given on this command line
+	json bool            // --json was given to a result command
+}
+
+// Arg returns the positional argument at index i, or "" if it is missing
+func (c *CommandContext) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+	return c.Args[i]
+}
+
+// Flag returns the string value of a parsed flag, or "" if it is not defined
+func (c *CommandContext) Flag(name string) string {
+	if c.Flags == nil {
+		return ""
+	}
+	f := c.Flags.Lookup(name)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// HasFlag reports whether a flag was given on the command line
+func (c *CommandContext) HasFlag(name string) bool {
+	return c.set[name]
+}
+
+// ContextHandler is a handler that receives parsed flags and state
+type ContextHandler interface {
+	ExecuteContext(ctx *CommandContext) error
+	Description() string
+}
+
+// ContextHandlerFunc allows using functions as context handlers
+type ContextHandlerFunc func(ctx *CommandContext) error
+
+func (f ContextHandlerFunc) ExecuteContext(ctx *CommandContext) error {
+	return f(ctx)
+}
+
+func (f ContextHandlerFunc) Description() string {
+	return "Custom command"
+}
+
+// contextAdapter lets a ContextHandler be stored and called as a Handler
+type contextAdapter struct {
+	ContextHandler
+}
+
+// Execute runs the handler with positional arguments only
+func (a contextAdapter) Execute(args []string) error {
+	return a.ExecuteContext(&CommandContext{Args: args})
+}
+
+func (a contextAdapter) unwrap() interface{} { return a.ContextHandler }
+
+// handlerAdapter lets a legacy Handler be used where a ContextHandler is expected
+type handlerAdapter struct {
+	Handler
+}
+
+// ExecuteContext runs the legacy handler with the positional arguments
+func (a handlerAdapter) ExecuteContext(ctx *CommandContext) error {
+	return a.Execute(ctx.Args)
+}
+
+func (a handlerAdapter) unwrap() interface{} { return a.Handler }
+
+// adapter is implemented by the wrappers above, which only embed the
+// interface they adapt, so optional interfaces of the wrapped handler such
+// as Usager are not promoted
+type adapter interface {
+	unwrap() interface{}
+}
+
+// unwrapHandler returns the handler the user registered, looking through
+// any adapters around it
+func unwrapHandler(h interface{}) interface{} {
+	for {
+		a, ok := h.(adapter)
+		if !ok {
+			return h
+		}
+		h = a.unwrap()
+	}
+}
+
+// AdaptHandler wraps a legacy Handler as a ContextHandler
+func AdaptHandler(h Handler) ContextHandler {
+	if ch, ok := h.(ContextHandler); ok {
+		return ch
+	}
+	return handlerAdapter{h}
+}
+
+// RegisterContext adds a command whose flags are parsed by the registry
+// before the handler runs. flags may be nil for commands without flags.
+func (r *Registry) RegisterContext(name string, handler ContextHandler, description string, flags *flag.FlagSet) {
+	r.Register(name, contextAdapter{handler}, description)
+	r.commands[name].Flags = flags
+}
+
+// SetFlags attaches a flag set to an already registered command
+func (r *Registry) SetFlags(name string, flags *flag.FlagSet) error {
+	cmd, exists := r.commands[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	cmd.Flags = flags
+	return nil
+}
+
+// SetState sets the state passed to context handlers
+func (r *Registry) SetState(state *config.State) {
+	r.state = state
+}
+
+// RecentHistory is how many command lines CommandContext.History holds
+const RecentHistory = 20
+
+// SetHistory sets the source of the command lines passed to context
+// handlers. The console sets it to Console.History.
+func (r *Registry) SetHistory(history func() []string) {
+	r.history = history
+}
+
+// recentHistory returns the last RecentHistory lines from the history source
+func (r *Registry) recentHistory() []string {
+	if r.history == nil {
+		return nil
+	}
+	lines := r.history()
+	if len(lines) > RecentHistory {
+		lines = lines[len(lines)-RecentHistory:]
+	}
+	return lines
+}
+
+// newContext parses flags for cmd and builds its execution context. It
+// returns flag.ErrHelp after printing usage when -h or --help is given.
+func (r *Registry) newContext(cmd *Command, args []string) (*CommandContext, error) {
+	ctx := &CommandContext{
+		Name:  cmd.Name,
+		Args:  args,
+		Flags: cmd.Flags,
+		State: r.state,
+		History: r.recentHistory(),
+	}
+	if cmd.Flags == nil {
+		ctx.Args = NormalizeFlagArgs(args, cmd.FlagAliases)
+		return ctx, nil
+	}
+
+	positionals, set, err := parseInterspersed(cmd.Flags, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			ShowUsage(cmd.Name, cmd.Flags)
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %v", cmd.Name, err)
+	}
+	ctx.Args = positionals
+	ctx.set = set
+	return ctx, nil
+}
+
+// trackedValue records which flags were set during a parse. FlagSet.Visit
+// can't be used for this because a reused flag set remembers flags from
+// earlier executions.
+type trackedValue struct {
+	flag.Value
+	name string
+	set  map[string]bool
+}
+
+func (v trackedValue) Set(s string) error {
+	v.set[v.name] = true
+	return v.Value.Set(s)
+}
+
+// IsBoolFlag preserves "-v" style boolean flags for the wrapped value
+func (v trackedValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// parseInterspersed parses flags that may appear before, after or between
+// positional arguments. Everything after a bare "--" is positional. Flag
+// values are reset to their defaults first because flag sets are reused
+// across executions.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, map[string]bool, error) {
+	set := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		f.Value.Set(f.DefValue)
+		name := f.Name
+		if alias, ok := f.Value.(aliasValue); ok {
+			name = alias.target
+		}
+		f.Value = trackedValue{Value: f.Value, name: name, set: set}
+	})
+	defer fs.VisitAll(func(f *flag.Flag) {
+		if tv, ok := f.Value.(trackedValue); ok {
+			f.Value = tv.Value
+		}
+	})
+
+	// The registry reports parse errors itself, so keep the flag package quiet
+	out := fs.Output()
+	fs.SetOutput(io.Discard)
+	defer fs.SetOutput(out)
+
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			rest = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	var positionals []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, nil, err
+		}
+		remaining := fs.Args()
+		if len(remaining) == 0 {
+			break
+		}
+		positionals = append(positionals, remaining[0])
+		args = remaining[1:]
+	}
+
+	return append(positionals, rest...), set, nil
+}
\ No newline at end of file
@@ -0,0 +1,185 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// aliasValue is a short flag sharing the value of its long form
+type aliasValue struct {
+	flag.Value
+	target string
+}
+
+// IsBoolFlag lets a short alias of a boolean flag be used without a value
+func (v aliasValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// AliasFlag defines short as another name for the long flag in fs, so -t
+// sets the same value as --threads. Names may be given with or without
+// leading dashes.
+func AliasFlag(fs *flag.FlagSet, short, long string) error {
+	short, long = strings.TrimLeft(short, "-"), strings.TrimLeft(long, "-")
+	target := fs.Lookup(long)
+	if target == nil {
+		return fmt.Errorf("flag not defined: --%s", long)
+	}
+	if fs.Lookup(short) != nil {
+		return fmt.Errorf("flag already defined: -%s", short)
+	}
+	fs.Var(aliasValue{Value: target.Value, target: long}, short, "shorthand for --"+long)
+	return nil
+}
+
+// AliasFlag makes short an alias of a command's long flag. Commands with a
+// flag set get the alias defined on it; other commands see the long name in
+// their arguments.
+func (r *Registry) AliasFlag(command, short, long string) error {
+	cmd, exists := r.commands[strings.ToLower(command)]
+	if !exists {
+		return fmt.Errorf("command not found: %s", command)
+	}
+
+	if cmd.Flags != nil {
+		return AliasFlag(cmd.Flags, short, long)
+	}
+	if cmd.FlagAliases == nil {
+		cmd.FlagAliases = make(map[string]string)
+	}
+	cmd.FlagAliases[strings.TrimLeft(short, "-")] = strings.TrimLeft(long, "-")
+	return nil
+}
+
+// NormalizeFlagArgs splits "--flag=value" into "--flag" "value" and rewrites
+// aliased short flags (keys without dashes) to their long form. Arguments
+// after a bare "--" are left untouched.
+func NormalizeFlagArgs(args []string, aliases map[string]string) []string {
+	normalized := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(normalized, args[i:]...)
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			normalized = append(normalized, arg)
+			continue
+		}
+
+		trimmed := strings.TrimLeft(arg, "-")
+		dashes := arg[:len(arg)-len(trimmed)]
+		name, value, hasValue := strings.Cut(trimmed, "=")
+		if long, ok := aliases[name]; ok {
+			name, dashes = long, "--"
+		}
+		normalized = append(normalized, dashes+name)
+		if hasValue {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
+
+// FlagRules declares which flags conflict with or depend on each other.
+// Flag names may be written with or without leading dashes.
+type FlagRules struct {
+	Exclusive [][]string          // groups of flags that can't be combined
+	Requires  map[string][]string // flag -> flags that must also be given
+}
+
+// Check returns an error describing the first violated rule. present holds
+// the names, without dashes, of the flags given on the command line.
+func (fr *FlagRules) Check(present map[string]bool) error {
+	if fr == nil {
+		return nil
+	}
+
+	for _, group := range fr.Exclusive {
+		var used []string
+		for _, name := range group {
+			if present[strings.TrimLeft(name, "-")] {
+				used = append(used, "--"+strings.TrimLeft(name, "-"))
+			}
+		}
+		if len(used) > 1 {
+			return fmt.Errorf("%s cannot be used together", strings.Join(used, " and "))
+		}
+	}
+
+	names := make([]string, 0, len(fr.Requires))
+	for name := range fr.Requires {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		required := fr.Requires[name]
+		if !present[strings.TrimLeft(name, "-")] {
+			continue
+		}
+		for _, req := range required {
+			if !present[strings.TrimLeft(req, "-")] {
+				return fmt.Errorf("--%s requires --%s", strings.TrimLeft(name, "-"), strings.TrimLeft(req, "-"))
+			}
+		}
+	}
+	return nil
+}
+
+// excluded reports whether flag conflicts with one of the present flags
+func (fr *FlagRules) excluded(flag string, present map[string]bool) bool {
+	if fr == nil {
+		return false
+	}
+	flag = strings.TrimLeft(flag, "-")
+	for _, group := range fr.Exclusive {
+		inGroup := false
+		for _, name := range group {
+			if strings.TrimLeft(name, "-") == flag {
+				inGroup = true
+			}
+		}
+		if !inGroup {
+			continue
+		}
+		for _, name := range group {
+			if name = strings.TrimLeft(name, "-"); name != flag && present[name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetFlagRules attaches flag constraints to a command. They are checked
+// before the handler runs.
+func (r *Registry) SetFlagRules(name string, rules *FlagRules) error {
+	cmd, exists := r.commands[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("command not found: %s", name)
+	}
+	cmd.FlagRules = rules
+	return nil
+}
+
+// presentFlags returns the names of the flags given to a command. Commands
+// with a flag set report what the parser saw; otherwise arguments that look
+// like flags are collected up to a bare "--".
+func presentFlags(ctx *CommandContext) map[string]bool {
+	if ctx.set != nil {
+		return ctx.set
+	}
+	present := make(map[string]bool)
+	for _, arg := range ctx.Args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			present[name] = true
+		}
+	}
+	return present
+}
\ No newline at end of file
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPresetsDeclareNoFlagRules(t *testing.T) {
+	quick := &Quick{}
+	r := NewRegistry()
+	noop := HandlerFunc(func([]string) error { return nil })
+	r.RegisterWithBuilder("scan", noop, "Run a security test", quick.SecurityTest())
+	r.RegisterWithBuilder("discover", noop, "Discover content", quick.Discovery())
+
+	for _, line := range []string{
+		"scan sql --verbose true --quiet false",
+		"discover directories --wordlist common.txt",
+	} {
+		args := strings.Fields(line)
+		if err := r.Execute(args[0], args[1:]); err != nil {
+			t.Errorf("%q failed: %v", line, err)
+		}
+	}
+}
+
+func TestFlagRulesOptIn(t *testing.T) {
+	quick := &Quick{}
+	r := NewRegistry()
+	noop := HandlerFunc(func([]string) error { return nil })
+	r.RegisterWithBuilder("scan", noop, "Run a security test",
+		quick.Discovery().Exclusive("--quiet", "--verbose").Requires("--wordlist", "--target"))
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"scan --quiet --verbose", "--quiet and --verbose cannot be used together"},
+		{"scan directories --wordlist common.txt", "--wordlist requires --target"},
+		{"scan directories --wordlist common.txt --target http://example.com", ""},
+	}
+	for _, tt := range tests {
+		args := strings.Fields(tt.line)
+		err := r.Execute(args[0], args[1:])
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%q failed: %v", tt.line, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%q returned %v, want %q", tt.line, err, tt.want)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"sync"
+	"time"
+)
+
+// GeneratorTimeout bounds how long Tab waits for a completion generator.
+// Slower generators keep running in the background and their result is
+// used on the next Tab.
+var GeneratorTimeout = 250 * time.Millisecond
+
+// cachedGenerator guards a completion generator with a timeout and caches
+// its result
+type cachedGenerator struct {
+	gen      func() []string
+	ttl      time.Duration
+	mu       sync.Mutex
+	result   []string
+	fetched  time.Time
+	inFlight chan struct{} // closed when the running call finishes
+}
+
+// CachedGenerator wraps gen so completion never blocks on it for longer than
+// GeneratorTimeout. Results are reused for ttl (0 re-runs gen on every call);
+// when gen is slow the last result, or nothing, is returned instead. Only one
+// call to gen runs at a time.
+func CachedGenerator(gen func() []string, ttl time.Duration) func() []string {
+	if gen == nil {
+		return nil
+	}
+	cg := &cachedGenerator{gen: gen, ttl: ttl}
+	return cg.get
+}
+
+// get returns fresh cached options or runs the generator
+func (cg *cachedGenerator) get() []string {
+	cg.mu.Lock()
+	if !cg.fetched.IsZero() && cg.ttl > 0 && time.Since(cg.fetched) < cg.ttl {
+		result := cg.result
+		cg.mu.Unlock()
+		return result
+	}
+
+	done := cg.inFlight
+	if done == nil {
+		done = make(chan struct{})
+		cg.inFlight = done
+		go cg.run(done)
+	}
+	cg.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(GeneratorTimeout):
+	}
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	return cg.result
+}
+
+// run calls the generator and stores its result. A panicking generator
+// leaves the previous result in place.
+func (cg *cachedGenerator) run(done chan struct{}) {
+	defer func() {
+		recover()
+		cg.mu.Lock()
+		cg.inFlight = nil
+		cg.mu.Unlock()
+		close(done)
+	}()
+
+	result := cg.gen()
+
+	cg.mu.Lock()
+	cg.result = result
+	cg.fetched = time.Now()
+	cg.mu.Unlock()
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ShowCommandHelp prints detailed help for one command: its description,
+// usage line, argument completions and flags. Commands registered without
+// any of these get a short note instead.
+func (r *Registry) ShowCommandHelp(name string) error {
+	cmd, exists := r.GetCommand(name)
+	if !exists {
+		return fmt.Errorf("unknown command: %s. Type 'help' for a list of commands", name)
+	}
+
+	fmt.Printf("\n%s - %s\n", cmd.Name, cmd.Description)
+	if cmd.Deprecated != "" {
+		fmt.Printf("Deprecated: %s\n", cmd.Deprecated)
+	}
+	if cmd.Dangerous != "" {
+		fmt.Printf("Dangerous: asks you to type '%s' before running; --force skips the prompt\n", cmd.Dangerous)
+	}
+
+	detailed := false
+	if usage := cmd.Usage(); usage != cmd.Name {
+		fmt.Printf("\nUsage: %s\n", usage)
+		detailed = true
+	}
+	if cmd.showArgumentHelp() {
+		detailed = true
+	}
+	if cmd.showFlagHelp() {
+		detailed = true
+	}
+
+	if !detailed {
+		fmt.Printf("\nNo detailed help available for %s.\n", cmd.Name)
+	}
+	return nil
+}
+
+// showArgumentHelp lists the completion options of each argument position
+func (c *Command) showArgumentHelp() bool {
+	positions := make([]int, 0, len(c.Completions))
+	for pos, completion := range c.Completions {
+		if len(completion.Options) > 0 || completion.Dynamic != nil {
+			positions = append(positions, pos)
+		}
+	}
+	if len(positions) == 0 {
+		return false
+	}
+	sort.Ints(positions)
+
+	fmt.Println("\nArguments:")
+	for _, pos := range positions {
+		completion := c.Completions[pos]
+		name := fmt.Sprintf("arg %d", pos+1)
+		if c.Args != nil && pos < len(c.Args.Names) {
+			name = c.Args.Names[pos]
+		}
+
+		values := strings.Join(completion.Options, ", ")
+		if completion.Dynamic != nil {
+			if values != "" {
+				values += ", "
+			}
+			values += "(values on Tab)"
+		}
+		fmt.Printf("  %-20s %s\n", name, values)
+	}
+	return true
+}
+
+// showFlagHelp prints the command's flag set, completed flag values, short
+// aliases and flag rules
+func (c *Command) showFlagHelp() bool {
+	completed := make(map[string]string)
+	for _, completion := range c.Completions {
+		for flag, options := range completion.Flags {
+			completed[flag] = strings.Join(options, ", ")
+		}
+		for flag := range completion.DynamicFlags {
+			completed[flag] = "(values on Tab)"
+		}
+	}
+	if c.Flags == nil && len(completed) == 0 && len(c.FlagAliases) == 0 && c.FlagRules == nil {
+		return false
+	}
+
+	fmt.Println("\nFlags:")
+	if c.Flags != nil {
+		out := c.Flags.Output()
+		c.Flags.SetOutput(os.Stdout)
+		c.Flags.PrintDefaults()
+		c.Flags.SetOutput(out)
+	}
+	for _, flag := range sortedKeys(completed) {
+		fmt.Printf("  %-20s %s\n", flag, completed[flag])
+	}
+	for _, short := range sortedKeys(c.FlagAliases) {
+		fmt.Printf("  -%-19s same as --%s\n", short, c.FlagAliases[short])
+	}
+
+	if rules := c.FlagRules; rules != nil {
+		for _, group := range rules.Exclusive {
+			fmt.Printf("  Only one of: %s\n", strings.Join(dashedFlags(group), ", "))
+		}
+		for _, flag := range sortedKeys(rules.Requires) {
+			fmt.Printf("  --%s requires: %s\n", strings.TrimLeft(flag, "-"), strings.Join(dashedFlags(rules.Requires[flag]), ", "))
+		}
+	}
+	return true
+}
+
+// dashedFlags writes flag names, given with or without dashes, as --name
+func dashedFlags(names []string) []string {
+	dashed := make([]string, len(names))
+	for i, name := range names {
+		dashed[i] = "--" + strings.TrimLeft(name, "-")
+	}
+	return dashed
+}
\ No newline at end of file
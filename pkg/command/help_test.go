@@ -0,0 +1,35 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+func TestShowCommandHelpFlagRules(t *testing.T) {
+	r := NewRegistry()
+	builder := NewCompletionBuilder().
+		Exclusive("--quiet", "verbose").
+		Requires("--wordlist", "--target", "mode")
+	r.RegisterWithBuilder("scan", HandlerFunc(func([]string) error { return nil }), "Scan a target", builder)
+
+	help, err := output.CaptureStdout(func() error {
+		return r.ShowCommandHelp("scan")
+	})
+	if err != nil {
+		t.Fatalf("ShowCommandHelp: %v", err)
+	}
+
+	for _, want := range []string{
+		"Only one of: --quiet, --verbose",
+		"--wordlist requires: --target, --mode",
+	} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help is missing %q:\n%s", want, help)
+		}
+	}
+	if strings.Contains(help, "---") {
+		t.Errorf("help has flags with extra dashes:\n%s", help)
+	}
+}
\ No newline at end of file
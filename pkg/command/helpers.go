@@ -37,8 +37,11 @@ func (q *Quick) HTTPClient() *CompletionBuilder {
 		AddPosition(0, defaults.GetHTTPMethods()...).
 		AddPosition(1, "http://example.com", "https://api.example.com").
 		AddFlag("--method", defaults.GetHTTPMethods()...).
-		AddFlag("--header", "Content-Type: application/json", "Authorization: Bearer token").
+		AddFlag("--header", `"Content-Type: application/json"`, `"Authorization: Bearer token"`).
+		AddFlag("--data", `{}`).
 		AddFlag("--timeout", "30", "60", "120").
+		AddFlag("--proxy", "http://127.0.0.1:8080", "socks5://127.0.0.1:9050").
+		AddFlag("--retries", "0", "1", "3").
 		AddFlag("--follow-redirects", BoolCompletion()()...).
 		AddFlag("--verify-ssl", BoolCompletion()()...).
 		AddFlag("--output", defaults.GetOutputFormats()...)
@@ -3,6 +3,7 @@ package command
 import (
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -75,5 +76,8 @@ func ValidateRequired(flagSet *flag.FlagSet, required []string) error {
 func ShowUsage(commandName string, flagSet *flag.FlagSet) {
 	fmt.Printf("Usage: %s [options]\n", commandName)
 	fmt.Println("Options:")
+	out := flagSet.Output()
+	flagSet.SetOutput(os.Stdout)
 	flagSet.PrintDefaults()
+	flagSet.SetOutput(out)
 }
\ No newline at end of file
@@ -1,10 +1,16 @@
 package command
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/chzyer/readline"
+	"github.com/jacobdavidalcock/consolekit/pkg/config"
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
 )
 
 // Handler defines the interface for command handlers
@@ -31,11 +37,21 @@ type Command struct {
 	Description string
 	Subcommands map[string]*Command
 	Completions map[int]ArgumentCompletion // Argument completion configuration
+	Hidden      bool                       // executable but omitted from help and completion
+	Deprecated  string                     // warning shown the first time the command runs
+	Dangerous   string                     // token the user must type before each run, see MarkDangerous
+	Args        *ArgSpec                   // optional argument validation
+	Flags       *flag.FlagSet              // optional flags parsed before the handler runs
+	FlagAliases map[string]string          // short -> long flag names for commands without Flags
+	FlagRules   *FlagRules                 // optional exclusive/required flag constraints
+	warned      bool
 }
 
 // Registry manages command registration and execution
 type Registry struct {
 	commands map[string]*Command
+	state    *config.State
+	history  func() []string // source of CommandContext.History
 }
 
 // NewRegistry creates a new command registry
@@ -56,6 +72,44 @@ func (r *Registry) Register(name string, handler Handler, description string) {
 	}
 }
 
+// RegisterHidden adds a command that can be executed but is omitted from
+// help and completion
+func (r *Registry) RegisterHidden(name string, handler Handler, description string) {
+	r.Register(name, handler, description)
+	r.commands[name].Hidden = true
+}
+
+// MarkDeprecated flags a registered command as deprecated. The message is
+// printed as a warning the first time the command is executed.
+func (r *Registry) MarkDeprecated(name, message string) error {
+	cmd, exists := r.commands[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	if message == "" {
+		message = fmt.Sprintf("'%s' is deprecated", cmd.Name)
+	}
+	cmd.Deprecated = message
+	cmd.warned = false
+	return nil
+}
+
+// MarkDangerous makes a registered command ask the user to type
+// requireTyped, or the command name when it is empty, before every run.
+// Passing --force skips the prompt; without an interactive terminal the
+// command refuses to run unless forced.
+func (r *Registry) MarkDangerous(name, requireTyped string) error {
+	cmd, exists := r.commands[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	if requireTyped == "" {
+		requireTyped = cmd.Name
+	}
+	cmd.Dangerous = requireTyped
+	return nil
+}
+
 // RegisterFunc registers a function as a command handler
 func (r *Registry) RegisterFunc(name string, fn func([]string) error, description string) {
 	r.Register(name, HandlerFunc(fn), description)
@@ -91,26 +145,136 @@ func (r *Registry) RegisterWithBuilder(name string, handler Handler, description
 		Description: description,
 		Subcommands: make(map[string]*Command),
 		Completions: builder.Build(),
+		FlagRules:   builder.Rules(),
+	}
+}
+
+// SetCompletion replaces the completion of an already registered command
+// with one built by builder, e.g. to add state keys to a command registered
+// with arguments
+func (r *Registry) SetCompletion(name string, builder *CompletionBuilder) error {
+	cmd, exists := r.commands[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown command: %s", name)
 	}
+	cmd.Completions = builder.Build()
+	cmd.FlagRules = builder.Rules()
+	return nil
 }
 
 // Execute runs the specified command with arguments
 func (r *Registry) Execute(name string, args []string) error {
+	return r.ExecuteInput(name, args, nil)
+}
+
+// ExecuteInput runs a command with stdin available as CommandContext.Stdin,
+// as the console does for the right-hand side of a pipe. Only context
+// handlers can read it; other handlers run as with Execute.
+func (r *Registry) ExecuteInput(name string, args []string, stdin io.Reader) error {
+	command, ctx, err := r.prepare(name, args)
+	if err != nil || ctx == nil {
+		return err
+	}
+	ctx.Stdin = stdin
+
+	if handler, ok := command.Handler.(ContextHandler); ok {
+		return handler.ExecuteContext(ctx)
+	}
+	return command.Handler.Execute(ctx.Args)
+}
+
+// prepare looks up a command, parses its flags and validates its arguments.
+// The context is nil when the user only asked for help.
+func (r *Registry) prepare(name string, args []string) (*Command, *CommandContext, error) {
 	command, exists := r.commands[strings.ToLower(name)]
 	if !exists {
-		return fmt.Errorf("unknown command: %s. Type 'help' for a list of commands", name)
+		return nil, nil, fmt.Errorf("unknown command: %s. Type 'help' for a list of commands", name)
+	}
+
+	if command.Deprecated != "" && !command.warned {
+		command.warned = true
+		fmt.Printf("%s⚠️  %s%s\n", output.YellowColor, command.Deprecated, output.Reset)
+	}
+
+	// Dangerous commands own --force, it skips the typed confirmation
+	forced := false
+	if command.Dangerous != "" {
+		args, forced = stripForceFlag(args)
+	}
+
+	// Result commands are rendered by the registry, so it owns --json
+	jsonFlag := false
+	if _, ok := command.Handler.(resultAdapter); ok {
+		args, jsonFlag = stripJSONFlag(args)
+	}
+
+	ctx, err := r.newContext(command, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return command, nil, nil
+		}
+		return nil, nil, err
+	}
+	ctx.json = jsonFlag
+
+	if err := command.FlagRules.Check(presentFlags(ctx)); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", command.Name, err)
 	}
 
-	return command.Handler.Execute(args)
+	if err := command.validateArgs(ctx.Args); err != nil {
+		return nil, nil, err
+	}
+
+	if command.Dangerous != "" && !forced {
+		if err := command.confirmDangerous(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return command, ctx, nil
+}
+
+// confirmDangerous asks the user to type the command's confirmation token
+func (c *Command) confirmDangerous() error {
+	prompt := fmt.Sprintf("%s⚠️  '%s' is marked dangerous.%s", output.YellowColor, c.Name, output.Reset)
+	confirmed, err := output.ConfirmTyped(prompt, c.Dangerous)
+	if errors.Is(err, output.ErrNotInteractive) {
+		return fmt.Errorf("%s: confirmation required, rerun with --force", c.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("%s: aborted, confirmation did not match", c.Name)
+	}
+	return nil
+}
+
+// stripForceFlag removes --force from args and reports whether it was there
+func stripForceFlag(args []string) ([]string, bool) {
+	found := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--force" {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
 }
 
 // BuildCompleter creates a readline completer from registered commands
 func (r *Registry) BuildCompleter() readline.PrefixCompleterInterface {
 	var items []readline.PrefixCompleterInterface
 
-	for name, cmd := range r.commands {
+	for _, name := range r.ListCommands() {
+		cmd := r.commands[name]
+		if cmd.Hidden {
+			continue
+		}
+		
 		// Check if command implements Completer interface
-		if completer, ok := cmd.Handler.(Completer); ok {
+		if completer, ok := unwrapHandler(cmd.Handler).(Completer); ok {
 			items = append(items, r.buildDynamicCompletion(name, completer, cmd))
 		} else if len(cmd.Completions) > 0 {
 			// Use static completion configuration
@@ -123,27 +287,27 @@ func (r *Registry) BuildCompleter() readline.PrefixCompleterInterface {
 
 	// Add built-in commands
 	items = append(items,
-		readline.PcItem("help"),
+		readline.PcItem("help", readline.PcItemDynamic(func(string) []string {
+			return r.visibleCommands()
+		})),
+		readline.PcItem("time"),
+		readline.PcItem("alias"),
+		readline.PcItem("unalias"),
+		readline.PcItem("history"),
 		readline.PcItem("exit"),
 		readline.PcItem("quit"),
 	)
 
-	return readline.NewPrefixCompleter(items...)
+	return &filterCompleter{readline.NewPrefixCompleter(items...)}
 }
 
-// buildDynamicCompletion creates completion for commands implementing Completer interface
+// buildDynamicCompletion creates completion for commands implementing Completer interface.
+// The completer is asked for options each time Tab is pressed.
 func (r *Registry) buildDynamicCompletion(name string, completer Completer, cmd *Command) readline.PrefixCompleterInterface {
-	// For dynamic completion, we start with a basic structure
-	// The actual dynamic completion happens in the readline callback
-	var subItems []readline.PrefixCompleterInterface
-	
-	// Get initial completions for empty args
-	initialOptions := completer.Complete([]string{}, 0)
-	for _, option := range initialOptions {
-		subItems = append(subItems, readline.PcItem(option))
+	return &completerNode{
+		PrefixCompleter: readline.PcItem(name),
+		completer:       completer,
 	}
-	
-	return readline.PcItem(name, subItems...)
 }
 
 // buildStaticCompletion creates completion from static configuration
@@ -158,16 +322,14 @@ func (r *Registry) buildStaticCompletion(name string, cmd *Command) readline.Pre
 				subItems = append(subItems, readline.PcItem(option))
 			}
 			
-			// Add dynamic options if available
+			// Add dynamic options, generated when Tab is pressed
 			if completion.Dynamic != nil {
-				dynamicOptions := completion.Dynamic()
-				for _, option := range dynamicOptions {
-					subItems = append(subItems, readline.PcItem(option))
-				}
+				subItems = append(subItems, readline.PcItemDynamic(dynamicCompleter(completion.Dynamic)))
 			}
 			
 			// Add flag completions
-			for flag, flagOptions := range completion.Flags {
+			for _, flag := range sortedKeys(completion.Flags) {
+				flagOptions := completion.Flags[flag]
 				if len(flagOptions) > 0 {
 					var flagSubItems []readline.PrefixCompleterInterface
 					for _, flagOption := range flagOptions {
@@ -178,12 +340,26 @@ func (r *Registry) buildStaticCompletion(name string, cmd *Command) readline.Pre
 					subItems = append(subItems, readline.PcItem(flag))
 				}
 			}
+			for _, flag := range sortedKeys(completion.DynamicFlags) {
+				generator := completion.DynamicFlags[flag]
+				subItems = append(subItems, readline.PcItem(flag, readline.PcItemDynamic(dynamicCompleter(generator))))
+			}
 		}
 	}
 	
+	if cmd.FlagRules != nil {
+		return &rulesNode{PrefixCompleter: readline.PcItem(name, subItems...), rules: cmd.FlagRules}
+	}
 	return readline.PcItem(name, subItems...)
 }
 
+// dynamicCompleter adapts an option generator to readline's dynamic completion
+func dynamicCompleter(generator func() []string) readline.DynamicCompleteFunc {
+	return func(string) []string {
+		return generator()
+	}
+}
+
 // buildLegacyCompletion maintains backward compatibility for hardcoded completions
 func (r *Registry) buildLegacyCompletion(name string) readline.PrefixCompleterInterface {
 	switch name {
@@ -194,17 +370,46 @@ func (r *Registry) buildLegacyCompletion(name string) readline.PrefixCompleterIn
 			readline.PcItem("analyze"),
 			readline.PcItem("suggest"),
 			readline.PcItem("explain"),
+			readline.PcItem("debug"),
+			readline.PcItem("doctor"),
+			readline.PcItem("unload"),
 			readline.PcItem("status"),
 			readline.PcItem("context",
 				readline.PcItem("clear"),
 				readline.PcItem("stats"),
 				readline.PcItem("limit"),
+				readline.PcItem("search"),
+				readline.PcItem("pin"),
+				readline.PcItem("unpin"),
+				readline.PcItem("dump",
+					readline.PcItem("analyze"),
+					readline.PcItem("suggest"),
+					readline.PcItem("explain"),
+					readline.PcItem("debug"),
+				),
+				readline.PcItem("prompts"),
+				readline.PcItem("export", readline.PcItem("--include-secrets")),
+				readline.PcItem("import"),
 			),
 			readline.PcItem("validate",
 				readline.PcItem("model"),
 				readline.PcItem("url"),
 				readline.PcItem("rules"),
 			),
+			readline.PcItem("cache",
+				readline.PcItem("stats"),
+				readline.PcItem("clear"),
+			),
+			readline.PcItem("save"),
+			readline.PcItem("load"),
+			readline.PcItem("report"),
+			readline.PcItem("bench"),
+			readline.PcItem("batch"),
+			readline.PcItem("usage"),
+			readline.PcItem("quiet",
+				readline.PcItem("on"),
+				readline.PcItem("off"),
+			),
 			readline.PcItem("help",
 				readline.PcItem("errors"),
 			),
@@ -229,10 +434,21 @@ func (r *Registry) buildLegacyCompletion(name string) readline.PrefixCompleterIn
 	}
 }
 
-// ShowHelp displays help for all registered commands
+// ShowHelp displays help for all registered commands in alphabetical order
 func (r *Registry) ShowHelp() {
-	for name, cmd := range r.commands {
-		fmt.Printf("  %-20s %s\n", name, cmd.Description)
+	for _, name := range r.ListCommands() {
+		cmd := r.commands[name]
+		if cmd.Hidden {
+			continue
+		}
+		description := cmd.Description
+		if cmd.Deprecated != "" {
+			description += " (deprecated)"
+		}
+		if cmd.Dangerous != "" {
+			description += " (dangerous)"
+		}
+		fmt.Printf("  %-20s %s\n", name, description)
 	}
 }
 
@@ -242,11 +458,29 @@ func (r *Registry) GetCommand(name string) (*Command, bool) {
 	return cmd, exists
 }
 
-// ListCommands returns all registered command names
+// ListCommands returns all registered command names in alphabetical order
 func (r *Registry) ListCommands() []string {
+	return sortedKeys(r.commands)
+}
+
+// visibleCommands returns the sorted names of commands shown in help
+func (r *Registry) visibleCommands() []string {
 	var names []string
-	for name := range r.commands {
-		names = append(names, name)
+	for _, name := range r.ListCommands() {
+		if !r.commands[name].Hidden {
+			names = append(names, name)
+		}
 	}
 	return names
+}
+
+// sortedKeys returns the keys of m in alphabetical order, so output built
+// from a map is the same on every run
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
\ No newline at end of file
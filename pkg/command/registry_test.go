@@ -0,0 +1,113 @@
+package command
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+// newShuffledRegistry registers commands out of alphabetical order, with a
+// flag map whose iteration order Go randomizes
+func newShuffledRegistry() *Registry {
+	r := NewRegistry()
+	noop := func([]string) error { return nil }
+	for _, name := range []string{"zeta", "scan", "alpha", "report", "mid", "beta"} {
+		r.RegisterFunc(name, noop, "The "+name+" command")
+	}
+	r.RegisterHidden("secret", HandlerFunc(noop), "Not listed")
+	r.RegisterWithCompletion("fetch", HandlerFunc(noop), "Fetch a resource", map[int]ArgumentCompletion{
+		0: {
+			Position: 0,
+			Flags: map[string][]string{
+				"--timeout": nil,
+				"--depth":   nil,
+				"--output":  nil,
+				"--all":     nil,
+				"--verbose": nil,
+				"--proxy":   nil,
+			},
+		},
+	})
+	return r
+}
+
+var shuffledCommands = []string{"alpha", "beta", "fetch", "mid", "report", "scan", "zeta"}
+
+func TestListCommandsSorted(t *testing.T) {
+	r := newShuffledRegistry()
+	want := append([]string{}, shuffledCommands...)
+	want = append(want, "secret")
+	sort.Strings(want)
+
+	if got := r.ListCommands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListCommands() = %q, want %q", got, want)
+	}
+}
+
+func TestShowHelpSortedAndStable(t *testing.T) {
+	var first string
+	for run := 0; run < 20; run++ {
+		help, err := output.CaptureStdout(func() error {
+			newShuffledRegistry().ShowHelp()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("CaptureStdout: %v", err)
+		}
+
+		var names []string
+		for _, line := range strings.Split(strings.TrimSpace(help), "\n") {
+			names = append(names, strings.Fields(line)[0])
+		}
+		if !reflect.DeepEqual(names, shuffledCommands) {
+			t.Fatalf("ShowHelp listed %q, want %q", names, shuffledCommands)
+		}
+
+		if run == 0 {
+			first = help
+		} else if help != first {
+			t.Fatalf("ShowHelp output changed between runs:\n%s\nvs\n%s", first, help)
+		}
+	}
+}
+
+func TestCompletionSortedAndStable(t *testing.T) {
+	var firstTop, firstFlags, firstHelp []string
+	for run := 0; run < 20; run++ {
+		r := newShuffledRegistry()
+		top := completeAt(t, r, "", 0)
+		flags := completeAt(t, r, "fetch -", len("fetch -"))
+		help := completeAt(t, r, "help ", len("help "))
+
+		// Registered commands come first, in order, ahead of the built-ins
+		if got := trimSpaces(top[:len(shuffledCommands)]); !reflect.DeepEqual(got, shuffledCommands) {
+			t.Fatalf("top-level completion = %q, want %q first", top, shuffledCommands)
+		}
+		if !sort.StringsAreSorted(flags) || len(flags) != 6 {
+			t.Fatalf("flag completion = %q, want 6 sorted flags", flags)
+		}
+		if got := trimSpaces(help); !reflect.DeepEqual(got, shuffledCommands) {
+			t.Fatalf("help completion = %q, want %q", got, shuffledCommands)
+		}
+
+		if run == 0 {
+			firstTop, firstFlags, firstHelp = top, flags, help
+			continue
+		}
+		if !reflect.DeepEqual(top, firstTop) || !reflect.DeepEqual(flags, firstFlags) || !reflect.DeepEqual(help, firstHelp) {
+			t.Fatalf("completion changed between runs: %q %q %q vs %q %q %q",
+				firstTop, firstFlags, firstHelp, top, flags, help)
+		}
+	}
+}
+
+func trimSpaces(options []string) []string {
+	trimmed := make([]string, len(options))
+	for i, option := range options {
+		trimmed[i] = strings.TrimSpace(option)
+	}
+	return trimmed
+}
\ No newline at end of file
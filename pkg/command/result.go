@@ -0,0 +1,215 @@
+package command
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+// CommandResult is what a ResultHandler returns instead of printing. The
+// registry renders it in the active output mode: Text for people, Data as
+// JSON for scripts, and Columns/Rows as a table.
+type CommandResult struct {
+	Text    string      // human-readable form
+	Data    interface{} // structured form for JSON output
+	Columns []string    // optional table headers
+	Rows    [][]string  // optional table rows
+}
+
+// NewTextResult returns a result with only a text form
+func NewTextResult(format string, args ...interface{}) *CommandResult {
+	return &CommandResult{Text: fmt.Sprintf(format, args...)}
+}
+
+// NewTableResult returns a result shown as a table, with each row encoded
+// as an object keyed by column in JSON mode
+func NewTableResult(columns []string, rows [][]string) *CommandResult {
+	return &CommandResult{Columns: columns, Rows: rows}
+}
+
+// Render writes the result to w in the given mode. Text mode prefers Text,
+// then the table, then Data; table mode prefers the table; JSON mode
+// prefers Data, then the rows, then {"output": Text}.
+func (r *CommandResult) Render(w io.Writer, mode output.Mode) error {
+	if r == nil {
+		return nil
+	}
+
+	switch mode {
+	case output.ModeJSON:
+		data, err := json.MarshalIndent(r.jsonValue(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON output: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case output.ModeTable:
+		if len(r.Columns) > 0 {
+			return r.renderTable(w)
+		}
+	}
+
+	switch {
+	case r.Text != "":
+		_, err := fmt.Fprint(w, withNewline(r.Text))
+		return err
+	case len(r.Columns) > 0:
+		return r.renderTable(w)
+	case r.Data != nil:
+		_, err := fmt.Fprintln(w, r.Data)
+		return err
+	}
+	return nil
+}
+
+// jsonValue picks the value encoded in JSON mode
+func (r *CommandResult) jsonValue() interface{} {
+	if r.Data != nil {
+		return r.Data
+	}
+	if len(r.Columns) > 0 {
+		records := make([]map[string]string, 0, len(r.Rows))
+		for _, row := range r.Rows {
+			record := make(map[string]string, len(r.Columns))
+			for n, column := range r.Columns {
+				if n < len(row) {
+					record[column] = row[n]
+				}
+			}
+			records = append(records, record)
+		}
+		return records
+	}
+	return map[string]string{"output": r.Text}
+}
+
+// renderTable writes Columns and Rows as an output.Table
+func (r *CommandResult) renderTable(w io.Writer) error {
+	table := output.NewTable(r.Columns...)
+	for _, row := range r.Rows {
+		table.AddRow(row...)
+	}
+	_, err := fmt.Fprint(w, table.Render())
+	return err
+}
+
+// withNewline ends s with a newline so the prompt starts on its own line
+func withNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
+// ResultHandler is a handler that returns its output for the registry to
+// render instead of printing it
+type ResultHandler interface {
+	ExecuteResult(ctx *CommandContext) (*CommandResult, error)
+	Description() string
+}
+
+// ResultHandlerFunc allows using functions as result handlers
+type ResultHandlerFunc func(ctx *CommandContext) (*CommandResult, error)
+
+func (f ResultHandlerFunc) ExecuteResult(ctx *CommandContext) (*CommandResult, error) {
+	return f(ctx)
+}
+
+func (f ResultHandlerFunc) Description() string {
+	return "Custom command"
+}
+
+// captureAdapter lets a Handler or ContextHandler be used as a
+// ResultHandler; whatever it prints becomes the result's text
+type captureAdapter struct {
+	ContextHandler
+}
+
+// ExecuteResult runs the handler with stdout captured
+func (a captureAdapter) ExecuteResult(ctx *CommandContext) (*CommandResult, error) {
+	text, err := output.CaptureStdout(func() error {
+		return a.ExecuteContext(ctx)
+	})
+	return &CommandResult{Text: text}, err
+}
+
+func (a captureAdapter) unwrap() interface{} { return a.ContextHandler }
+
+// AdaptResult wraps a Handler as a ResultHandler. Handlers that already
+// return results are used as they are; others have their printed output
+// captured into CommandResult.Text.
+func AdaptResult(h Handler) ResultHandler {
+	if rh, ok := h.(ResultHandler); ok {
+		return rh
+	}
+	return captureAdapter{AdaptHandler(h)}
+}
+
+// resultAdapter lets a ResultHandler be stored and called as a Handler
+type resultAdapter struct {
+	ResultHandler
+}
+
+// Execute runs the handler with positional arguments only and prints the
+// result in the current output mode
+func (a resultAdapter) Execute(args []string) error {
+	return a.ExecuteContext(&CommandContext{Args: args})
+}
+
+func (a resultAdapter) unwrap() interface{} { return a.ResultHandler }
+
+// ExecuteContext runs the handler and prints its result
+func (a resultAdapter) ExecuteContext(ctx *CommandContext) error {
+	result, err := a.ExecuteResult(ctx)
+	if renderErr := result.Render(os.Stdout, resultMode(ctx)); err == nil {
+		err = renderErr
+	}
+	return err
+}
+
+// resultMode is the session mode, switched to JSON when --json was given
+func resultMode(ctx *CommandContext) output.Mode {
+	if ctx.json {
+		return output.ModeJSON
+	}
+	return output.CurrentMode
+}
+
+// stripJSONFlag removes --json from args and reports whether it was there
+func stripJSONFlag(args []string) ([]string, bool) {
+	found := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--json" {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// RegisterResult adds a command whose handler returns a CommandResult. Like
+// context commands, flags may be nil. A --json argument renders that one
+// result as JSON.
+func (r *Registry) RegisterResult(name string, handler ResultHandler, description string, flags *flag.FlagSet) {
+	r.Register(name, resultAdapter{handler}, description)
+	r.commands[name].Flags = flags
+}
+
+// ExecuteResult runs a command and returns its result without printing it.
+// Commands that print instead of returning results have their output
+// captured into the result's text.
+func (r *Registry) ExecuteResult(name string, args []string) (*CommandResult, error) {
+	command, ctx, err := r.prepare(name, args)
+	if err != nil || ctx == nil {
+		return nil, err
+	}
+
+	return AdaptResult(command.Handler).ExecuteResult(ctx)
+}
\ No newline at end of file
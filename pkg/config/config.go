@@ -1,16 +1,24 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents application configuration
 type Config struct {
-	data map[string]interface{}
+	data   map[string]interface{}
+	format Format // overrides extension-based format detection when set
 }
 
 // New creates a new config instance
@@ -20,40 +28,173 @@ func New() *Config {
 	}
 }
 
-// LoadFromFile loads configuration from a YAML file
+// LoadFromFile loads configuration from a YAML, JSON or TOML file, chosen by
+// extension or SetFormat
 func (c *Config) LoadFromFile(path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	return yaml.Unmarshal(data, &c.data)
+	parsed, err := decodeConfig(data, c.formatFor(path))
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	c.data = parsed
+	return nil
+}
+
+// LoadFromFileWithValidation loads configuration from a file and validates
+// it, returning every validation failure as a ValidationErrors
+func (c *Config) LoadFromFileWithValidation(path string, v *Validator) error {
+	if err := c.LoadFromFile(path); err != nil {
+		return err
+	}
+	
+	if v == nil {
+		return nil
+	}
+	if err := v.Validate(c.data); err != nil {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return nil
+}
+
+// MergeFromFile deep-merges a config file into the existing configuration.
+// Nested maps are merged recursively; scalars and slices are replaced.
+func (c *Config) MergeFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	
+	overlay, err := decodeConfig(data, c.formatFor(path))
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	
+	mergeMaps(c.data, overlay)
+	return nil
+}
+
+// Merge deep-merges another configuration into this one, with values from
+// other taking precedence
+func (c *Config) Merge(other *Config) {
+	if other == nil {
+		return
+	}
+	mergeMaps(c.data, other.data)
 }
 
-// SaveToFile saves configuration to a YAML file
+// mergeMaps recursively merges src into dst. Nested maps in src are copied so
+// later changes to dst never alias src.
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		if !srcIsMap {
+			dst[key] = srcValue
+			continue
+		}
+		
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if !dstIsMap {
+			dstMap = make(map[string]interface{}, len(srcMap))
+			dst[key] = dstMap
+		}
+		mergeMaps(dstMap, srcMap)
+	}
+}
+
+// SaveToFile saves configuration in the format matching the file extension
+// or SetFormat
 func (c *Config) SaveToFile(path string) error {
-	data, err := yaml.Marshal(c.data)
+	data, err := encodeConfig(c.data, c.formatFor(path))
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return utils.WriteFileAtomic(path, data, 0644)
+}
+
+// Keys returns every leaf key in sorted order, with nested keys in dotted
+// form such as "server.timeout"
+func (c *Config) Keys() []string {
+	var keys []string
+	collectKeys(c.data, "", &keys)
+	sort.Strings(keys)
+	return keys
 }
 
-// Set sets a configuration value
+// collectKeys appends the dotted paths of the leaves of data to keys
+func collectKeys(data map[string]interface{}, prefix string, keys *[]string) {
+	for key, value := range data {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			collectKeys(nested, path, keys)
+			continue
+		}
+		*keys = append(*keys, path)
+	}
+}
+
+// Set sets a configuration value. Dotted keys like "server.timeout" set
+// nested values, creating intermediate maps as needed.
 func (c *Config) Set(key string, value interface{}) {
-	c.data[key] = value
+	if _, exists := c.data[key]; exists || !strings.Contains(key, ".") {
+		c.data[key] = value
+		return
+	}
+	
+	parts := strings.Split(key, ".")
+	current := c.data
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
 }
 
-// Get gets a configuration value
+// Get gets a configuration value. Dotted keys like "server.timeout" are
+// resolved through nested maps when no flat key with that name exists.
 func (c *Config) Get(key string) (interface{}, bool) {
-	value, exists := c.data[key]
-	return value, exists
+	return c.lookup(key)
+}
+
+// lookup resolves a flat or dotted key
+func (c *Config) lookup(key string) (interface{}, bool) {
+	return lookupPath(c.data, key)
+}
+
+// lookupPath resolves a flat or dotted key in a nested map
+func lookupPath(data map[string]interface{}, key string) (interface{}, bool) {
+	if value, exists := data[key]; exists || !strings.Contains(key, ".") {
+		return value, exists
+	}
+	
+	var current interface{} = data
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
 }
 
 // GetString gets a string configuration value
 func (c *Config) GetString(key string) (string, bool) {
-	value, exists := c.data[key]
+	value, exists := c.lookup(key)
 	if !exists {
 		return "", false
 	}
@@ -65,7 +206,7 @@ func (c *Config) GetString(key string) (string, bool) {
 
 // GetInt gets an integer configuration value
 func (c *Config) GetInt(key string) (int, bool) {
-	value, exists := c.data[key]
+	value, exists := c.lookup(key)
 	if !exists {
 		return 0, false
 	}
@@ -77,7 +218,7 @@ func (c *Config) GetInt(key string) (int, bool) {
 
 // GetBool gets a boolean configuration value
 func (c *Config) GetBool(key string) (bool, bool) {
-	value, exists := c.data[key]
+	value, exists := c.lookup(key)
 	if !exists {
 		return false, false
 	}
@@ -87,6 +228,62 @@ func (c *Config) GetBool(key string) (bool, bool) {
 	return false, false
 }
 
+// GetIntDefault returns an integer value, or def if the key is missing or not numeric
+func (c *Config) GetIntDefault(key string, def int) int {
+	if value, exists := c.Get(key); exists {
+		if i, ok := toInt(value); ok {
+			return i
+		}
+	}
+	return def
+}
+
+// GetStringDefault returns a string value, or def if the key is missing or not a string
+func (c *Config) GetStringDefault(key string, def string) string {
+	if str, ok := c.GetString(key); ok {
+		return str
+	}
+	return def
+}
+
+// GetBoolDefault returns a boolean value, or def if the key is missing or not a boolean.
+// Strings such as "true", "yes" and "1" are accepted.
+func (c *Config) GetBoolDefault(key string, def bool) bool {
+	if value, exists := c.Get(key); exists {
+		if b, ok := toBool(value); ok {
+			return b
+		}
+	}
+	return def
+}
+
+// GetDuration gets a duration value, parsing strings like "30s". Plain numbers are seconds.
+func (c *Config) GetDuration(key string) (time.Duration, bool) {
+	value, exists := c.Get(key)
+	if !exists {
+		return 0, false
+	}
+	return toDuration(value)
+}
+
+// GetFloat64 gets a floating point value
+func (c *Config) GetFloat64(key string) (float64, bool) {
+	value, exists := c.Get(key)
+	if !exists {
+		return 0, false
+	}
+	return toFloat64(value)
+}
+
+// GetStringSlice gets a list value. Comma-separated strings are split.
+func (c *Config) GetStringSlice(key string) ([]string, bool) {
+	value, exists := c.Get(key)
+	if !exists {
+		return nil, false
+	}
+	return toStringSlice(value)
+}
+
 // LoadFromStruct loads configuration from a struct using YAML tags
 func (c *Config) LoadFromStruct(v interface{}) error {
 	data, err := yaml.Marshal(v)
@@ -107,19 +304,53 @@ func (c *Config) UnmarshalTo(v interface{}) error {
 	return yaml.Unmarshal(data, v)
 }
 
-// HandleStartupFlag processes the --config startup flag
+// HandleStartupFlag processes the --config startup flag. Without the flag
+// it falls back to Discover, using the executable's name as the app name,
+// and returns "" when no config file is found.
 func HandleStartupFlag() (string, error) {
 	var configPath string
-	flag.StringVar(&configPath, "config", "", "Path to a YAML configuration file")
+	flag.StringVar(&configPath, "config", "", "Path to a configuration file (YAML, JSON or TOML)")
 	flag.Parse()
 
 	if configPath != "" {
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			return "", fmt.Errorf("config file does not exist: %s", configPath)
 		}
+		return configPath, nil
 	}
 
-	return configPath, nil
+	appName := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	discovered, err := Discover(appName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return discovered, err
+}
+
+// DiscoverPaths returns the places Discover looks for appName's config, in
+// order: ./<app>.yaml, $XDG_CONFIG_HOME/<app>/config.yaml (when the variable
+// is set) and ~/.config/<app>/config.yaml
+func DiscoverPaths(appName string) []string {
+	paths := []string{appName + ".yaml"}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, appName, "config.yaml"))
+	}
+	if defaultPath, err := utils.GetDefaultConfigPath(appName); err == nil {
+		paths = append(paths, defaultPath)
+	}
+	return paths
+}
+
+// Discover returns the first existing config file from DiscoverPaths. The
+// error wraps fs.ErrNotExist when there is none.
+func Discover(appName string) (string, error) {
+	paths := DiscoverPaths(appName)
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no config file for %s in %s: %w", appName, strings.Join(paths, ", "), fs.ErrNotExist)
 }
 
 // GenerateExample generates an example configuration file content
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// durationType is special-cased so durations print as "30s" rather than nanoseconds
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// GenerateExampleFromStruct renders v, a struct or pointer to one, as a
+// commented example YAML config. Keys come from yaml tags (fields tagged "-"
+// are skipped and ",inline" structs are flattened), values from v's fields,
+// and each line carries a type hint. A zero-valued field shows its
+// `default:"..."` tag instead, and a `comment:"..."` tag becomes a comment
+// above the key.
+//
+//	type Settings struct {
+//		Target  string        `yaml:"target" comment:"URL to scan"`
+//		Threads int           `yaml:"threads" default:"10"`
+//		Timeout time.Duration `yaml:"timeout"`
+//	}
+//
+//	fmt.Print(config.GenerateExampleFromStruct(Settings{Timeout: 5 * time.Second}))
+func GenerateExampleFromStruct(v interface{}) string {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value = reflect.New(value.Type().Elem())
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Sprintf("# cannot generate an example from %T\n", v)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s configuration\n#\n# Load with: --config /path/to/config.yaml\n\n", value.Type().Name())
+	writeStructExample(&b, value, 0)
+	return b.String()
+}
+
+// writeStructExample writes the fields of a struct value at the given depth
+func writeStructExample(b *strings.Builder, value reflect.Value, depth int) {
+	indent := strings.Repeat("  ", depth)
+	structType := value.Type()
+
+	for n := 0; n < structType.NumField(); n++ {
+		field := structType.Field(n)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, inline, skip := yamlFieldName(field)
+		if skip {
+			continue
+		}
+		fieldValue := value.Field(n)
+		if inline {
+			writeStructExample(b, indirect(fieldValue), depth)
+			continue
+		}
+
+		if comment := field.Tag.Get("comment"); comment != "" {
+			fmt.Fprintf(b, "%s# %s\n", indent, comment)
+		}
+
+		fieldValue = indirect(fieldValue)
+		hint := typeHint(fieldValue.Type())
+		defaultTag, hasDefault := field.Tag.Lookup("default")
+
+		switch {
+		case fieldValue.IsZero() && hasDefault:
+			fmt.Fprintf(b, "%s%s: %s  # %s\n", indent, name, defaultLiteral(fieldValue.Type(), defaultTag), hint)
+		case fieldValue.Kind() == reflect.Struct && fieldValue.Type() != durationType:
+			fmt.Fprintf(b, "%s%s:\n", indent, name)
+			writeStructExample(b, fieldValue, depth+1)
+		case fieldValue.Kind() == reflect.Slice && fieldValue.Len() > 0:
+			fmt.Fprintf(b, "%s%s:  # %s\n", indent, name, hint)
+			for i := 0; i < fieldValue.Len(); i++ {
+				fmt.Fprintf(b, "%s  - %s\n", indent, scalarLiteral(fieldValue.Index(i)))
+			}
+		case fieldValue.Kind() == reflect.Map && fieldValue.Len() > 0:
+			fmt.Fprintf(b, "%s%s:  # %s\n", indent, name, hint)
+			keys := fieldValue.MapKeys()
+			sort.Slice(keys, func(i, j int) bool {
+				return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+			})
+			for _, key := range keys {
+				fmt.Fprintf(b, "%s  %s: %s\n", indent, scalarLiteral(key), scalarLiteral(fieldValue.MapIndex(key)))
+			}
+		default:
+			fmt.Fprintf(b, "%s%s: %s  # %s\n", indent, name, scalarLiteral(fieldValue), hint)
+		}
+	}
+}
+
+// yamlFieldName returns the key yaml.v3 uses for a field, whether it is
+// inlined and whether it is skipped
+func yamlFieldName(field reflect.StructField) (name string, inline, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "inline" {
+			inline = true
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, inline, false
+}
+
+// indirect follows pointers, using a zero value for nil ones
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.New(value.Type().Elem()).Elem()
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// typeHint describes a type for the comment after a value
+func typeHint(t reflect.Type) string {
+	if t == durationType {
+		return "duration, e.g. 30s or 5m"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeHint(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list of " + typeHint(t.Elem())
+	case reflect.Map:
+		return fmt.Sprintf("map of %s to %s", typeHint(t.Key()), typeHint(t.Elem()))
+	case reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
+// scalarLiteral renders a value as YAML on one line; empty lists and maps
+// become [] and {}
+func scalarLiteral(value reflect.Value) string {
+	value = indirect(value)
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Len() == 0 {
+			return "[]"
+		}
+	case reflect.Map:
+		if value.Len() == 0 {
+			return "{}"
+		}
+	case reflect.String:
+		// Always quote strings so empty ones still read as strings
+		return fmt.Sprintf("%q", value.String())
+	}
+
+	data, err := yaml.Marshal(value.Interface())
+	if err != nil {
+		return fmt.Sprint(value.Interface())
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// defaultLiteral renders a default tag, quoting it for string fields
+func defaultLiteral(t reflect.Type, tag string) string {
+	if t.Kind() == reflect.String {
+		return fmt.Sprintf("%q", tag)
+	}
+	return tag
+}
\ No newline at end of file
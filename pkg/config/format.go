@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies a configuration file format
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// SetFormat forces the format used to read and write files, overriding
+// detection by extension. Pass an empty Format to restore detection.
+func (c *Config) SetFormat(f Format) {
+	c.format = f
+}
+
+// formatFor returns the format for path: the override if set, otherwise by
+// extension, defaulting to YAML
+func (c *Config) formatFor(path string) Format {
+	if c.format != "" {
+		return c.format
+	}
+	return DetectFormat(path)
+}
+
+// DetectFormat determines a file's format from its extension, defaulting to YAML
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatYAML
+	}
+}
+
+// decodeConfig parses data in the given format into a map
+func decodeConfig(data []byte, format Format) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &result)
+	case FormatTOML:
+		err = toml.Unmarshal(data, &result)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &result)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	normalizeNumbers(result)
+	return result, nil
+}
+
+// encodeConfig serializes a map in the given format
+func encodeConfig(data map[string]interface{}, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatYAML:
+		return yaml.Marshal(data)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+}
+
+// normalizeNumbers converts whole-number floats (JSON) and int64 values (TOML)
+// to int so typed getters behave the same regardless of format
+func normalizeNumbers(data map[string]interface{}) {
+	for key, value := range data {
+		data[key] = normalizeValue(value)
+	}
+}
+
+// normalizeValue applies normalizeNumbers to a single value
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
+	case int64:
+		return int(v)
+	case map[string]interface{}:
+		normalizeNumbers(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeValue(v[i])
+		}
+	case []map[string]interface{}:
+		for _, m := range v {
+			normalizeNumbers(m)
+		}
+	}
+	return value
+}
\ No newline at end of file
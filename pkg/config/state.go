@@ -2,29 +2,61 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 	
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
 	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+	"gopkg.in/yaml.v3"
 )
 
 // State manages global application state with thread safety
 type State struct {
-	data  map[string]interface{}
-	mutex sync.RWMutex
+	data    map[string]interface{}
+	mutex   sync.RWMutex
+	version uint64 // incremented on every change, used by AutoSave
+	
+	sensitivePatterns []string // extra substrings marking keys as sensitive
+	
+	listenerMu   sync.RWMutex
+	listeners    map[string]map[int]func(old, new interface{})
+	anyListeners map[int]func(key string, old, new interface{})
+	nextListener int
+}
+
+// stateChange records a single key change for listener dispatch
+type stateChange struct {
+	key      string
+	old, new interface{}
+}
+
+// SaveOptions controls how state is written to disk
+type SaveOptions struct {
+	IncludeSecrets bool // also persist keys flagged as sensitive
 }
 
 // NewState creates a new state manager
 func NewState() *State {
 	return &State{
-		data: make(map[string]interface{}),
+		data:         make(map[string]interface{}),
+		listeners:    make(map[string]map[int]func(old, new interface{})),
+		anyListeners: make(map[int]func(key string, old, new interface{})),
 	}
 }
 
 // Set sets a state value (thread-safe)
 func (s *State) Set(key string, value interface{}) {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	old := s.data[key]
 	s.data[key] = value
+	s.version++
+	s.mutex.Unlock()
+	
+	s.notify(stateChange{key: key, old: old, new: value})
 }
 
 // Get gets a state value (thread-safe)
@@ -77,18 +109,87 @@ func (s *State) GetBool(key string) (bool, bool) {
 	return false, false
 }
 
+// GetIntDefault returns an integer value, or def if the key is missing or not numeric
+func (s *State) GetIntDefault(key string, def int) int {
+	if value, exists := s.Get(key); exists {
+		if i, ok := toInt(value); ok {
+			return i
+		}
+	}
+	return def
+}
+
+// GetStringDefault returns a string value, or def if the key is missing or not a string
+func (s *State) GetStringDefault(key string, def string) string {
+	if str, ok := s.GetString(key); ok {
+		return str
+	}
+	return def
+}
+
+// GetBoolDefault returns a boolean value, or def if the key is missing or not a boolean.
+// Strings such as "true", "yes" and "1" are accepted.
+func (s *State) GetBoolDefault(key string, def bool) bool {
+	if value, exists := s.Get(key); exists {
+		if b, ok := toBool(value); ok {
+			return b
+		}
+	}
+	return def
+}
+
+// GetDuration gets a duration value, parsing strings like "30s". Plain numbers are seconds.
+func (s *State) GetDuration(key string) (time.Duration, bool) {
+	value, exists := s.Get(key)
+	if !exists {
+		return 0, false
+	}
+	return toDuration(value)
+}
+
+// GetFloat64 gets a floating point value
+func (s *State) GetFloat64(key string) (float64, bool) {
+	value, exists := s.Get(key)
+	if !exists {
+		return 0, false
+	}
+	return toFloat64(value)
+}
+
+// GetStringSlice gets a list value. Comma-separated strings are split.
+func (s *State) GetStringSlice(key string) ([]string, bool) {
+	value, exists := s.Get(key)
+	if !exists {
+		return nil, false
+	}
+	return toStringSlice(value)
+}
+
 // Delete removes a state value (thread-safe)
 func (s *State) Delete(key string) {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	old, exists := s.data[key]
 	delete(s.data, key)
+	s.version++
+	s.mutex.Unlock()
+	
+	if exists {
+		s.notify(stateChange{key: key, old: old})
+	}
 }
 
 // Clear removes all state values (thread-safe)
 func (s *State) Clear() {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	changes := make([]stateChange, 0, len(s.data))
+	for key, old := range s.data {
+		changes = append(changes, stateChange{key: key, old: old})
+	}
 	s.data = make(map[string]interface{})
+	s.version++
+	s.mutex.Unlock()
+	
+	s.notify(changes...)
 }
 
 // Keys returns all state keys (thread-safe)
@@ -110,28 +211,307 @@ func (s *State) ShowAll() {
 	fmt.Println("\n--- Current State ---")
 	for key, value := range s.data {
 		// Mask sensitive values
-		if isSensitive(key) {
-			if str, ok := value.(string); ok {
-				fmt.Printf("  %-15s : %s\n", key, utils.MaskString(str, 4, 4))
-				continue
-			}
+		if s.isSensitiveLocked(key) {
+			fmt.Printf("  %-15s : %s\n", key, utils.MaskString(fmt.Sprintf("%v", value), 4, 4))
+			continue
 		}
 		fmt.Printf("  %-15s : %v\n", key, value)
 	}
 	fmt.Println("--------------------")
 }
 
-// isSensitive checks if a key contains sensitive information
-func isSensitive(key string) bool {
-	sensitiveKeys := []string{
-		"password", "token", "secret", "key", "credential",
-		"apikey", "api_key", "auth", "jwt", "bearer",
+// OnChange registers fn to be called after key is set or deleted. On delete,
+// new is nil. Call the returned function to unregister the listener.
+func (s *State) OnChange(key string, fn func(old, new interface{})) func() {
+	s.listenerMu.Lock()
+	defer s.listenerMu.Unlock()
+	
+	id := s.nextListener
+	s.nextListener++
+	if s.listeners[key] == nil {
+		s.listeners[key] = make(map[int]func(old, new interface{}))
+	}
+	s.listeners[key][id] = fn
+	
+	return func() {
+		s.listenerMu.Lock()
+		defer s.listenerMu.Unlock()
+		delete(s.listeners[key], id)
+		if len(s.listeners[key]) == 0 {
+			delete(s.listeners, key)
+		}
+	}
+}
+
+// OnAnyChange registers fn to be called after any key is set or deleted.
+// Call the returned function to unregister the listener.
+func (s *State) OnAnyChange(fn func(key string, old, new interface{})) func() {
+	s.listenerMu.Lock()
+	defer s.listenerMu.Unlock()
+	
+	id := s.nextListener
+	s.nextListener++
+	s.anyListeners[id] = fn
+	
+	return func() {
+		s.listenerMu.Lock()
+		defer s.listenerMu.Unlock()
+		delete(s.anyListeners, id)
+	}
+}
+
+// notify invokes listeners for the given changes. It must be called without
+// holding the state lock so listeners can read and modify state.
+func (s *State) notify(changes ...stateChange) {
+	if len(changes) == 0 {
+		return
+	}
+	
+	// Snapshot listeners so they can unregister themselves while running
+	s.listenerMu.RLock()
+	keyListeners := make(map[string][]func(old, new interface{}))
+	for _, change := range changes {
+		for _, fn := range s.listeners[change.key] {
+			keyListeners[change.key] = append(keyListeners[change.key], fn)
+		}
+	}
+	anyListeners := make([]func(key string, old, new interface{}), 0, len(s.anyListeners))
+	for _, fn := range s.anyListeners {
+		anyListeners = append(anyListeners, fn)
+	}
+	s.listenerMu.RUnlock()
+	
+	for _, change := range changes {
+		for _, fn := range keyListeners[change.key] {
+			fn(change.old, change.new)
+		}
+		for _, fn := range anyListeners {
+			fn(change.key, change.old, change.new)
+		}
+	}
+}
+
+// Save writes the state to a YAML file, skipping sensitive keys
+func (s *State) Save(path string) error {
+	return s.SaveWithOptions(path, SaveOptions{})
+}
+
+// SaveWithOptions writes the state to a YAML file
+func (s *State) SaveWithOptions(path string, opts SaveOptions) error {
+	s.mutex.RLock()
+	data := make(map[string]interface{}, len(s.data))
+	for key, value := range s.data {
+		if !opts.IncludeSecrets && s.isSensitiveLocked(key) {
+			continue
+		}
+		data[key] = value
+	}
+	s.mutex.RUnlock()
+	
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	
+	if err := utils.WriteFileAtomic(path, out, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// Load reads state from a YAML file, merging it into the current values
+func (s *State) Load(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+	
+	var data map[string]interface{}
+	if err := yaml.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("failed to parse state file: %w", err)
+	}
+	
+	s.mutex.Lock()
+	changes := make([]stateChange, 0, len(data))
+	for key, value := range data {
+		changes = append(changes, stateChange{key: key, old: s.data[key], new: value})
+		s.data[key] = value
+	}
+	s.version++
+	s.mutex.Unlock()
+	
+	s.notify(changes...)
+	return nil
+}
+
+// Diff compares a snapshot written by Save with the current values and
+// returns a unified diff (see output.Diff) with one "key: value" line per
+// key. Sensitive values are masked, and a sensitive key missing from the
+// snapshot is left out, since Save skips secrets by default. An empty
+// result means nothing changed.
+func (s *State) Diff(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read state file: %w", err)
+	}
+	
+	var saved map[string]interface{}
+	if err := yaml.Unmarshal(raw, &saved); err != nil {
+		return "", fmt.Errorf("failed to parse state file: %w", err)
+	}
+	
+	s.mutex.RLock()
+	current := make(map[string]interface{}, len(s.data))
+	for key, value := range s.data {
+		if _, inSnapshot := saved[key]; inSnapshot || !s.isSensitiveLocked(key) {
+			current[key] = value
+		}
+	}
+	s.mutex.RUnlock()
+	
+	return output.Diff(s.diffText(saved), s.diffText(current)), nil
+}
+
+// diffText renders values as sorted "key: value" lines, masking secrets
+func (s *State) diffText(values map[string]interface{}) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	
+	var b strings.Builder
+	for _, key := range keys {
+		value := fmt.Sprintf("%v", values[key])
+		if s.IsSensitive(key) {
+			value = utils.MaskString(value, 4, 4)
+		}
+		fmt.Fprintf(&b, "%s: %s\n", key, value)
+	}
+	return b.String()
+}
+
+// AutoSave periodically saves the state to path whenever it has changed.
+// Call the returned function to stop saving; it performs a final flush.
+// Errors are reported to onError if provided.
+func (s *State) AutoSave(path string, interval time.Duration, onError ...func(error)) func() {
+	report := func(err error) {
+		for _, fn := range onError {
+			fn(err)
+		}
+	}
+	
+	// Start from zero so existing unsaved values are written on the first tick
+	var saved uint64
+	
+	flush := func() {
+		s.mutex.RLock()
+		current := s.version
+		s.mutex.RUnlock()
+		
+		if current == saved {
+			return
+		}
+		if err := s.Save(path); err != nil {
+			report(err)
+			return
+		}
+		saved = current
 	}
 	
-	for _, sensitive := range sensitiveKeys {
-		if key == sensitive {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		
+		for {
+			select {
+			case <-ticker.C:
+				flush()
+			case <-stop:
+				flush()
+				return
+			}
+		}
+	}()
+	
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// AddSensitivePattern marks keys containing substr (case-insensitive) as sensitive
+func (s *State) AddSensitivePattern(substr string) {
+	if substr == "" {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sensitivePatterns = append(s.sensitivePatterns, strings.ToLower(substr))
+}
+
+// IsSensitive reports whether values stored under key are masked and excluded from saves
+func (s *State) IsSensitive(key string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.isSensitiveLocked(key)
+}
+
+// isSensitiveLocked checks default and registered patterns; the caller must hold the lock
+func (s *State) isSensitiveLocked(key string) bool {
+	if isSensitive(key) {
+		return true
+	}
+	lower := strings.ToLower(key)
+	for _, pattern := range s.sensitivePatterns {
+		if strings.Contains(lower, pattern) {
 			return true
 		}
 	}
 	return false
+}
+
+// IsSensitiveKey reports whether values stored under key should be masked when displayed
+func IsSensitiveKey(key string) bool {
+	return isSensitive(key)
+}
+
+// sensitivePatterns are substrings that mark a key as holding sensitive information
+var sensitivePatterns = []string{
+	"password", "passwd", "token", "secret", "credential",
+	"authorization", "jwt", "bearer",
+}
+
+// sensitiveWords mark a key as sensitive only as a whole word, so
+// "api_key" and "apiKey" are sensitive but "keyword" and "monkey" aren't
+var sensitiveWords = []string{"key", "apikey", "privatekey", "accesskey"}
+
+// isSensitive checks if a key holds sensitive information: it contains one
+// of sensitivePatterns, has one of sensitiveWords as a separator-delimited
+// word, or ends in the word "auth" ("basic_auth", but not "auth_method")
+func isSensitive(key string) bool {
+	lower := strings.ToLower(key)
+	for _, sensitive := range sensitivePatterns {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		for _, sensitive := range sensitiveWords {
+			if word == sensitive {
+				return true
+			}
+		}
+	}
+	return len(words) > 0 && words[len(words)-1] == "auth"
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -22,6 +23,27 @@ type ValidationRule struct {
 	Custom   func(interface{}) error          // custom validation function
 }
 
+// ValidationErrors collects every failure from a validation pass
+type ValidationErrors []error
+
+// Error joins all failures into a single message
+func (ve ValidationErrors) Error() string {
+	if len(ve) == 1 {
+		return ve[0].Error()
+	}
+	
+	msgs := make([]string, len(ve))
+	for i, err := range ve {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d validation errors: %s", len(ve), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual failures for errors.Is and errors.As
+func (ve ValidationErrors) Unwrap() []error {
+	return ve
+}
+
 // NewValidator creates a new validator
 func NewValidator() *Validator {
 	return &Validator{
@@ -76,14 +98,25 @@ func (v *Validator) AddEmailRule(key string, required bool) {
 	}
 }
 
-// Validate validates a configuration map against defined rules
+// Validate validates a configuration map against defined rules. Dotted keys
+// are resolved through nested maps. All failures are returned together as a
+// ValidationErrors, ordered by key.
 func (v *Validator) Validate(config map[string]interface{}) error {
-	for key, rule := range v.rules {
-		value, exists := config[key]
+	keys := make([]string, 0, len(v.rules))
+	for key := range v.rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	
+	var errs ValidationErrors
+	for _, key := range keys {
+		rule := v.rules[key]
+		value, exists := lookupPath(config, key)
 		
 		// Check if required field is missing
 		if rule.Required && !exists {
-			return fmt.Errorf("required field missing: %s", key)
+			errs = append(errs, fmt.Errorf("required field missing: %s", key))
+			continue
 		}
 		
 		// Skip validation if field is not present and not required
@@ -93,10 +126,13 @@ func (v *Validator) Validate(config map[string]interface{}) error {
 		
 		// Validate based on type
 		if err := v.validateValue(key, value, rule); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 	
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
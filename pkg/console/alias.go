@@ -0,0 +1,217 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+)
+
+// maxAliasDepth bounds alias chains such as a -> b -> c
+const maxAliasDepth = 10
+
+// variablePattern matches ${key} and $key references to state values
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// SetAlias defines name as shorthand for the command line expansion. The
+// alias is saved next to the history file.
+func (c *Console) SetAlias(name, expansion string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t=$") {
+		return fmt.Errorf("invalid alias name: %q", name)
+	}
+	if isBuiltin(name) {
+		return fmt.Errorf("cannot alias built-in command: %s", name)
+	}
+	expansion = strings.TrimSpace(expansion)
+	if expansion == "" {
+		return fmt.Errorf("alias %s has no command", name)
+	}
+
+	c.loadAliases()
+	c.aliases[name] = expansion
+	if _, err := c.expandAliases(name); err != nil {
+		delete(c.aliases, name)
+		return err
+	}
+	return c.saveAliases()
+}
+
+// RemoveAlias deletes an alias
+func (c *Console) RemoveAlias(name string) error {
+	c.loadAliases()
+	if _, exists := c.aliases[name]; !exists {
+		return fmt.Errorf("no such alias: %s", name)
+	}
+	delete(c.aliases, name)
+	return c.saveAliases()
+}
+
+// Aliases returns a copy of the defined aliases
+func (c *Console) Aliases() map[string]string {
+	c.loadAliases()
+	aliases := make(map[string]string, len(c.aliases))
+	for name, expansion := range c.aliases {
+		aliases[name] = expansion
+	}
+	return aliases
+}
+
+// expandAliases replaces the first word of line while it names an alias.
+// An alias that leads back to itself is an error rather than a hang.
+func (c *Console) expandAliases(line string) (string, error) {
+	c.loadAliases()
+	seen := []string{}
+	for depth := 0; depth <= maxAliasDepth; depth++ {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return line, nil
+		}
+		expansion, exists := c.aliases[fields[0]]
+		if !exists {
+			return line, nil
+		}
+		for _, name := range seen {
+			if name == fields[0] {
+				return "", fmt.Errorf("alias loop: %s -> %s", strings.Join(seen, " -> "), fields[0])
+			}
+		}
+		seen = append(seen, fields[0])
+
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]))
+		line = strings.TrimSpace(expansion + " " + rest)
+	}
+	return "", fmt.Errorf("alias %s expands more than %d times", seen[0], maxAliasDepth)
+}
+
+// substituteVariables replaces ${key} and $key with values from the
+// console's state. References to unset keys are left as typed, so text such
+// as GraphQL variables passes through untouched.
+func (c *Console) substituteVariables(line string) string {
+	if c.state == nil || !strings.Contains(line, "$") {
+		return line
+	}
+	return variablePattern.ReplaceAllStringFunc(line, func(match string) string {
+		groups := variablePattern.FindStringSubmatch(match)
+		key := groups[1]
+		if key == "" {
+			key = groups[2]
+		}
+		value, exists := c.state.Get(key)
+		if !exists {
+			return match
+		}
+		return fmt.Sprintf("%v", value)
+	})
+}
+
+// aliasCommand handles the alias built-in: with no arguments it lists the
+// aliases, "alias name" shows one and "alias name=command ..." defines one
+func (c *Console) aliasCommand(line string) error {
+	definition := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "alias"))
+	if definition == "" {
+		aliases := c.Aliases()
+		if len(aliases) == 0 {
+			fmt.Println("No aliases defined. Usage: alias name=\"command args...\"")
+			return nil
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  alias %s=%q\n", name, aliases[name])
+		}
+		return nil
+	}
+
+	name, expansion, hasValue := strings.Cut(definition, "=")
+	if !hasValue {
+		expansion, exists := c.Aliases()[name]
+		if !exists {
+			return fmt.Errorf("no such alias: %s", name)
+		}
+		fmt.Printf("  alias %s=%q\n", name, expansion)
+		return nil
+	}
+
+	return c.SetAlias(name, unquote(strings.TrimSpace(expansion)))
+}
+
+// unquote strips one pair of matching surrounding quotes
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// aliasFile is where aliases are persisted, alongside the history file
+func (c *Console) aliasFile() string {
+	if c.HistoryFile == "" {
+		return ""
+	}
+	return c.HistoryFile + ".aliases"
+}
+
+// loadAliases reads saved aliases the first time they are needed
+func (c *Console) loadAliases() {
+	if c.aliases != nil {
+		return
+	}
+	c.aliases = make(map[string]string)
+
+	path := c.aliasFile()
+	if path == "" {
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if name, expansion, ok := strings.Cut(scanner.Text(), "="); ok && name != "" {
+			c.aliases[name] = expansion
+		}
+	}
+}
+
+// saveAliases writes the aliases as name=expansion lines
+func (c *Console) saveAliases() error {
+	path := c.aliasFile()
+	if path == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.aliases))
+	for name := range c.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%s\n", name, c.aliases[name])
+	}
+	if err := utils.WriteFileAtomic(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to save aliases: %w", err)
+	}
+	return nil
+}
+
+// isBuiltin reports whether name is handled by the console itself
+func isBuiltin(name string) bool {
+	switch strings.ToLower(name) {
+	case "exit", "quit", "help", "time", "alias", "unalias", "history":
+		return true
+	}
+	return false
+}
\ No newline at end of file
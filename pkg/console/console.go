@@ -1,12 +1,21 @@
 package console
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/jacobdavidalcock/consolekit/pkg/command"
+	"github.com/jacobdavidalcock/consolekit/pkg/config"
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
 )
 
@@ -17,16 +26,25 @@ type Console struct {
 	HistoryFile  string
 	Commands     *command.Registry
 	readline     *readline.Instance
+	timing       bool
+	shutdown     []func() error
+	state        *config.State     // source of ${key} substitutions
+	aliases      map[string]string // loaded on first use
+	history      []string          // lines run this session, see History
+	idleTimeout  time.Duration     // exit after this long at the prompt, see WithIdleTimeout
+	idle         *idleTimer        // active while the REPL runs with an idle timeout
 }
 
 // New creates a new Console instance
 func New(name string) *Console {
-	return &Console{
+	c := &Console{
 		Name:        name,
 		Prompt:      name + " > ",
 		HistoryFile: "/tmp/" + name + "_history.tmp",
 		Commands:    command.NewRegistry(),
 	}
+	c.Commands.SetHistory(c.History)
+	return c
 }
 
 // WithPrompt sets a custom prompt
@@ -35,17 +53,63 @@ func (c *Console) WithPrompt(prompt string) *Console {
 	return c
 }
 
+// WithPager sends output taller than the terminal, such as long Intel
+// explanations, through $PAGER (see output.Page)
+func (c *Console) WithPager(enabled bool) *Console {
+	output.PagerEnabled = enabled
+	return c
+}
+
 // WithHistoryFile sets a custom history file location
 func (c *Console) WithHistoryFile(file string) *Console {
 	c.HistoryFile = file
 	return c
 }
 
+// WithTiming enables printing how long each command took after it runs
+func (c *Console) WithTiming(enabled bool) *Console {
+	c.timing = enabled
+	return c
+}
+
 // AddCommand registers a new command
 func (c *Console) AddCommand(name string, handler command.Handler, description string) {
 	c.Commands.Register(name, handler, description)
 }
 
+// AddCommandWithArgs registers a command whose arguments are validated against spec
+func (c *Console) AddCommandWithArgs(name string, handler command.Handler, description string, spec command.ArgSpec) {
+	c.Commands.RegisterWithArgs(name, handler, description, spec)
+}
+
+// AddContextCommand registers a command whose flags are parsed before it runs
+func (c *Console) AddContextCommand(name string, handler command.ContextHandler, description string, flags *flag.FlagSet) {
+	c.Commands.RegisterContext(name, handler, description, flags)
+}
+
+// AddResultCommand registers a command that returns a CommandResult, which
+// is rendered as text, a table or JSON depending on the output mode
+func (c *Console) AddResultCommand(name string, handler command.ResultHandler, description string, flags *flag.FlagSet) {
+	c.Commands.RegisterResult(name, handler, description, flags)
+}
+
+// SetState sets the state passed to context commands
+func (c *Console) SetState(state *config.State) {
+	c.state = state
+	c.Commands.SetState(state)
+}
+
+// State returns the state set with SetState, or nil
+func (c *Console) State() *config.State {
+	return c.state
+}
+
+// SetOutputMode sets the session-wide output mode. In output.ModeJSON,
+// commands that support it print JSON as if --json had been passed.
+func (c *Console) SetOutputMode(mode output.Mode) {
+	output.CurrentMode = mode
+}
+
 // AddCommandWithCompletion registers a command with custom completion
 func (c *Console) AddCommandWithCompletion(name string, handler command.Handler, description string, completions map[int]command.ArgumentCompletion) {
 	c.Commands.RegisterWithCompletion(name, handler, description, completions)
@@ -87,13 +151,56 @@ func (c *Console) EnableIntel(intel interface{}) {
 	}
 }
 
-// SetBanner displays a startup banner
+// SetBanner displays a startup banner. It is skipped when stdin is piped.
 func (c *Console) SetBanner(banner string) {
+	if !output.IsTerminal(os.Stdin) {
+		return
+	}
 	fmt.Println(output.Cyan(banner))
 }
 
+// OnShutdown registers a cleanup handler to run when the console stops.
+// Handlers run in reverse registration order; a failing handler doesn't
+// prevent the others from running.
+func (c *Console) OnShutdown(fn func() error) {
+	c.shutdown = append(c.shutdown, fn)
+}
+
+// runShutdownHandlers runs registered handlers LIFO and joins their errors
+func (c *Console) runShutdownHandlers() error {
+	handlers := c.shutdown
+	c.shutdown = nil
+
+	var errs []error
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if err := handlers[i](); err != nil {
+			fmt.Printf("❌ shutdown: %s\n", err.Error())
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Run starts the interactive console REPL
 func (c *Console) Run() error {
+	return c.RunContext(context.Background())
+}
+
+// RunContext starts the interactive console REPL and stops it when ctx is
+// cancelled. Shutdown handlers run however the loop ends, and their errors
+// are returned.
+func (c *Console) RunContext(ctx context.Context) (err error) {
+	defer func() {
+		if shutdownErr := c.runShutdownHandlers(); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}()
+
+	// Piped input (echo "scan" | mytool) runs non-interactively
+	if !output.IsTerminal(os.Stdin) {
+		return c.runScript(ctx, os.Stdin)
+	}
+
 	// Create completer from registered commands
 	completer := c.Commands.BuildCompleter()
 
@@ -113,49 +220,401 @@ func (c *Console) Run() error {
 	defer rl.Close()
 
 	c.readline = rl
+	defer func() { c.readline = nil }()
+
+	// The listener is attached after creation since the timer closes rl
+	if c.idle = c.watchIdle(rl); c.idle != nil {
+		rl.Config.Listener = c.idle.listener()
+		defer func() {
+			c.idle.pause()
+			c.idle = nil
+		}()
+	}
+
+	// Prompts from commands (output.Confirm, output.ReadSecret) read
+	// through readline too
+	output.SetInputReader(c.readAnswer)
+	output.SetSecretReader(c.readSecret)
+	defer output.SetInputReader(nil)
+	defer output.SetSecretReader(nil)
+
+	// Closing readline unblocks the pending Readline call on cancellation
+	stop := context.AfterFunc(ctx, func() { rl.Close() })
+	defer stop()
+
+	terminated := c.handleSignals(rl)
+	defer terminated.stop()
 
 	// Main REPL loop (extracted from firescan)
 	for {
 		line, err := rl.Readline()
-		if err == readline.ErrInterrupt || err == io.EOF {
+		c.idle.pause()
+		if c.idle.timedOut() {
+			fmt.Printf("\n%sSession idle for %s; exiting.%s\n", output.YellowColor, c.idleTimeout, output.Reset)
+			break
+		}
+		if err == readline.ErrInterrupt || err == io.EOF || ctx.Err() != nil || terminated.received() {
 			break
 		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
 
-		input := strings.Fields(line)
-		if len(input) == 0 {
-			continue
+		if c.executeLine(line) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// signalWatcher redraws the prompt when the terminal is resized and closes
+// readline on SIGTERM or SIGHUP so RunContext returns through its normal
+// path, saving history and running the shutdown handlers
+type signalWatcher struct {
+	signals chan os.Signal
+	done    chan struct{}
+	caught  atomic.Bool
+}
+
+// handleSignals starts watching signals for the REPL until stop is called
+func (c *Console) handleSignals(rl *readline.Instance) *signalWatcher {
+	w := &signalWatcher{
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}
+	watched := append([]os.Signal{}, terminationSignals...)
+	if resizeSignal != nil {
+		watched = append(watched, resizeSignal)
+	}
+	signal.Notify(w.signals, watched...)
+
+	go func() {
+		for {
+			select {
+			case <-w.done:
+				return
+			case sig := <-w.signals:
+				if sig == resizeSignal {
+					rl.Refresh()
+					continue
+				}
+				w.caught.Store(true)
+				rl.Close()
+				return
+			}
 		}
+	}()
+	return w
+}
+
+// received reports whether a termination signal arrived
+func (w *signalWatcher) received() bool {
+	return w.caught.Load()
+}
 
-		commandName := input[0]
-		args := input[1:]
+// stop restores the default signal handling
+func (w *signalWatcher) stop() {
+	signal.Stop(w.signals)
+	close(w.done)
+}
+
+// RunScript executes commands read line by line from r without prompting.
+// Blank lines and lines starting with '#' are skipped. Command errors are
+// reported and execution continues; exit or quit stops early. Shutdown
+// handlers run when the script ends.
+func (c *Console) RunScript(r io.Reader) (err error) {
+	defer func() {
+		if shutdownErr := c.runShutdownHandlers(); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}()
+	return c.runScript(context.Background(), r)
+}
 
-		// Handle built-in commands
-		switch strings.ToLower(commandName) {
-		case "exit", "quit":
+// runScript is the non-interactive command loop shared by RunScript and piped stdin
+func (c *Console) runScript(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
 			return nil
-		case "help":
-			c.showHelp()
-			continue
 		}
 
-		// Execute registered command
-		if err := c.Commands.Execute(commandName, args); err != nil {
-			fmt.Printf("❌ %s\n", err.Error())
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if c.executeLine(line) {
+			return nil
 		}
 	}
 
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
 	return nil
 }
 
+// executeLine runs one line of input, reporting any error. It returns true
+// when the console should exit.
+func (c *Console) executeLine(line string) bool {
+	exit, err := c.dispatch(line)
+	if err != nil {
+		fmt.Printf("❌ %s\n", err.Error())
+	}
+	return exit
+}
+
+// ExecuteCapture runs one line of input as if it had been typed at the
+// prompt and returns everything the command printed along with its error.
+// Aliases, variables and built-ins behave as in the REPL. It needs no
+// terminal, which makes it the seam for testing handlers:
+//
+//	out, err := app.ExecuteCapture("scan example.com --threads 5")
+func (c *Console) ExecuteCapture(line string) (string, error) {
+	// Recall history first so a recalled pipe takes the pipe path below;
+	// the recalled line is echoed as dispatch does in the REPL
+	echo := ""
+	if strings.HasPrefix(strings.TrimSpace(line), "!") {
+		recalled, err := c.recall(line)
+		if err != nil {
+			return "", err
+		}
+		line, echo = recalled, recalled+"\n"
+	}
+
+	// Captures can't nest, so a pipe captures its left side first and only
+	// the right side's output is returned
+	if left, right, piped := pipeLine(line); piped {
+		c.recordHistory(line)
+		captured, sink, err := c.pipeSource(left, right)
+		if err != nil {
+			return echo + captured, err
+		}
+		out, err := output.CaptureStdout(func() error {
+			return c.Commands.ExecuteInput(sink[0], sink[1:], strings.NewReader(captured))
+		})
+		return echo + out, err
+	}
+
+	out, err := output.CaptureStdout(func() error {
+		_, err := c.dispatch(line)
+		return err
+	})
+	return echo + out, err
+}
+
+// dispatch expands aliases and variables in line and runs the command or
+// built-in it names. exit is true for exit and quit.
+func (c *Console) dispatch(line string) (exit bool, err error) {
+	if strings.HasPrefix(strings.TrimSpace(line), "!") {
+		if line, err = c.recall(line); err != nil {
+			return false, err
+		}
+		fmt.Println(line)
+	}
+
+	input := strings.Fields(line)
+	if len(input) == 0 {
+		return false, nil
+	}
+	c.recordHistory(line)
+
+	// alias definitions keep their $variables for when the alias is used
+	switch strings.ToLower(input[0]) {
+	case "alias":
+		return false, c.aliasCommand(line)
+	case "unalias":
+		if len(input) != 2 {
+			return false, fmt.Errorf("usage: unalias <name>")
+		}
+		return false, c.RemoveAlias(input[1])
+	}
+
+	if left, right, piped := pipeLine(line); piped {
+		return false, c.runPipe(left, right)
+	}
+
+	input, err = c.expand(line)
+	if err != nil || len(input) == 0 {
+		return false, err
+	}
+
+	commandName := input[0]
+	args := input[1:]
+
+	// Handle built-in commands
+	switch strings.ToLower(commandName) {
+	case "exit", "quit":
+		return true, nil
+	case "help":
+		return false, c.helpCommand(args)
+	case "history":
+		return false, c.historyCommand(args)
+	case "time":
+		if len(args) == 0 {
+			fmt.Println("Usage: time <command> [args...]")
+			return false, nil
+		}
+		return false, c.runCommand(args[0], args[1:], true)
+	}
+
+	return false, c.runCommand(commandName, args, c.timing)
+}
+
+// expand applies aliases and variables to line and splits it into words.
+// Quoted text stays one word, so --header "Content-Type: application/json"
+// reaches the handler as two arguments.
+func (c *Console) expand(line string) ([]string, error) {
+	expanded, err := c.expandAliases(line)
+	if err != nil {
+		return nil, err
+	}
+	return lineWords(c.substituteVariables(expanded)), nil
+}
+
+// runCommand executes a registered command and, if requested, reports how
+// long the handler took
+func (c *Console) runCommand(name string, args []string, timed bool) error {
+	start := time.Now()
+	err := c.Commands.Execute(name, args)
+	elapsed := time.Since(start)
+
+	if timed {
+		fmt.Println(output.Colorize(fmt.Sprintf("(%s)", formatElapsed(elapsed)), output.DimColor))
+	}
+	return err
+}
+
+// formatElapsed formats a command duration compactly, e.g. 340ms or 1.2s
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
 // showHelp displays help for all registered commands
 func (c *Console) showHelp() {
 	fmt.Printf("\n--- %s Help Menu ---\n", c.Name)
 	c.Commands.ShowHelp()
-	fmt.Println("  exit / quit           Close the application.")
-	fmt.Println("  help                  Display this help menu.")
+	for _, builtin := range builtinHelp {
+		fmt.Printf("  %-21s %s\n", builtin.usage, builtin.description)
+	}
 	fmt.Println("------------------------")
 }
 
+// builtinHelp describes the commands handled by the console itself
+var builtinHelp = []struct {
+	names       []string
+	usage       string
+	description string
+}{
+	{[]string{"time"}, "time <command>", "Run a command and report how long it took."},
+	{[]string{"alias"}, "alias [name=command]", "List aliases or define a shortcut."},
+	{[]string{"unalias"}, "unalias <name>", "Remove an alias."},
+	{[]string{"history"}, "history [n]", "List this session's commands; !<n> runs one again."},
+	{nil, "<cmd> | <cmd>", "Feed one command's output to another."},
+	{[]string{"exit", "quit"}, "exit / quit", "Close the application."},
+	{[]string{"help"}, "help [command]", "Display this help menu, or details for one command."},
+}
+
+// helpCommand shows the help menu, or detailed help for the command, alias
+// or built-in named in args
+func (c *Console) helpCommand(args []string) error {
+	if len(args) == 0 {
+		c.showHelp()
+		return nil
+	}
+
+	name := strings.ToLower(args[0])
+	for _, builtin := range builtinHelp {
+		for _, builtinName := range builtin.names {
+			if name == builtinName {
+				fmt.Printf("\n%s - %s\n\nUsage: %s\n", builtinName, builtin.description, builtin.usage)
+				return nil
+			}
+		}
+	}
+
+	if expansion, ok := c.Aliases()[args[0]]; ok {
+		fmt.Printf("\n%s is an alias for '%s'\n", args[0], expansion)
+		fields := strings.Fields(expansion)
+		if _, exists := c.Commands.GetCommand(fields[0]); !exists {
+			return nil
+		}
+		name = fields[0]
+	}
+	return c.Commands.ShowCommandHelp(name)
+}
+
+// readAnswer reads a reply to a command's question using the REPL's
+// readline instance, keeping it out of the command history
+func (c *Console) readAnswer(prompt string) (string, error) {
+	rl := c.readline
+	if rl == nil {
+		return "", io.EOF
+	}
+
+	rl.SetPrompt(prompt)
+	rl.HistoryDisable()
+	defer func() {
+		rl.HistoryEnable()
+		rl.SetPrompt(c.Prompt)
+	}()
+	
+	// The prompt restarts the idle timer; stop it again once answered
+	defer c.idle.pause()
+	return rl.Readline()
+}
+
+// readSecret reads a reply without echo using the REPL's readline instance,
+// which restores the terminal if the user presses Ctrl-C
+func (c *Console) readSecret(prompt string) (string, error) {
+	rl := c.readline
+	if rl == nil {
+		return "", io.EOF
+	}
+
+	secret, err := rl.ReadPassword(prompt)
+	if err == readline.ErrInterrupt {
+		return "", fmt.Errorf("interrupted")
+	}
+	return string(secret), err
+}
+
+// Stdout returns a writer for output produced while the REPL is running.
+// Writes go through readline so they don't clobber the input line; before
+// Run starts or after it exits they go straight to os.Stdout.
+func (c *Console) Stdout() io.Writer {
+	return consoleWriter{console: c}
+}
+
+// consoleWriter resolves the readline output at write time
+type consoleWriter struct {
+	console *Console
+}
+
+// Write sends p to readline's stdout if active, otherwise (or while output
+// is being captured) to os.Stdout. Escape sequences are stripped when colors
+// are disabled, catching code that prints the raw color constants.
+func (w consoleWriter) Write(p []byte) (int, error) {
+	var target io.Writer = os.Stdout
+	if rl := w.console.readline; rl != nil && !output.Capturing() {
+		target = rl.Stdout()
+	}
+	if !output.ColorEnabled {
+		target = output.StripANSIWriter(target)
+	}
+	return target.Write(p)
+}
+
 // Close gracefully shuts down the console
 func (c *Console) Close() error {
 	if c.readline != nil {
@@ -0,0 +1,98 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/command"
+)
+
+func newPipeConsole() *Console {
+	c := New("capture-test")
+	c.Commands.RegisterContext("say", command.ContextHandlerFunc(func(ctx *command.CommandContext) error {
+		fmt.Println(strings.Join(ctx.Args, " "))
+		return nil
+	}), "Print the arguments", nil)
+	c.Commands.RegisterContext("upper", command.ContextHandlerFunc(func(ctx *command.CommandContext) error {
+		if ctx.Stdin == nil {
+			return fmt.Errorf("nothing piped in")
+		}
+		data, err := io.ReadAll(ctx.Stdin)
+		if err != nil {
+			return err
+		}
+		fmt.Print(strings.ToUpper(string(data)))
+		return nil
+	}), "Upper-case piped input", nil)
+	return c
+}
+
+// captureWithin runs ExecuteCapture and fails the test if it doesn't return
+func captureWithin(t *testing.T, c *Console, line string) (string, error) {
+	t.Helper()
+	type result struct {
+		out string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		out, err := c.ExecuteCapture(line)
+		done <- result{out, err}
+	}()
+	select {
+	case r := <-done:
+		return r.out, r.err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ExecuteCapture(%q) did not return", line)
+		return "", nil
+	}
+}
+
+func TestExecuteCapturePipe(t *testing.T) {
+	c := newPipeConsole()
+	out, err := captureWithin(t, c, "say hello | upper")
+	if err != nil {
+		t.Fatalf("ExecuteCapture: %v", err)
+	}
+	if out != "HELLO\n" {
+		t.Errorf("output = %q, want %q", out, "HELLO\n")
+	}
+}
+
+func TestExecuteCaptureRecalledPipe(t *testing.T) {
+	c := newPipeConsole()
+	if _, err := captureWithin(t, c, "say hello | upper"); err != nil {
+		t.Fatalf("ExecuteCapture: %v", err)
+	}
+
+	for _, line := range []string{"!1", "!!"} {
+		out, err := captureWithin(t, c, line)
+		if err != nil {
+			t.Fatalf("ExecuteCapture(%q): %v", line, err)
+		}
+		if want := "say hello | upper\nHELLO\n"; out != want {
+			t.Errorf("ExecuteCapture(%q) = %q, want %q", line, out, want)
+		}
+	}
+
+	if _, err := captureWithin(t, c, "!9"); err == nil {
+		t.Error("ExecuteCapture(!9) succeeded, want event not found")
+	}
+}
+
+func TestExecuteCaptureRecall(t *testing.T) {
+	c := newPipeConsole()
+	if _, err := captureWithin(t, c, "say hi"); err != nil {
+		t.Fatalf("ExecuteCapture: %v", err)
+	}
+	out, err := captureWithin(t, c, "!1 there")
+	if err != nil {
+		t.Fatalf("ExecuteCapture: %v", err)
+	}
+	if want := "say hi there\nhi there\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
\ No newline at end of file
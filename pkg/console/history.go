@@ -0,0 +1,71 @@
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxSessionHistory bounds the in-session history kept for History and !n
+const maxSessionHistory = 1000
+
+// History returns the command lines run this session, oldest first. Lines
+// recalled with !n are recorded as the command they ran.
+func (c *Console) History() []string {
+	history := make([]string, len(c.history))
+	copy(history, c.history)
+	return history
+}
+
+// recordHistory appends a line to the session history
+func (c *Console) recordHistory(line string) {
+	c.history = append(c.history, strings.TrimSpace(line))
+	if len(c.history) > maxSessionHistory {
+		c.history = c.history[len(c.history)-maxSessionHistory:]
+	}
+}
+
+// recall expands "!n" to the nth line of history (1-based, as numbered by
+// the history command) and "!!" to the previous line. Text after the
+// reference is appended, so "!3 --verbose" adds a flag.
+func (c *Console) recall(line string) (string, error) {
+	reference, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+
+	var recalled string
+	if reference == "!!" {
+		if len(c.history) == 0 {
+			return "", fmt.Errorf("no previous command")
+		}
+		recalled = c.history[len(c.history)-1]
+	} else {
+		n, err := strconv.Atoi(strings.TrimPrefix(reference, "!"))
+		if err != nil || n < 1 || n > len(c.history) {
+			return "", fmt.Errorf("%s: event not found", reference)
+		}
+		recalled = c.history[n-1]
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" {
+		recalled += " " + rest
+	}
+	return recalled, nil
+}
+
+// historyCommand handles the history built-in: all lines, or the last n
+func (c *Console) historyCommand(args []string) error {
+	start := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return fmt.Errorf("usage: history [n]")
+		}
+		if n < len(c.history) {
+			start = len(c.history) - n
+		}
+	}
+
+	for i := start; i < len(c.history); i++ {
+		fmt.Printf("  %4d  %s\n", i+1, c.history[i])
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+package console
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/chzyer/readline"
+)
+
+// WithIdleTimeout makes the REPL exit after the user has been idle at the
+// prompt for d, running the shutdown handlers as on a normal exit. Any key
+// press counts as activity, and time spent running a command is never
+// counted. 0 disables the timeout.
+func (c *Console) WithIdleTimeout(d time.Duration) *Console {
+	if d < 0 {
+		d = 0
+	}
+	c.idleTimeout = d
+	return c
+}
+
+// idleTimer closes readline when the prompt has waited too long for input,
+// which unblocks Readline so RunContext returns through its normal path
+type idleTimer struct {
+	timeout time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// watchIdle starts the idle timer for rl, or returns nil when no timeout is
+// configured. The returned timer is paused; readline's listener starts it
+// whenever a prompt is shown.
+func (c *Console) watchIdle(rl *readline.Instance) *idleTimer {
+	if c.idleTimeout <= 0 {
+		return nil
+	}
+
+	t := &idleTimer{timeout: c.idleTimeout}
+	t.timer = time.AfterFunc(t.timeout, func() {
+		t.expired.Store(true)
+		rl.Close()
+	})
+	t.timer.Stop()
+	return t
+}
+
+// listener restarts the timer on every key press and when a prompt opens
+func (t *idleTimer) listener() readline.Listener {
+	return readline.FuncListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		t.touch()
+		return nil, 0, false
+	})
+}
+
+// touch restarts the countdown
+func (t *idleTimer) touch() {
+	if t != nil && !t.expired.Load() {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// pause stops the countdown while a command runs
+func (t *idleTimer) pause() {
+	if t != nil {
+		t.timer.Stop()
+	}
+}
+
+// timedOut reports whether the timer closed readline
+func (t *idleTimer) timedOut() bool {
+	return t != nil && t.expired.Load()
+}
\ No newline at end of file
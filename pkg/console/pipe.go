@@ -0,0 +1,70 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+// pipeLine splits line at a standalone "|". A pipe inside a word, such as
+// the regex argument "admin|root", or inside quotes is left alone, as are
+// alias definitions. Both sides keep their quotes.
+func pipeLine(line string) (left, right string, ok bool) {
+	words := splitWords(line)
+	if len(words) > 0 && (words[0].raw == "alias" || words[0].raw == "unalias") {
+		return "", "", false
+	}
+	for i, w := range words {
+		if w.raw == "|" {
+			return joinRaw(words[:i]), joinRaw(words[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// joinRaw joins words as they were typed
+func joinRaw(words []word) string {
+	raw := make([]string, len(words))
+	for i, w := range words {
+		raw[i] = w.raw
+	}
+	return strings.Join(raw, " ")
+}
+
+// runPipe runs left with its output captured and hands that output to right
+// as CommandContext.Stdin. If left fails, whatever it printed is shown and
+// right doesn't run.
+func (c *Console) runPipe(left, right string) error {
+	captured, sink, err := c.pipeSource(left, right)
+	if err != nil {
+		fmt.Print(captured)
+		return err
+	}
+	return c.Commands.ExecuteInput(sink[0], sink[1:], strings.NewReader(captured))
+}
+
+// pipeSource expands aliases and variables on both sides of a pipe, runs
+// the left command and returns its captured output with the right
+// command's words
+func (c *Console) pipeSource(left, right string) (captured string, sink []string, err error) {
+	if _, _, again := pipeLine(right); again {
+		return "", nil, fmt.Errorf("only a single pipe is supported")
+	}
+
+	source, err := c.expand(left)
+	if err != nil {
+		return "", nil, err
+	}
+	if sink, err = c.expand(right); err != nil {
+		return "", nil, err
+	}
+	if len(source) == 0 || len(sink) == 0 {
+		return "", nil, fmt.Errorf("usage: <command> | <command>")
+	}
+
+	captured, err = output.CaptureStdout(func() error {
+		return c.Commands.Execute(source[0], source[1:])
+	})
+	return captured, sink, err
+}
\ No newline at end of file
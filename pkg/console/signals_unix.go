@@ -0,0 +1,14 @@
+//go:build !windows
+
+package console
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignal is delivered when the terminal window changes size
+var resizeSignal os.Signal = syscall.SIGWINCH
+
+// terminationSignals end the session gracefully
+var terminationSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
\ No newline at end of file
@@ -0,0 +1,14 @@
+//go:build windows
+
+package console
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignal is nil on Windows, which has no resize signal
+var resizeSignal os.Signal
+
+// terminationSignals end the session gracefully
+var terminationSignals = []os.Signal{syscall.SIGTERM}
\ No newline at end of file
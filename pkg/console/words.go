@@ -0,0 +1,86 @@
+package console
+
+import "strings"
+
+// word is one word of a command line: text with quotes removed and raw as
+// typed
+type word struct {
+	text string
+	raw  string
+}
+
+// splitWords splits line at whitespace outside quotes, so
+// --header "Content-Type: application/json" is two words. A quote opens
+// only at the start of a word or after '=', which keeps apostrophes in
+// text such as "what's" literal, and a quote that is never closed is kept
+// as typed. Inside double quotes \" and \\ are escapes; single quotes keep
+// everything literally.
+func splitWords(line string) []word {
+	var words []word
+	var text, raw strings.Builder
+	inWord := false
+
+	flush := func() {
+		if inWord {
+			words = append(words, word{text: text.String(), raw: raw.String()})
+		}
+		text.Reset()
+		raw.Reset()
+		inWord = false
+	}
+
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			flush()
+			continue
+		case (ch == '"' || ch == '\'') && (!inWord || strings.HasSuffix(raw.String(), "=")):
+			if end := closingQuote(line, i); end > 0 {
+				text.WriteString(unescape(line[i+1:end], ch))
+				raw.WriteString(line[i : end+1])
+				inWord = true
+				i = end
+				continue
+			}
+		}
+		text.WriteByte(ch)
+		raw.WriteByte(ch)
+		inWord = true
+	}
+	flush()
+	return words
+}
+
+// closingQuote returns the index of the quote closing the one at start, or
+// -1 if there is none
+func closingQuote(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case quote == '"' && line[i] == '\\' && i+1 < len(line):
+			i++
+		case line[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape resolves the escapes allowed inside a quoted string
+func unescape(s string, quote byte) string {
+	if quote == '\'' {
+		return s
+	}
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s)
+}
+
+// lineWords returns the words of line with quotes removed
+func lineWords(line string) []string {
+	words := splitWords(line)
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.text
+	}
+	return texts
+}
\ No newline at end of file
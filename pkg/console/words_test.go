@@ -0,0 +1,67 @@
+package console
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineWords(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"scan example.com  --threads 5", []string{"scan", "example.com", "--threads", "5"}},
+		{`http POST /api --header "Content-Type: application/json"`, []string{"http", "POST", "/api", "--header", "Content-Type: application/json"}},
+		{`http --header 'Authorization: Bearer abc def'`, []string{"http", "--header", "Authorization: Bearer abc def"}},
+		{`http --header="X-Name: a b"`, []string{"http", "--header=X-Name: a b"}},
+		{`post --data "{\"name\": \"x\"}"`, []string{"post", "--data", `{"name": "x"}`}},
+		{`say 'single "double" inside'`, []string{"say", `single "double" inside`}},
+		{`intel explain what's xss`, []string{"intel", "explain", "what's", "xss"}},
+		{`say "unterminated quote`, []string{"say", `"unterminated`, "quote"}},
+		{`say ""`, []string{"say", ""}},
+		{`open C:\temp\file.txt`, []string{"open", `C:\temp\file.txt`}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := lineWords(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lineWords(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestPipeLineIgnoresQuotedPipe(t *testing.T) {
+	if _, _, piped := pipeLine(`grep "admin | root"`); piped {
+		t.Error(`pipeLine split at a quoted "|"`)
+	}
+
+	left, right, piped := pipeLine(`say "a | b" | upper`)
+	if !piped || left != `say "a | b"` || right != "upper" {
+		t.Errorf("pipeLine = %q, %q, %v", left, right, piped)
+	}
+}
+
+func TestExecuteCaptureQuotedArguments(t *testing.T) {
+	c := newPipeConsole()
+	c.Commands.RegisterFunc("args", func(args []string) error {
+		fmt.Println(strings.Join(args, "|"))
+		return nil
+	}, "Print the arguments separated by |")
+
+	out, err := captureWithin(t, c, `args --header "Content-Type: application/json" --data '{}'`)
+	if err != nil {
+		t.Fatalf("ExecuteCapture: %v", err)
+	}
+	if want := "--header|Content-Type: application/json|--data|{}\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	out, err = captureWithin(t, c, `say "a | b" | upper`)
+	if err != nil {
+		t.Fatalf("ExecuteCapture: %v", err)
+	}
+	if out != "A | B\n" {
+		t.Errorf("piped output = %q, want %q", out, "A | B\n")
+	}
+}
\ No newline at end of file
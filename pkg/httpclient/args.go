@@ -0,0 +1,116 @@
+package httpclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// methods accepted as the first positional argument
+var methods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true,
+	"PATCH": true, "HEAD": true, "OPTIONS": true, "TRACE": true,
+}
+
+// Invocation is a request and client configuration parsed from command
+// arguments, plus the output format the user asked for
+type Invocation struct {
+	Request Request
+	Config  Config
+	Output  string
+}
+
+// ParseArgs parses the arguments the command.Quick HTTPClient completion
+// suggests:
+//
+//	[METHOD] <url> [--method M] [--header "K: V"]... [--data BODY]
+//	[--timeout SECONDS] [--follow-redirects BOOL] [--verify-ssl BOOL]
+//	[--proxy URL] [--retries N] [--output FORMAT]
+//
+// Settings not given on the command line come from base. A header value
+// with spaces is one argument when quoted at the console prompt.
+func ParseArgs(args []string, base Config) (*Invocation, error) {
+	inv := &Invocation{Config: base, Request: Request{Method: "GET", Headers: map[string]string{}}}
+
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--method":
+			inv.Request.Method = strings.ToUpper(value)
+		case "--header":
+			key, headerValue, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q (use \"Name: value\")", value)
+			}
+			inv.Request.Headers[strings.TrimSpace(key)] = strings.TrimSpace(headerValue)
+		case "--data":
+			inv.Request.Body = []byte(value)
+		case "--timeout":
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds < 0 {
+				return nil, fmt.Errorf("invalid timeout: %s", value)
+			}
+			inv.Config.Timeout = time.Duration(seconds * float64(time.Second))
+		case "--follow-redirects":
+			follow, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for --follow-redirects: %s", value)
+			}
+			inv.Config.FollowRedirects = follow
+		case "--verify-ssl":
+			verify, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for --verify-ssl: %s", value)
+			}
+			inv.Config.VerifyTLS = verify
+		case "--proxy":
+			inv.Config.Proxy = value
+		case "--retries":
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return nil, fmt.Errorf("invalid retries: %s", value)
+			}
+			inv.Config.Retries = retries
+		case "--output":
+			inv.Output = value
+		default:
+			return nil, fmt.Errorf("unknown flag: %s", name)
+		}
+	}
+
+	if len(positional) > 0 && methods[strings.ToUpper(positional[0])] {
+		inv.Request.Method = strings.ToUpper(positional[0])
+		positional = positional[1:]
+	}
+	switch len(positional) {
+	case 0:
+		return nil, fmt.Errorf("missing URL")
+	case 1:
+		inv.Request.URL = positional[0]
+	default:
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(positional[1:], " "))
+	}
+	if !strings.Contains(inv.Request.URL, "://") {
+		inv.Request.URL = "http://" + inv.Request.URL
+	}
+	if inv.Request.Body != nil && inv.Request.Headers["Content-Type"] == "" {
+		inv.Request.Headers["Content-Type"] = "application/json"
+	}
+	return inv, nil
+}
\ No newline at end of file
@@ -0,0 +1,273 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultUserAgent is sent when neither the config nor the request sets one
+const DefaultUserAgent = "consolekit/1.0"
+
+// Config controls how a Client sends requests
+type Config struct {
+	Timeout         time.Duration     // per attempt; 0 means no timeout
+	Proxy           string            // http://, https:// or socks5:// URL; empty uses the environment
+	VerifyTLS       bool              // false accepts any certificate
+	Headers         map[string]string // sent with every request
+	UserAgent       string
+	FollowRedirects bool
+	MaxRedirects    int           // 0 uses the net/http limit of 10
+	Retries         int           // extra attempts after network errors, 429 and 5xx
+	RetryDelay      time.Duration // grows linearly with each attempt
+	MaxBodySize     int64         // bytes of the body to keep; 0 keeps everything
+}
+
+// DefaultConfig returns the settings tools usually want: a 30 second
+// timeout, certificate checks on, redirects followed and no retries
+func DefaultConfig() Config {
+	return Config{
+		Timeout:         30 * time.Second,
+		VerifyTLS:       true,
+		FollowRedirects: true,
+		RetryDelay:      time.Second,
+		MaxBodySize:     10 << 20,
+	}
+}
+
+// Client sends HTTP requests with a shared configuration. It is safe for
+// concurrent use.
+type Client struct {
+	config Config
+	http   *http.Client
+}
+
+// New creates a client from config. It fails if the proxy URL is invalid.
+func New(config Config) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: !config.VerifyTLS}
+
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", config.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   config.Timeout,
+	}
+	switch {
+	case !config.FollowRedirects:
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	case config.MaxRedirects > 0:
+		limit := config.MaxRedirects
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > limit {
+				return fmt.Errorf("stopped after %d redirects", limit)
+			}
+			return nil
+		}
+	}
+
+	return &Client{config: config, http: client}, nil
+}
+
+// Config returns the client's configuration
+func (c *Client) Config() Config {
+	return c.config
+}
+
+// Request describes a single request. Body is a byte slice so it can be
+// resent when the request is retried.
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// Response holds everything a tool usually inspects about a response. The
+// body has already been read and closed.
+type Response struct {
+	Method     string
+	URL        string // final URL after redirects
+	StatusCode int
+	Status     string
+	Headers    http.Header
+	Body       []byte
+	Truncated  bool // the body was longer than Config.MaxBodySize
+	Duration   time.Duration
+	Attempts   int
+}
+
+// Get sends a GET request
+func (c *Client) Get(ctx context.Context, url string) (*Response, error) {
+	return c.Do(ctx, Request{Method: http.MethodGet, URL: url})
+}
+
+// Post sends a POST request with the given content type
+func (c *Client) Post(ctx context.Context, url, contentType string, body []byte) (*Response, error) {
+	return c.Do(ctx, Request{
+		Method:  http.MethodPost,
+		URL:     url,
+		Headers: map[string]string{"Content-Type": contentType},
+		Body:    body,
+	})
+}
+
+// Do sends req, retrying network errors, 429 and 5xx responses up to
+// Config.Retries times. The last response is returned even when its status
+// would have been retried.
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= c.config.Retries+1; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(c.config.RetryDelay * time.Duration(attempt-1)):
+			}
+		}
+
+		resp, err := c.send(ctx, method, req)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+		resp.Attempts = attempt
+		if !retryable(resp.StatusCode) || attempt > c.config.Retries {
+			return resp, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// send performs one attempt
+func (c *Client) send(ctx context.Context, method string, req Request) (*Response, error) {
+	var body io.Reader
+	if req.Body != nil {
+		body = bytes.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, req.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	userAgent := c.config.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	httpReq.Header.Set("User-Agent", userAgent)
+	for key, value := range c.config.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	if host := httpReq.Header.Get("Host"); host != "" {
+		httpReq.Host = host
+	}
+
+	start := time.Now()
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, req.URL, err)
+	}
+	defer httpResp.Body.Close()
+
+	reader := io.Reader(httpResp.Body)
+	if c.config.MaxBodySize > 0 {
+		reader = io.LimitReader(httpResp.Body, c.config.MaxBodySize+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	resp := &Response{
+		Method:     method,
+		URL:        httpResp.Request.URL.String(),
+		StatusCode: httpResp.StatusCode,
+		Status:     httpResp.Status,
+		Headers:    httpResp.Header,
+		Body:       data,
+		Duration:   time.Since(start),
+	}
+	if c.config.MaxBodySize > 0 && int64(len(data)) > c.config.MaxBodySize {
+		resp.Body = data[:c.config.MaxBodySize]
+		resp.Truncated = true
+	}
+	return resp, nil
+}
+
+// retryable reports whether a status is worth another attempt
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// Text returns the body as a string
+func (r *Response) Text() string {
+	return string(r.Body)
+}
+
+// JSON decodes the body into v
+func (r *Response) JSON(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", r.URL, err)
+	}
+	return nil
+}
+
+// OK reports whether the status is 2xx
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Evidence summarizes the response for a finding's Evidence map: method,
+// URL, status, timing, the response headers and up to 2KB of the body
+func (r *Response) Evidence() map[string]interface{} {
+	headers := make(map[string]string, len(r.Headers))
+	for key := range r.Headers {
+		headers[key] = r.Headers.Get(key)
+	}
+
+	body := r.Body
+	truncated := r.Truncated
+	if len(body) > 2048 {
+		body = body[:2048]
+		truncated = true
+	}
+
+	return map[string]interface{}{
+		"method":         r.Method,
+		"url":            r.URL,
+		"status":         r.StatusCode,
+		"duration_ms":    r.Duration.Milliseconds(),
+		"headers":        headers,
+		"body":           string(body),
+		"body_truncated": truncated,
+	}
+}
\ No newline at end of file
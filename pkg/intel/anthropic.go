@@ -0,0 +1,257 @@
+package intel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+const (
+	// DefaultAnthropicURL is used when Config.BaseURL is empty
+	DefaultAnthropicURL = "https://api.anthropic.com"
+
+	anthropicVersion = "2023-06-01"
+
+	// anthropicMaxTokens is sent when Options.NumPredict is unset; the
+	// Messages API requires max_tokens on every request
+	anthropicMaxTokens = 1024
+)
+
+// anthropicBackend calls Anthropic's Messages API
+type anthropicBackend struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// newAnthropicBackend creates a backend for the Messages API at baseURL
+func newAnthropicBackend(apiKey, baseURL string) *anthropicBackend {
+	if baseURL == "" {
+		baseURL = DefaultAnthropicURL
+	}
+	return &anthropicBackend{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{},
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	TopP        *float64           `json:"top_p,omitempty"`
+	Stream      bool               `json:"stream"`
+}
+
+// anthropicEvent covers the fields used from the streaming event types
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Chat sends req to the Messages API and streams the reply to fn as Ollama
+// chat responses. The final response has Done set and carries token counts.
+func (a *anthropicBackend) Chat(ctx context.Context, req *api.ChatRequest, fn api.ChatResponseFunc) error {
+	body, err := json.Marshal(toAnthropicRequest(req))
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("content-type", "application/json")
+	httpReq.Header.Set("accept", "text/event-stream")
+	httpReq.Header.Set("x-api-key", a.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	start := time.Now()
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return anthropicStatusError(resp)
+	}
+
+	var metrics api.Metrics
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event:") {
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		}
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		var data anthropicEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &data); err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", event, err)
+		}
+
+		switch data.Type {
+		case "message_start":
+			metrics.PromptEvalCount = data.Message.Usage.InputTokens
+		case "content_block_delta":
+			if data.Delta.Type != "text_delta" || data.Delta.Text == "" {
+				continue
+			}
+			if err := fn(api.ChatResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now(),
+				Message:   api.Message{Role: "assistant", Content: data.Delta.Text},
+			}); err != nil {
+				return err
+			}
+		case "message_delta":
+			metrics.EvalCount = data.Usage.OutputTokens
+		case "message_stop":
+			metrics.TotalDuration = time.Since(start)
+			metrics.EvalDuration = metrics.TotalDuration
+			return fn(api.ChatResponse{
+				Model:      req.Model,
+				CreatedAt:  time.Now(),
+				Message:    api.Message{Role: "assistant"},
+				Done:       true,
+				DoneReason: "stop",
+				Metrics:    metrics,
+			})
+		case "error":
+			return anthropicError(data.Error.Type, data.Error.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return NewNetworkError("stream_incomplete", "Anthropic stream ended before the message was complete", nil)
+}
+
+// toAnthropicRequest maps an Ollama chat request to the Messages API. System
+// messages move to the top-level system field and supported generation
+// options are carried over.
+func toAnthropicRequest(req *api.ChatRequest) anthropicRequest {
+	out := anthropicRequest{
+		Model:     req.Model,
+		MaxTokens: anthropicMaxTokens,
+		Stream:    true,
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		out.Messages = append(out.Messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+	}
+	out.System = strings.Join(system, "\n\n")
+
+	if n, ok := req.Options["num_predict"].(int); ok && n > 0 {
+		out.MaxTokens = n
+	}
+	if t, ok := req.Options["temperature"].(float64); ok {
+		// The Messages API accepts 0-1 rather than Ollama's 0-2
+		if t > 1 {
+			t = 1
+		}
+		out.Temperature = &t
+	}
+	if p, ok := req.Options["top_p"].(float64); ok {
+		out.TopP = &p
+	}
+
+	return out
+}
+
+// anthropicStatusError converts a non-200 response into an IntelError
+func anthropicStatusError(resp *http.Response) error {
+	var body struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := json.Unmarshal(data, &body); err != nil || body.Error.Message == "" {
+		body.Error.Message = strings.TrimSpace(string(data))
+		if body.Error.Message == "" {
+			body.Error.Message = resp.Status
+		}
+	}
+
+	// Proxies and gateways may answer without a typed error body
+	if body.Error.Type == "" {
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			body.Error.Type = "authentication_error"
+		case resp.StatusCode == http.StatusNotFound:
+			body.Error.Type = "not_found_error"
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			body.Error.Type = "api_error"
+		}
+	}
+
+	intelErr := anthropicError(body.Error.Type, body.Error.Message)
+	return intelErr.WithContext("status", resp.StatusCode)
+}
+
+// anthropicError maps an Anthropic error type to an IntelError
+func anthropicError(errType, message string) *IntelError {
+	cause := fmt.Errorf("%s: %s", errType, message)
+	switch errType {
+	case "authentication_error", "permission_error":
+		return NewConfigError("invalid_api_key", "Anthropic rejected the API key", cause).
+			WithSuggestions(
+				"Check api_key in config or the ANTHROPIC_API_KEY environment variable",
+				"Verify the key has access to the configured model",
+			)
+	case "not_found_error":
+		return NewIntelError(ErrorTypeModel, "not_found", "Model not found on Anthropic", cause).
+			WithSuggestions(
+				"Check the model name (e.g., 'claude-sonnet-4-5')",
+				"See https://docs.anthropic.com/en/docs/about-claude/models",
+			)
+	case "rate_limit_error", "overloaded_error", "api_error":
+		return NewNetworkError("unavailable", "Anthropic API is busy or unavailable", cause)
+	}
+	return NewIntelError(ErrorTypeUnknown, "anthropic_"+errType, message, cause)
+}
\ No newline at end of file
@@ -0,0 +1,113 @@
+package intel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+// DefaultAuditLogMaxSize is the size at which the audit log is rotated
+// when Config.AuditLogMaxSize is not set
+const DefaultAuditLogMaxSize = 10 * 1024 * 1024
+
+// AuditRecord is one line of the audit log
+type AuditRecord struct {
+	Timestamp  time.Time  `json:"timestamp"`
+	PromptType PromptType `json:"prompt_type"`
+	Model      string     `json:"model"`
+	Prompt     string     `json:"prompt"`
+	Response   string     `json:"response"`
+	LatencyMS  int64      `json:"latency_ms"`
+	Success    bool       `json:"success"`
+	Cached     bool       `json:"cached,omitempty"`
+	Error      string     `json:"error,omitempty"`
+
+	PromptTokens   int `json:"prompt_tokens,omitempty"`
+	ResponseTokens int `json:"response_tokens,omitempty"`
+}
+
+// auditLogger appends JSON lines to a file, rotating it to path.1 once it
+// would grow past maxSize
+type auditLogger struct {
+	path    string
+	maxSize int64
+	warned  bool
+	mu      sync.Mutex
+}
+
+// newAuditLogger creates a logger for path; maxSize <= 0 uses the default
+func newAuditLogger(path string, maxSize int64) *auditLogger {
+	if maxSize <= 0 {
+		maxSize = DefaultAuditLogMaxSize
+	}
+	return &auditLogger{path: path, maxSize: maxSize}
+}
+
+// Write appends a record. Only the first failure is returned, so the
+// caller warns once and auditing never breaks a query.
+func (a *auditLogger) Write(record AuditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.write(record); err != nil && !a.warned {
+		a.warned = true
+		return err
+	}
+	return nil
+}
+
+// write encodes and appends a record; the caller must hold the lock
+func (a *auditLogger) write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if info, err := os.Stat(a.path); err == nil && info.Size()+int64(len(line)) > a.maxSize {
+		if err := os.Rename(a.path, a.path+".1"); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(line)
+	return err
+}
+
+// auditQuery records a model query when an audit log is configured. Sensitive
+// state values are masked the same way as in 'intel context dump'.
+func (i *IntelSystem) auditQuery(promptType PromptType, prompt, response string, start time.Time, usage TokenUsage, err error) {
+	if i.audit == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Timestamp:  start,
+		PromptType: promptType,
+		Model:      i.config.Model,
+		Prompt:     i.maskSensitiveStateLines(prompt),
+		Response:   i.maskSensitiveStateLines(response),
+		LatencyMS:  time.Since(start).Milliseconds(),
+		Success:    err == nil,
+		Cached:     usage.Cached,
+
+		PromptTokens:   usage.PromptTokens,
+		ResponseTokens: usage.ResponseTokens,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if err := i.audit.Write(record); err != nil {
+		fmt.Fprintf(i.Output(), "%s⚠️  Audit log write failed: %s%s\n", output.YellowColor, err.Error(), output.Reset)
+	}
+}
\ No newline at end of file
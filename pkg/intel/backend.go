@@ -0,0 +1,52 @@
+package intel
+
+import (
+	"context"
+	"os"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Supported values for Config.Backend
+const (
+	BackendOllama    = "ollama"
+	BackendAnthropic = "anthropic"
+)
+
+// Backend sends chat requests to a model. Requests and streamed responses use
+// the Ollama API types; other backends translate to and from their own format.
+// *api.Client satisfies Backend.
+type Backend interface {
+	Chat(ctx context.Context, req *api.ChatRequest, fn api.ChatResponseFunc) error
+}
+
+// isHostedBackend reports whether the backend is a hosted API, in which case
+// Ollama installation, model downloads and local embeddings are skipped
+func isHostedBackend(backend string) bool {
+	return backend != "" && backend != BackendOllama
+}
+
+// apiKeyFor returns the configured API key, falling back to the backend's
+// usual environment variable
+func apiKeyFor(config *Config) string {
+	if config.APIKey != "" {
+		return config.APIKey
+	}
+	switch config.Backend {
+	case BackendAnthropic:
+		return os.Getenv("ANTHROPIC_API_KEY")
+	}
+	return ""
+}
+
+// newHostedBackend creates the client for a hosted backend
+func newHostedBackend(config *Config) (Backend, error) {
+	switch config.Backend {
+	case BackendAnthropic:
+		return newAnthropicBackend(apiKeyFor(config), config.BaseURL), nil
+	}
+	return nil, NewConfigError("unknown_backend", "Unknown backend: "+config.Backend, nil).
+		WithSuggestions(
+			"Use 'ollama' (default) or 'anthropic'",
+		)
+}
\ No newline at end of file
@@ -0,0 +1,208 @@
+package intel
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+)
+
+// TargetProvider is implemented by context providers that can switch their
+// state to a new target, which lets 'intel batch' run the same analysis
+// across many targets
+type TargetProvider interface {
+	SetTarget(target string) error
+}
+
+// BatchFunc analyzes a single target for AnalyzeBatch
+type BatchFunc func(target string) (*Response, error)
+
+// BatchResult is the outcome of analyzing one target
+type BatchResult struct {
+	Target   string
+	Response *Response
+	Duration time.Duration
+	Err      error
+}
+
+// AnalyzeTarget points every TargetProvider at target and runs an analysis
+// using the providers' analyze template. It is the flow 'intel batch' uses.
+func (i *IntelSystem) AnalyzeTarget(target string) (*Response, error) {
+	i.mu.RLock()
+	providers := append([]ContextProvider(nil), i.providers...)
+	i.mu.RUnlock()
+
+	for _, provider := range providers {
+		if setter, ok := provider.(TargetProvider); ok {
+			if err := setter.SetTarget(target); err != nil {
+				return nil, fmt.Errorf("%s: failed to set target: %w", provider.Name(), err)
+			}
+		}
+	}
+	return i.Analyze(fmt.Sprintf("Analyze target %s", target))
+}
+
+// AnalyzeBatch runs fn for each target and returns the results in the order
+// of targets, printing a progress line on Output as each one finishes.
+// Up to Config.BatchConcurrency targets run at once; fn may call Analyze,
+// whose prompts are built one at a time while the queries overlap. A nil
+// fn uses AnalyzeTarget, which always runs one target at a time since the
+// targets share provider state.
+func (i *IntelSystem) AnalyzeBatch(targets []string, fn BatchFunc) []BatchResult {
+	concurrency := i.config.BatchConcurrency
+	if fn == nil {
+		fn, concurrency = i.AnalyzeTarget, 1
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(targets))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	done := 0
+
+	for idx, target := range targets {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(idx int, target string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			start := time.Now()
+			response, err := fn(target)
+			results[idx] = BatchResult{Target: target, Response: response, Duration: time.Since(start), Err: err}
+
+			progressMu.Lock()
+			done++
+			i.reportBatchProgress(results[idx], done, len(targets))
+			progressMu.Unlock()
+		}(idx, target)
+	}
+	wg.Wait()
+	return results
+}
+
+// reportBatchProgress prints one line per finished target, except in JSON mode
+func (i *IntelSystem) reportBatchProgress(result BatchResult, done, total int) {
+	if output.CurrentMode == output.ModeJSON {
+		return
+	}
+	status := fmt.Sprintf("%s✓%s", output.GreenColor, output.Reset)
+	if result.Err != nil {
+		status = fmt.Sprintf("%s✗%s", output.RedColor, output.Reset)
+	}
+	fmt.Fprintf(i.Output(), "[%d/%d] %s %s %s(%s)%s\n", done, total, status, result.Target,
+		output.DimColor, result.Duration.Round(100*time.Millisecond), output.Reset)
+}
+
+// BuildBatchReport renders batch results as a markdown document: a summary
+// table followed by each target's analysis
+func (i *IntelSystem) BuildBatchReport(results []BatchResult) string {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s Batch Analysis\n\n", i.appName)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Generated | %s |\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Model | %s |\n", escapeTableCell(i.config.Model))
+	fmt.Fprintf(&b, "| Targets | %d (%d failed) |\n", len(results), failed)
+
+	b.WriteString("\n## Summary\n\n| Target | Status | Time | Summary |\n|---|---|---|---|\n")
+	for _, result := range results {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", escapeTableCell(result.Target), batchStatus(result),
+			result.Duration.Round(100*time.Millisecond), escapeTableCell(batchSummary(result)))
+	}
+
+	for _, result := range results {
+		fmt.Fprintf(&b, "\n## %s\n\n", result.Target)
+		if result.Err != nil {
+			fmt.Fprintf(&b, "Analysis failed: %s\n", result.Err)
+			continue
+		}
+		b.WriteString(demoteHeadings(strings.TrimSpace(result.Response.Content), 2))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// BuildBatchCSV renders batch results as CSV with one row per target:
+// target, status, seconds, summary and error
+func BuildBatchCSV(results []BatchResult) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"target", "status", "seconds", "summary", "error"})
+	for _, result := range results {
+		summary, errText := batchSummary(result), ""
+		if result.Err != nil {
+			summary, errText = "", result.Err.Error()
+		}
+		writer.Write([]string{
+			result.Target,
+			batchStatus(result),
+			fmt.Sprintf("%.1f", result.Duration.Seconds()),
+			summary,
+			errText,
+		})
+	}
+	writer.Flush()
+	return buf.String(), writer.Error()
+}
+
+// WriteBatchReport writes batch results to path, as CSV when it ends in
+// .csv and as markdown otherwise
+func (i *IntelSystem) WriteBatchReport(path string, results []BatchResult) error {
+	report := i.BuildBatchReport(results)
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		var err error
+		if report, err = BuildBatchCSV(results); err != nil {
+			return err
+		}
+	}
+
+	if err := utils.WriteFileAtomic(path, []byte(report), 0644); err != nil {
+		return NewIntelError(ErrorTypeContext, "report_write_failed",
+			fmt.Sprintf("Failed to write report file: %s", path), err).
+			WithSuggestions(
+				"Check that the directory exists and is writable",
+				"Try a different path",
+			)
+	}
+	return nil
+}
+
+// batchStatus is the status column of a batch report
+func batchStatus(result BatchResult) string {
+	if result.Err != nil {
+		return "failed"
+	}
+	return "ok"
+}
+
+// batchSummary is the first line of a target's analysis that isn't a
+// heading, or its error
+func batchSummary(result BatchResult) string {
+	if result.Err != nil {
+		return result.Err.Error()
+	}
+	for _, line := range strings.Split(result.Response.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.TrimSpace(strings.TrimLeft(line, "*-• "))
+	}
+	return ""
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package intel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// slowBackend answers every chat after a delay, so concurrent batch
+// analyses overlap while their prompts are being built
+type slowBackend struct {
+	delay time.Duration
+}
+
+func (b slowBackend) Chat(ctx context.Context, req *api.ChatRequest, fn api.ChatResponseFunc) error {
+	time.Sleep(b.delay)
+	return fn(api.ChatResponse{Message: api.Message{Role: "assistant", Content: "- finding"}, Done: true})
+}
+
+// targetProvider reports the last target it was pointed at as state
+type targetProvider struct {
+	*BaseContextProvider
+	target string
+}
+
+func (p *targetProvider) GetCurrentState() map[string]interface{} {
+	return map[string]interface{}{"target_url": p.target, "authenticated": true}
+}
+
+func TestAnalyzeBatchConcurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config := DefaultConfig()
+	config.BatchConcurrency = 4
+	system := New("batch-test", config)
+	system.SetOutput(io.Discard)
+	system.backend = slowBackend{delay: 20 * time.Millisecond}
+	system.initialized = true
+	system.RegisterProvider(&targetProvider{BaseContextProvider: NewBaseContextProvider("web", "testing", "HTTP knowledge")})
+	system.AddAction("scan", []string{"example.com"}, "done", false)
+
+	var targets []string
+	for n := 0; n < 12; n++ {
+		targets = append(targets, fmt.Sprintf("host%d.example.com", n))
+	}
+
+	results := system.AnalyzeBatch(targets, func(target string) (*Response, error) {
+		return system.Analyze("Analyze target " + target)
+	})
+
+	if len(results) != len(targets) {
+		t.Fatalf("got %d results, want %d", len(results), len(targets))
+	}
+	for n, result := range results {
+		if result.Target != targets[n] {
+			t.Errorf("result %d is for %s, want %s", n, result.Target, targets[n])
+		}
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Target, result.Err)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,92 @@
+package intel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// benchPrompt is sent unchanged to every model so results are comparable
+const benchPrompt = `You are assisting with a security assessment of a web API.
+Session summary:
+- Target: https://api.example.com
+- 14 endpoints discovered, 3 require no authentication
+- GraphQL introspection is enabled
+- One endpoint returned a stack trace on malformed input
+
+Analyze the session. Give 3-5 key findings and immediate next steps. Use bullets. Be concise.`
+
+// BenchResult holds the measurements for one model
+type BenchResult struct {
+	Model            string
+	TimeToFirstToken time.Duration
+	Total            time.Duration
+	OutputTokens     int
+	TokensPerSecond  float64
+	Err              error
+}
+
+// BenchmarkModel sends the fixed benchmark prompt to model and measures
+// time-to-first-token, total latency and output token count. The model must
+// already be available locally.
+func (i *IntelSystem) BenchmarkModel(model string) BenchResult {
+	result := BenchResult{Model: model}
+	if !i.IsInitialized() {
+		result.Err = fmt.Errorf("Intel system not initialized")
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), i.config.TimeoutFor(PromptAnalyze))
+	defer cancel()
+
+	req := i.newChatRequest(benchPrompt)
+	req.Model = model
+
+	start := time.Now()
+	var metrics api.Metrics
+	err := i.backend.Chat(ctx, req, func(resp api.ChatResponse) error {
+		if resp.Message.Content != "" && result.TimeToFirstToken == 0 {
+			result.TimeToFirstToken = time.Since(start)
+		}
+		if resp.Done {
+			metrics = resp.Metrics
+		}
+		return nil
+	})
+	result.Total = time.Since(start)
+
+	if err != nil {
+		result.Err = HandleError(err)
+		return result
+	}
+
+	result.OutputTokens = metrics.EvalCount
+	if metrics.EvalDuration > 0 {
+		result.TokensPerSecond = float64(metrics.EvalCount) / metrics.EvalDuration.Seconds()
+	}
+	return result
+}
+
+// LocalModels returns the names of models available in Ollama
+func (i *IntelSystem) LocalModels() ([]string, error) {
+	if !i.IsInitialized() {
+		return nil, fmt.Errorf("Intel system not initialized")
+	}
+	if i.client == nil {
+		return nil, fmt.Errorf("local models are not available with the %s backend", i.config.Backend)
+	}
+	return NewModelManager(i.client).ListAvailableModels()
+}
+
+// PullModel downloads a model with progress reporting
+func (i *IntelSystem) PullModel(model string) error {
+	if !i.IsInitialized() {
+		return fmt.Errorf("Intel system not initialized")
+	}
+	if i.client == nil {
+		return fmt.Errorf("models cannot be downloaded with the %s backend", i.config.Backend)
+	}
+	return NewModelManager(i.client).WithWriter(i.Output()).EnsureModel(model)
+}
\ No newline at end of file
@@ -0,0 +1,114 @@
+package intel
+
+import (
+	"sort"
+	"strings"
+)
+
+// domainBudgetShare is the fraction of the token limit reserved for provider domain knowledge
+const domainBudgetShare = 0.5
+
+// DefaultProviderWeight is the weight given to providers registered without one
+const DefaultProviderWeight = 1.0
+
+// DomainKnowledge is one provider's domain knowledge with its budget weight
+type DomainKnowledge struct {
+	Provider  string
+	Knowledge string
+	Weight    float64
+}
+
+// AddDomainKnowledge adds each provider's domain knowledge, splitting the domain
+// token budget between providers in proportion to their weights. Providers that
+// need less than their share hand the remainder to the others, and knowledge
+// that exceeds its allocation is trimmed from the end so lower-weight providers
+// give up the most.
+func (cm *ContextManager) AddDomainKnowledge(entries []DomainKnowledge) {
+	budget := int(float64(cm.maxTokens) * domainBudgetShare)
+	allocations := cm.allocateDomainBudget(entries, budget)
+
+	for _, entry := range entries {
+		if entry.Knowledge == "" {
+			continue
+		}
+		content := cm.trimToTokens(entry.Knowledge, allocations[entry.Provider])
+		if content == "" {
+			cm.removeItem("domain-" + entry.Provider)
+			continue
+		}
+		cm.AddProviderContext(entry.Provider, "domain-"+entry.Provider, ContextTypeDomain, content, true)
+	}
+}
+
+// allocateDomainBudget distributes budget tokens across providers by weight
+func (cm *ContextManager) allocateDomainBudget(entries []DomainKnowledge, budget int) map[string]int {
+	allocations := make(map[string]int, len(entries))
+	needs := make(map[string]int, len(entries))
+
+	pending := make([]DomainKnowledge, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Knowledge == "" {
+			continue
+		}
+		if entry.Weight <= 0 {
+			entry.Weight = DefaultProviderWeight
+		}
+		needs[entry.Provider] = cm.estimateTokens(entry.Knowledge)
+		pending = append(pending, entry)
+	}
+
+	// Satisfy providers that fit within their share, then re-split what's left
+	for len(pending) > 0 {
+		totalWeight := 0.0
+		for _, entry := range pending {
+			totalWeight += entry.Weight
+		}
+
+		var remaining []DomainKnowledge
+		for _, entry := range pending {
+			share := int(float64(budget) * entry.Weight / totalWeight)
+			if needs[entry.Provider] <= share {
+				allocations[entry.Provider] = needs[entry.Provider]
+				budget -= needs[entry.Provider]
+			} else {
+				remaining = append(remaining, entry)
+			}
+		}
+
+		if len(remaining) == len(pending) {
+			// Everyone needs more than their share; split proportionally
+			for _, entry := range remaining {
+				allocations[entry.Provider] = int(float64(budget) * entry.Weight / totalWeight)
+			}
+			break
+		}
+		pending = remaining
+	}
+
+	return allocations
+}
+
+// trimToTokens returns the longest line-aligned prefix of text that fits in
+// maxTokens, falling back to a character cut when even one line is too long
+func (cm *ContextManager) trimToTokens(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	if cm.estimateTokens(text) <= maxTokens {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	n := sort.Search(len(lines)+1, func(n int) bool {
+		return cm.estimateTokens(strings.Join(lines[:n], "\n")) > maxTokens
+	}) - 1
+	if n > 0 {
+		return strings.Join(lines[:n], "\n")
+	}
+
+	runes := []rune(lines[0])
+	n = sort.Search(len(runes)+1, func(n int) bool {
+		return cm.estimateTokens(string(runes[:n])) > maxTokens
+	}) - 1
+	return string(runes[:n])
+}
\ No newline at end of file
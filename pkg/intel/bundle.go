@@ -0,0 +1,125 @@
+package intel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+)
+
+// ContextBundleVersion is the current context bundle format version
+const ContextBundleVersion = 1
+
+// ContextBundle is the prompt-building state written by ExportContext: the
+// context items plus the model and templates they were used with. Unlike a
+// saved session it leaves out actions and session data, so it can be handed
+// to a teammate to reproduce a result.
+type ContextBundle struct {
+	Version    int               `json:"version"`
+	AppName    string            `json:"app_name"`
+	Model      string            `json:"model"`
+	ExportedAt time.Time         `json:"exported_at"`
+	MaxTokens  int               `json:"max_tokens"`
+	Redacted   bool              `json:"redacted"`
+	Prompts    map[string]string `json:"prompts"`
+	Items      []ContextItem     `json:"items"`
+}
+
+// bundlePromptTypes are the prompt types whose templates are exported
+var bundlePromptTypes = []PromptType{PromptAnalyze, PromptSuggest, PromptExplain, PromptDebug, PromptHelp}
+
+// ExportContext writes the current context items, model and effective
+// prompt templates to path as JSON. With redact set, "- key: value" state
+// lines whose key looks sensitive are masked as in 'intel context dump'.
+func (i *IntelSystem) ExportContext(path string, redact bool) error {
+	i.contextMu.Lock()
+	i.mu.RLock()
+	bundle := ContextBundle{
+		Version:    ContextBundleVersion,
+		AppName:    i.appName,
+		Model:      i.config.Model,
+		ExportedAt: time.Now(),
+		MaxTokens:  i.contextManager.maxTokens,
+		Redacted:   redact,
+		Prompts:    make(map[string]string),
+		Items:      i.contextManager.GetItems(),
+	}
+	i.mu.RUnlock()
+	i.contextMu.Unlock()
+
+	for _, promptType := range bundlePromptTypes {
+		if template := i.EffectivePrompt(promptType); template != "" {
+			bundle.Prompts[string(promptType)] = template
+		}
+	}
+	if redact {
+		for idx := range bundle.Items {
+			bundle.Items[idx].Content = i.maskSensitiveStateLines(bundle.Items[idx].Content)
+		}
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return NewIntelError(ErrorTypeContext, "bundle_encode_failed", "Failed to encode context", err)
+	}
+
+	if err := utils.WriteFileAtomic(path, data, 0600); err != nil {
+		return NewIntelError(ErrorTypeContext, "bundle_write_failed",
+			fmt.Sprintf("Failed to write context file: %s", path), err).
+			WithSuggestions(
+				"Check that the directory exists and is writable",
+				"Try a different path",
+			)
+	}
+	return nil
+}
+
+// ImportContext replaces the context items with those in a bundle written
+// by ExportContext and uses its templates as Config.CustomPrompts, so the
+// next prompt is built the way it was on the exporting machine. Provider
+// templates still take precedence, and providers refresh their own items
+// the next time a prompt is built. The bundle is returned so callers can
+// compare its model with theirs.
+func (i *IntelSystem) ImportContext(path string) (*ContextBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, NewIntelError(ErrorTypeContext, "bundle_read_failed",
+			fmt.Sprintf("Failed to read context file: %s", path), err).
+			WithSuggestions(
+				"Check that the file exists",
+				"Export one first with 'intel context export <file>'",
+			)
+	}
+
+	var bundle ContextBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, NewIntelError(ErrorTypeContext, "bundle_decode_failed", "Context file is not valid", err)
+	}
+
+	if bundle.Version < 1 || bundle.Version > ContextBundleVersion {
+		return nil, NewIntelError(ErrorTypeContext, "bundle_version_unsupported",
+			fmt.Sprintf("Unsupported context format version: %d", bundle.Version), nil).
+			WithSuggestions(
+				fmt.Sprintf("This build supports context format versions 1-%d", ContextBundleVersion),
+				"Upgrade the tool to import context exported by newer versions",
+			)
+	}
+
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if bundle.MaxTokens > 0 {
+		i.contextManager.maxTokens = bundle.MaxTokens
+	}
+	i.contextManager.LoadItems(bundle.Items)
+	if i.config.CustomPrompts == nil {
+		i.config.CustomPrompts = make(map[string]string)
+	}
+	for promptType, template := range bundle.Prompts {
+		i.config.CustomPrompts[promptType] = template
+	}
+	return &bundle, nil
+}
\ No newline at end of file
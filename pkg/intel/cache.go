@@ -0,0 +1,106 @@
+package intel
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// responseCache is a fixed-size LRU cache of model responses keyed by prompt hash
+type responseCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	hits     int
+	misses   int
+	mu       sync.Mutex
+}
+
+// cacheEntry is a single cached response
+type cacheEntry struct {
+	key     string
+	content string
+}
+
+// newResponseCache creates a cache holding at most capacity responses
+func newResponseCache(capacity int) *responseCache {
+	return &responseCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// cacheKey hashes the model name and fully-built prompt into a cache key
+func cacheKey(model, prompt string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns a cached response and marks it as recently used
+func (c *responseCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		c.misses++
+		return "", false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).content, true
+}
+
+// Put stores a response, evicting the least recently used entry when full
+func (c *responseCache) Put(key, content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		element.Value.(*cacheEntry).content = content
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, content: content})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Clear removes all cached responses and resets counters
+func (c *responseCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.hits = 0
+	c.misses = 0
+}
+
+// Stats returns cache usage statistics
+func (c *responseCache) Stats() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hitRate := 0.0
+	if total := c.hits + c.misses; total > 0 {
+		hitRate = float64(c.hits) / float64(total)
+	}
+
+	return map[string]interface{}{
+		"enabled":  true,
+		"entries":  c.order.Len(),
+		"capacity": c.capacity,
+		"hits":     c.hits,
+		"misses":   c.misses,
+		"hit_rate": hitRate,
+	}
+}
\ No newline at end of file
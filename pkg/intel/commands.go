@@ -2,21 +2,31 @@ package intel
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/jacobdavidalcock/consolekit/pkg/command"
 	"github.com/jacobdavidalcock/consolekit/pkg/console"
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
 )
 
 // RegisterIntelCommands adds Intel commands to a ConsoleKit application
 func RegisterIntelCommands(app *console.Console, intel *IntelSystem) {
 	// Main intel command with subcommands
+	intel.SetOutput(app.Stdout())
+	if state := app.State(); state != nil {
+		intel.SetState(state)
+	}
 	app.AddCommand("intel", &IntelCommand{system: intel}, "AI-powered analysis and assistance")
 }
 
 // IntelCommand handles all intel subcommands
 type IntelCommand struct {
 	system *IntelSystem
+	piped  string // output piped into the current invocation, see ExecuteContext
 }
 
 // Execute handles intel command execution with subcommands
@@ -38,15 +48,37 @@ func (c *IntelCommand) Execute(args []string) error {
 		return c.handleSuggest(subArgs)
 	case "explain", "explanation":
 		return c.handleExplain(subArgs)
+	case "debug", "troubleshoot":
+		return c.handleDebug(subArgs)
+	case "doctor":
+		return c.handleDoctor(subArgs)
+	case "unload":
+		return c.handleUnload(subArgs)
 	case "status":
 		return c.handleStatus(subArgs)
 	case "context":
 		return c.handleContext(subArgs)
 	case "validate":
 		return c.handleValidate(subArgs)
+	case "cache":
+		return c.handleCache(subArgs)
+	case "save":
+		return c.handleSave(subArgs)
+	case "load":
+		return c.handleLoad(subArgs)
+	case "report":
+		return c.handleReport(subArgs)
+	case "bench", "benchmark":
+		return c.handleBench(subArgs)
+	case "batch":
+		return c.handleBatch(subArgs)
+	case "usage":
+		return c.handleUsage(subArgs)
+	case "quiet":
+		return c.handleQuiet(subArgs)
 	case "help":
 		if len(subArgs) > 0 && subArgs[0] == "errors" {
-			ShowQuickHelp()
+			ShowQuickHelpTo(c.system.Output())
 		} else {
 			c.showHelp()
 		}
@@ -56,6 +88,29 @@ func (c *IntelCommand) Execute(args []string) error {
 	}
 }
 
+// ExecuteContext runs a subcommand with any piped input attached to the
+// query, so "scan example.com | intel analyze" analyzes the scan output
+func (c *IntelCommand) ExecuteContext(ctx *command.CommandContext) error {
+	c.piped = ""
+	if ctx.Stdin != nil {
+		data, err := io.ReadAll(ctx.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read piped input: %w", err)
+		}
+		c.piped = strings.TrimSpace(string(data))
+	}
+	defer func() { c.piped = "" }()
+	return c.Execute(ctx.Args)
+}
+
+// withPiped appends piped input to a query
+func (c *IntelCommand) withPiped(query string) string {
+	if c.piped == "" {
+		return query
+	}
+	return fmt.Sprintf("%s\n\nCommand output:\n%s", query, c.piped)
+}
+
 // Description returns the command description
 func (c *IntelCommand) Description() string {
 	return "AI-powered analysis and assistance"
@@ -64,38 +119,53 @@ func (c *IntelCommand) Description() string {
 // handleStart initializes the Intel system
 func (c *IntelCommand) handleStart(args []string) error {
 	// Show personality message
-	ShowPersonalityMessage("initializing")
+	ShowPersonalityMessageTo(c.system.Output(), "initializing")
 	
 	if err := c.system.Initialize(); err != nil {
-		fmt.Printf("\r%80s\r", "") // Clear personality message
+		fmt.Fprintf(c.system.Output(), "\r%80s\r", "") // Clear personality message
 		if intelErr, ok := err.(*IntelError); ok {
-			intelErr.Display()
+			intelErr.DisplayTo(c.system.Output())
 		} else {
 			style := GetStyleConstants()
-			fmt.Printf("%s\n", style.FormatStatus("Failed to initialize Intel system: "+err.Error(), "error"))
-			fmt.Printf("%s\n", style.FormatStatus("Try: `ollama serve` or check your configuration", "info"))
+			fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus("Failed to initialize Intel system: "+err.Error(), "error"))
+			fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus("Try: `ollama serve` or check your configuration", "info"))
+		}
+		if c.system.IsOffline() {
+			style := GetStyleConstants()
+			fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus("Offline mode: 'intel analyze' and 'intel suggest' will show heuristic summaries", "warning"))
 		}
 		return err
 	}
 
 	style := GetStyleConstants()
-	fmt.Printf("\r%s\n", style.FormatStatus("Intel AI system initialized successfully", "success"))
+	fmt.Fprintf(c.system.Output(), "\r%s\n", style.FormatStatus("Intel AI system initialized successfully", "success"))
 	
 	// Show available providers
 	if len(c.system.providers) > 0 {
-		fmt.Printf("\nActive context providers:\n")
+		fmt.Fprintf(c.system.Output(), "\nActive context providers:\n")
 		for _, provider := range c.system.providers {
-			fmt.Printf("  %s\n", style.FormatBullet(provider.Name()))
+			fmt.Fprintf(c.system.Output(), "  %s\n", style.FormatBullet(provider.Name()))
 		}
 	}
 
-	fmt.Printf("\n%sTry: intel analyze%s\n", output.CyanColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "\n%sTry: intel analyze%s\n", output.CyanColor, output.Reset)
 	return nil
 }
 
 // handleAnalyze performs AI analysis of the current session
 func (c *IntelCommand) handleAnalyze(args []string) error {
+	args, asJSON := output.WantsJSON(args)
+	
 	if !c.system.IsInitialized() {
+		if c.system.IsOffline() {
+			response := c.system.HeuristicAnalyze()
+			c.system.recordAnalysis(strings.Join(args, " "), response)
+			if asJSON {
+				return output.JSON(response)
+			}
+			c.showOffline("Intel Analysis", response.Content)
+			return nil
+		}
 		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
 	}
 
@@ -103,19 +173,28 @@ func (c *IntelCommand) handleAnalyze(args []string) error {
 	if len(args) > 0 {
 		userPrompt = strings.Join(args, " ")
 	}
+	userPrompt = c.withPiped(userPrompt)
+	
+	if asJSON {
+		response, err := c.system.Analyze(userPrompt)
+		if err != nil {
+			return err
+		}
+		return output.JSON(response)
+	}
 
 	// Show personality message
-	ShowPersonalityMessage("analyzing")
+	ShowPersonalityMessageTo(c.system.Output(), "analyzing")
 	
-	fmt.Printf("\n%sIntel Analysis%s\n", output.BoldColor, output.Reset)
-	fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 15), output.Reset)
+	fmt.Fprintf(c.system.Output(), "\n%sIntel Analysis%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 15), output.Reset)
 	
 	// Use streaming analysis
 	if err := c.system.AnalyzeWithStreaming(userPrompt); err != nil {
 		if intelErr, ok := err.(*IntelError); ok {
-			intelErr.Display()
+			intelErr.DisplayTo(c.system.Output())
 		} else {
-			fmt.Printf("%s❌ Analysis failed: %s%s\n", 
+			fmt.Fprintf(c.system.Output(), "%s❌ Analysis failed: %s%s\n", 
 				output.RedColor, err.Error(), output.Reset)
 		}
 		return err
@@ -126,7 +205,17 @@ func (c *IntelCommand) handleAnalyze(args []string) error {
 
 // handleSuggest provides AI-generated suggestions
 func (c *IntelCommand) handleSuggest(args []string) error {
+	args, asJSON := output.WantsJSON(args)
+	
 	if !c.system.IsInitialized() {
+		if c.system.IsOffline() {
+			suggestions := c.system.HeuristicSuggest()
+			if asJSON {
+				return output.JSON(suggestions)
+			}
+			c.showOffline("Intel Suggestions", FormatSuggestions(suggestions))
+			return nil
+		}
 		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
 	}
 
@@ -134,19 +223,28 @@ func (c *IntelCommand) handleSuggest(args []string) error {
 	if len(args) > 0 {
 		context = strings.Join(args, " ")
 	}
+	context = c.withPiped(context)
+	
+	if asJSON {
+		suggestions, err := c.system.Suggest(context)
+		if err != nil {
+			return err
+		}
+		return output.JSON(suggestions)
+	}
 
 	// Show personality message
-	ShowPersonalityMessage("suggesting")
+	ShowPersonalityMessageTo(c.system.Output(), "suggesting")
 	
-	fmt.Printf("\n%sIntel Suggestions%s\n", output.BoldColor, output.Reset)
-	fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 17), output.Reset)
+	fmt.Fprintf(c.system.Output(), "\n%sIntel Suggestions%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 17), output.Reset)
 	
 	// Use streaming suggestions
 	if err := c.system.SuggestWithStreaming(context); err != nil {
 		if intelErr, ok := err.(*IntelError); ok {
-			intelErr.Display()
+			intelErr.DisplayTo(c.system.Output())
 		} else {
-			fmt.Printf("%s❌ Suggestion generation failed: %s%s\n", 
+			fmt.Fprintf(c.system.Output(), "%s❌ Suggestion generation failed: %s%s\n", 
 				output.RedColor, err.Error(), output.Reset)
 		}
 		return err
@@ -155,31 +253,54 @@ func (c *IntelCommand) handleSuggest(args []string) error {
 	return nil
 }
 
+// showOffline prints heuristic output under the offline banner
+func (c *IntelCommand) showOffline(title, content string) {
+	style := GetStyleConstants()
+
+	fmt.Fprintf(c.system.Output(), "\n%s%s%s\n", output.BoldColor, title, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", len(title)), output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus(OfflineBanner, "warning"))
+	fmt.Fprint(c.system.Output(), output.RenderMarkdown(content))
+}
+
 // handleExplain provides detailed explanations
 func (c *IntelCommand) handleExplain(args []string) error {
+	args, asJSON := output.WantsJSON(args)
+	
 	if !c.system.IsInitialized() {
 		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
 	}
 
-	if len(args) == 0 {
+	if len(args) == 0 && c.piped == "" {
 		return fmt.Errorf("please specify what you'd like explained. Usage: intel explain <topic>")
 	}
 
 	topic := strings.Join(args, " ")
+	if topic == "" {
+		topic = "Explain this output"
+	}
+	
+	if asJSON {
+		explanation, err := c.system.Explain(c.withPiped(topic))
+		if err != nil {
+			return err
+		}
+		return output.JSON(explanation)
+	}
 	
 	// Show personality message
-	ShowPersonalityMessage("explaining")
+	ShowPersonalityMessageTo(c.system.Output(), "explaining")
 	
-	fmt.Printf("\n%sIntel Explanation%s\n", output.BoldColor, output.Reset)
-	fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 17), output.Reset)
-	fmt.Printf("%sTopic: %s%s\n\n", output.YellowColor, topic, output.Reset)
+	fmt.Fprintf(c.system.Output(), "\n%sIntel Explanation%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 17), output.Reset)
+	fmt.Fprintf(c.system.Output(), "%sTopic: %s%s\n\n", output.YellowColor, topic, output.Reset)
 	
 	// Use streaming explanation
-	if err := c.system.ExplainWithStreaming(topic); err != nil {
+	if err := c.system.ExplainWithStreaming(c.withPiped(topic)); err != nil {
 		if intelErr, ok := err.(*IntelError); ok {
-			intelErr.Display()
+			intelErr.DisplayTo(c.system.Output())
 		} else {
-			fmt.Printf("%s❌ Explanation failed: %s%s\n", 
+			fmt.Fprintf(c.system.Output(), "%s❌ Explanation failed: %s%s\n", 
 				output.RedColor, err.Error(), output.Reset)
 		}
 		return err
@@ -188,31 +309,186 @@ func (c *IntelCommand) handleExplain(args []string) error {
 	return nil
 }
 
+// handleDebug troubleshoots an error message or symptom
+func (c *IntelCommand) handleDebug(args []string) error {
+	args, asJSON := output.WantsJSON(args)
+	
+	if !c.system.IsInitialized() {
+		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
+	}
+
+	if len(args) == 0 && c.piped == "" {
+		return fmt.Errorf("please describe the error or symptom. Usage: intel debug <error-or-symptom>")
+	}
+
+	symptom := strings.Join(args, " ")
+	if symptom == "" {
+		symptom = "Why did this command fail?"
+	}
+	
+	if asJSON {
+		diagnosis, err := c.system.Debug(c.withPiped(symptom))
+		if err != nil {
+			return err
+		}
+		return output.JSON(diagnosis)
+	}
+	
+	ShowPersonalityMessageTo(c.system.Output(), "debugging")
+	
+	fmt.Fprintf(c.system.Output(), "\n%sIntel Troubleshooting%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 21), output.Reset)
+	fmt.Fprintf(c.system.Output(), "%sProblem: %s%s\n\n", output.YellowColor, symptom, output.Reset)
+	
+	if err := c.system.DebugWithStreaming(c.withPiped(symptom)); err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		} else {
+			fmt.Fprintf(c.system.Output(), "%s❌ Troubleshooting failed: %s%s\n", 
+				output.RedColor, err.Error(), output.Reset)
+		}
+		return err
+	}
+	
+	return nil
+}
+
+// handleDoctor runs the Intel health checks and fails when a critical one does
+func (c *IntelCommand) handleDoctor(args []string) error {
+	args, asJSON := output.WantsJSON(args)
+	
+	// Only offer to download the model when someone can answer
+	var offerPull func(string) bool
+	if !asJSON {
+		offerPull = func(model string) bool {
+			pull, err := output.Confirm(fmt.Sprintf("Model %s is not downloaded. Pull it now?", model))
+			return err == nil && pull
+		}
+		fmt.Fprintf(c.system.Output(), "\n%sIntel Doctor%s\n", output.BoldColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 12), output.Reset)
+	}
+	
+	checks := c.system.Doctor(offerPull)
+	
+	failed := 0
+	for _, check := range checks {
+		if !check.OK && !check.Skipped && check.Critical {
+			failed++
+		}
+	}
+	
+	if asJSON {
+		if err := output.JSON(checks); err != nil {
+			return err
+		}
+	} else {
+		for _, check := range checks {
+			switch {
+			case check.OK:
+				fmt.Fprintf(c.system.Output(), "%s✓%s %-18s %s\n", output.GreenColor, output.Reset, check.Name, check.Detail)
+			case check.Skipped:
+				fmt.Fprintf(c.system.Output(), "%s-%s %-18s %s\n", output.YellowColor, output.Reset, check.Name, check.Detail)
+			case check.Critical:
+				fmt.Fprintf(c.system.Output(), "%s✗%s %-18s %s\n", output.RedColor, output.Reset, check.Name, check.Detail)
+			default:
+				fmt.Fprintf(c.system.Output(), "%s!%s %-18s %s\n", output.YellowColor, output.Reset, check.Name, check.Detail)
+			}
+			if !check.OK {
+				for _, fix := range check.Fixes {
+					fmt.Fprintf(c.system.Output(), "    %s▸ %s%s\n", output.CyanColor, fix, output.Reset)
+				}
+			}
+		}
+		fmt.Fprintln(c.system.Output())
+	}
+	
+	if failed > 0 {
+		return fmt.Errorf("%d critical check(s) failed", failed)
+	}
+	if !asJSON {
+		fmt.Fprintf(c.system.Output(), "%s✅ Intel is ready%s\n", output.GreenColor, output.Reset)
+	}
+	return nil
+}
+
+// handleUnload evicts the model from Ollama's memory
+func (c *IntelCommand) handleUnload(args []string) error {
+	if err := c.system.Unload(); err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		}
+		return err
+	}
+	
+	style := GetStyleConstants()
+	fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus(fmt.Sprintf("Model %s unloaded from memory", c.system.config.Model), "success"))
+	return nil
+}
+
 // handleStatus shows Intel system status
 func (c *IntelCommand) handleStatus(args []string) error {
-	fmt.Printf("\n%s🤖 Intel System Status:%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "\n%s🤖 Intel System Status:%s\n", output.BoldColor, output.Reset)
 	
-	// Show Ollama status
-	ollamaStatus, err := c.system.GetOllamaStatus()
-	if err != nil {
-		fmt.Printf("Ollama: %s%s%s\n", output.RedColor, ollamaStatus, output.Reset)
+	// Show Ollama status, or the hosted backend in use
+	if c.system.UsesHostedBackend() {
+		fmt.Fprintf(c.system.Output(), "Backend: %s%s%s\n", output.CyanColor, c.system.config.Backend, output.Reset)
 	} else {
-		fmt.Printf("Ollama: %s\n", ollamaStatus)
+		ollamaStatus, err := c.system.GetOllamaStatus()
+		if err != nil {
+			fmt.Fprintf(c.system.Output(), "Ollama: %s%s%s\n", output.RedColor, ollamaStatus, output.Reset)
+		} else {
+			fmt.Fprintf(c.system.Output(), "Ollama: %s\n", ollamaStatus)
+		}
 	}
 	
 	// Show Intel system status
 	if c.system.IsInitialized() {
-		fmt.Printf("Intel: %s✅ Active%s\n", output.GreenColor, output.Reset)
-		fmt.Printf("Model: %s%s%s\n", output.CyanColor, c.system.config.Model, output.Reset)
-		fmt.Printf("URL: %s%s%s\n", output.CyanColor, c.system.config.OllamaURL, output.Reset)
+		fmt.Fprintf(c.system.Output(), "Intel: %s✅ Active%s\n", output.GreenColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "Model: %s%s%s\n", output.CyanColor, c.system.config.Model, output.Reset)
+		if c.system.UsesHostedBackend() {
+			baseURL := c.system.config.BaseURL
+			if baseURL == "" {
+				baseURL = "(default)"
+			}
+			fmt.Fprintf(c.system.Output(), "URL: %s%s%s\n", output.CyanColor, baseURL, output.Reset)
+		} else {
+			fmt.Fprintf(c.system.Output(), "URL: %s%s%s\n", output.CyanColor, c.system.config.OllamaURL, output.Reset)
+		}
+	} else if c.system.IsOffline() {
+		fmt.Fprintf(c.system.Output(), "Intel: %s⚠️  Offline (heuristic mode)%s\n", output.YellowColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "Run '%sintel start%s' to retry\n", output.YellowColor, output.Reset)
 	} else {
-		fmt.Printf("Intel: %s❌ Not initialized%s\n", output.RedColor, output.Reset)
-		fmt.Printf("Run '%sintel start%s' to initialize\n", output.YellowColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "Intel: %s❌ Not initialized%s\n", output.RedColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "Run '%sintel start%s' to initialize\n", output.YellowColor, output.Reset)
+	}
+	
+	if !c.system.UsesHostedBackend() {
+		if name, vramGB, ok := NewModelManager(nil).DetectGPU(); ok {
+			fmt.Fprintf(c.system.Output(), "GPU: %s%s (%dGB)%s\n", output.CyanColor, name, vramGB, output.Reset)
+		} else {
+			fmt.Fprintf(c.system.Output(), "GPU: %snone detected (CPU inference)%s\n", output.YellowColor, output.Reset)
+		}
+	}
+	
+	if c.system.config.Proactive {
+		if c.system.IsQuiet() {
+			fmt.Fprintf(c.system.Output(), "Proactive: %squiet%s\n", output.YellowColor, output.Reset)
+		} else {
+			fmt.Fprintf(c.system.Output(), "Proactive: %son%s\n", output.CyanColor, output.Reset)
+		}
 	}
 	
-	fmt.Printf("Providers: %s%d registered%s\n", output.CyanColor, len(c.system.providers), output.Reset)
+	if remaining, limit := c.system.RateLimitStatus(); limit > 0 {
+		color := output.CyanColor
+		if remaining == 0 {
+			color = output.YellowColor
+		}
+		fmt.Fprintf(c.system.Output(), "Rate limit: %s%d/%d queries available (per minute)%s\n", color, remaining, limit, output.Reset)
+	}
+	
+	fmt.Fprintf(c.system.Output(), "Providers: %s%d registered%s\n", output.CyanColor, len(c.system.providers), output.Reset)
 	for _, provider := range c.system.providers {
-		fmt.Printf("  • %s%s%s\n", output.YellowColor, provider.Name(), output.Reset)
+		fmt.Fprintf(c.system.Output(), "  • %s%s%s\n", output.YellowColor, provider.Name(), output.Reset)
 	}
 	
 	// Show recent actions count
@@ -220,20 +496,20 @@ func (c *IntelCommand) handleStatus(args []string) error {
 	actionCount := len(c.system.context.RecentActions)
 	c.system.context.mu.RUnlock()
 	
-	fmt.Printf("Context: %s%d recent actions%s\n", output.CyanColor, actionCount, output.Reset)
-	fmt.Printf("Session: %s%s%s\n", output.CyanColor, c.system.context.StartTime.Format("15:04:05"), output.Reset)
+	fmt.Fprintf(c.system.Output(), "Context: %s%d recent actions%s\n", output.CyanColor, actionCount, output.Reset)
+	fmt.Fprintf(c.system.Output(), "Session: %s%s%s\n", output.CyanColor, c.system.context.StartTime.Format("15:04:05"), output.Reset)
 	
 	// Show context manager stats
 	if c.system.IsInitialized() {
 		stats := c.system.GetContextStats()
-		fmt.Printf("\n%sContext Manager:%s\n", output.BoldColor, output.Reset)
-		fmt.Printf("Tokens: %s%d/%d (%.1f%%)%s\n", 
+		fmt.Fprintf(c.system.Output(), "\n%sContext Manager:%s\n", output.BoldColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "Tokens: %s%d/%d (%.1f%%)%s\n", 
 			output.CyanColor, 
 			stats["current_tokens"], 
 			stats["max_tokens"], 
 			stats["utilization"].(float64)*100, 
 			output.Reset)
-		fmt.Printf("Items: %s%d total%s\n", 
+		fmt.Fprintf(c.system.Output(), "Items: %s%d total%s\n", 
 			output.CyanColor, 
 			stats["total_items"], 
 			output.Reset)
@@ -246,37 +522,64 @@ func (c *IntelCommand) handleStatus(args []string) error {
 func (c *IntelCommand) showHelp() {
 	style := GetStyleConstants()
 	
-	fmt.Printf("\n%s\n", style.CreateHeader("Intel AI Assistant Commands", "main"))
-	fmt.Printf("  %sstart%s            Initialize the Intel AI system\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %sanalyze [query]%s   Analyze current session or specific query\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %ssuggest [context]%s Get AI suggestions for next steps\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %sexplain <topic>%s   Get detailed explanation of a concept\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %sstatus%s           Show Intel system status and configuration\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %scontext%s          Manage context (clear, stats, limit)\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %svalidate%s         Validate configuration (model, url, rules)\n", output.GreenColor, output.Reset)
-	fmt.Printf("  %shelp%s             Show this help message\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.CreateHeader("Intel AI Assistant Commands", "main"))
+	fmt.Fprintf(c.system.Output(), "  %sstart%s            Initialize the Intel AI system\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sanalyze [query]%s   Analyze current session or specific query\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %ssuggest [context]%s Get AI suggestions for next steps\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sexplain <topic>%s   Get detailed explanation of a concept\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sdebug <problem>%s   Troubleshoot an error using recent failed commands\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sdoctor%s            Check Ollama, the model and a test completion\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sunload%s            Free the model's memory in Ollama\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sstatus%s           Show Intel system status and configuration\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %scontext%s          Manage context (clear, stats, limit, search, pin, unpin, dump, prompts, export, import)\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %svalidate%s         Validate configuration (model, url, rules)\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %scache%s            Manage response cache (stats, clear)\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %ssave <file>%s      Save session context to a file\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sload <file>%s      Restore session context from a file\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sreport <file.md>%s Write the last analysis and findings to a markdown report\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sbench [model...]%s  Compare model latency and throughput (asks before downloading missing models)\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %sbatch <file>%s      Analyze each target listed in file (--out report.md|report.csv)\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %susage%s             Show query and token totals with an estimated cost\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %squiet [on|off]%s   Silence proactive hints for this session\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "  %shelp%s             Show this help message\n", output.GreenColor, output.Reset)
 	
-	fmt.Printf("\n%s\n", style.CreateHeader("Examples", "section"))
-	fmt.Printf("  intel start\n")
-	fmt.Printf("  intel analyze\n")
-	fmt.Printf("  intel suggest next steps\n")
-	fmt.Printf("  intel explain GraphQL injection\n")
-	fmt.Printf("  intel status\n")
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.CreateHeader("Examples", "section"))
+	fmt.Fprintf(c.system.Output(), "  intel start\n")
+	fmt.Fprintf(c.system.Output(), "  intel analyze\n")
+	fmt.Fprintf(c.system.Output(), "  intel suggest next steps\n")
+	fmt.Fprintf(c.system.Output(), "  intel explain GraphQL injection\n")
+	fmt.Fprintf(c.system.Output(), "  intel debug connection refused on port 443\n")
+	fmt.Fprintf(c.system.Output(), "  intel status\n")
 	
-	fmt.Printf("\n%s\n", style.FormatStatus("Intel requires Ollama to be running at " + c.system.config.OllamaURL, "info"))
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.FormatStatus("Intel requires Ollama to be running at " + c.system.config.OllamaURL, "info"))
 }
 
 // handleContext manages context information
 func (c *IntelCommand) handleContext(args []string) error {
+	// Dumping the prompt never calls the model, so it works before 'intel start'
+	if len(args) > 0 && strings.ToLower(args[0]) == "dump" {
+		return c.handleContextDump(args[1:])
+	}
+	if len(args) > 0 && strings.ToLower(args[0]) == "prompts" {
+		c.showPrompts()
+		return nil
+	}
+	if len(args) > 0 && strings.ToLower(args[0]) == "export" {
+		return c.handleContextExport(args[1:])
+	}
+	if len(args) > 0 && strings.ToLower(args[0]) == "import" {
+		return c.handleContextImport(args[1:])
+	}
+	
 	if !c.system.IsInitialized() {
 		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
 	}
 
 	if len(args) == 0 {
 		// Show context summary
-		fmt.Printf("\n%sContext Summary%s\n", output.BoldColor, output.Reset)
-		fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 15), output.Reset)
-		fmt.Printf("%s\n", c.system.GetContextSummary())
+		fmt.Fprintf(c.system.Output(), "\n%sContext Summary%s\n", output.BoldColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 15), output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s\n", c.system.GetContextSummary())
 		return nil
 	}
 
@@ -284,21 +587,35 @@ func (c *IntelCommand) handleContext(args []string) error {
 	switch subcommand {
 	case "clear":
 		c.system.ClearContext()
-		fmt.Printf("%s✓ Context cleared%s\n", output.GreenColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s✓ Context cleared%s\n", output.GreenColor, output.Reset)
 	case "stats":
 		stats := c.system.GetContextStats()
-		fmt.Printf("\n%sContext Statistics%s\n", output.BoldColor, output.Reset)
-		fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 18), output.Reset)
-		fmt.Printf("Total items: %d\n", stats["total_items"])
-		fmt.Printf("Current tokens: %d/%d (%.1f%%)\n", 
+		fmt.Fprintf(c.system.Output(), "\n%sContext Statistics%s\n", output.BoldColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 18), output.Reset)
+		fmt.Fprintf(c.system.Output(), "Total items: %d\n", stats["total_items"])
+		fmt.Fprintf(c.system.Output(), "Current tokens: %d/%d (%.1f%%)\n", 
 			stats["current_tokens"], 
 			stats["max_tokens"], 
 			stats["utilization"].(float64)*100)
+		fmt.Fprintf(c.system.Output(), "Tokenizer: %s\n", stats["tokenizer"])
+		if semantic, _ := stats["semantic"].(bool); semantic {
+			fmt.Fprintf(c.system.Output(), "Semantic ranking: enabled\n")
+		}
 		
 		if byType, ok := stats["by_type"].(map[string]int); ok {
-			fmt.Printf("\nBy type:\n")
+			fmt.Fprintf(c.system.Output(), "\nBy type:\n")
 			for typeName, count := range byType {
-				fmt.Printf("  • %s: %d\n", typeName, count)
+				fmt.Fprintf(c.system.Output(), "  • %s: %d\n", typeName, count)
+			}
+		}
+		
+		if byProvider, ok := stats["by_provider"].(map[string]int); ok && len(byProvider) > 0 {
+			fmt.Fprintf(c.system.Output(), "\nBy provider:\n")
+			for _, provider := range c.system.providers {
+				fmt.Fprintf(c.system.Output(), "  • %s: %d tokens (weight %.1f)\n",
+					provider.Name(),
+					byProvider[provider.Name()],
+					c.system.GetProviderWeight(provider.Name()))
 			}
 		}
 	case "limit":
@@ -316,34 +633,194 @@ func (c *IntelCommand) handleContext(args []string) error {
 		}
 		
 		c.system.SetMaxTokens(limit)
-		fmt.Printf("%s✓ Token limit set to %d%s\n", output.GreenColor, limit, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s✓ Token limit set to %d%s\n", output.GreenColor, limit, output.Reset)
+	case "search":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: intel context search <term>")
+		}
+		return c.handleContextSearch(strings.Join(args[1:], " "))
+	case "pin":
+		if len(args) < 2 {
+			c.listContextItems()
+			fmt.Fprintf(c.system.Output(), "\nUsage: intel context pin <id>\n")
+			return nil
+		}
+		if err := c.system.PinContext(args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.system.Output(), "%s✓ Pinned %s%s\n", output.GreenColor, args[1], output.Reset)
+	case "unpin":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: intel context unpin <id>")
+		}
+		if err := c.system.UnpinContext(args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.system.Output(), "%s✓ Unpinned %s%s\n", output.GreenColor, args[1], output.Reset)
 	default:
-		return fmt.Errorf("unknown context subcommand: %s. Use 'clear', 'stats', or 'limit'", subcommand)
+		return fmt.Errorf("unknown context subcommand: %s. Use 'clear', 'stats', 'limit', 'search', 'pin', 'unpin', 'dump', 'export' or 'import'", subcommand)
 	}
 	
 	return nil
 }
 
+// handleContextExport writes the prompt-building state to a file for sharing
+func (c *IntelCommand) handleContextExport(args []string) error {
+	redact := true
+	var path string
+	for _, arg := range args {
+		if arg == "--include-secrets" {
+			redact = false
+		} else {
+			path = arg
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("usage: intel context export <file.json> [--include-secrets]")
+	}
+
+	if err := c.system.ExportContext(path, redact); err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		}
+		return err
+	}
+
+	fmt.Fprintf(c.system.Output(), "%s✓ Context exported to %s%s\n", output.GreenColor, path, output.Reset)
+	if !redact {
+		fmt.Fprintf(c.system.Output(), "%s⚠️  Sensitive state values were not masked%s\n", output.YellowColor, output.Reset)
+	}
+	return nil
+}
+
+// handleContextImport loads context exported by 'intel context export'
+func (c *IntelCommand) handleContextImport(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: intel context import <file.json>")
+	}
+
+	bundle, err := c.system.ImportContext(args[0])
+	if err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		}
+		return err
+	}
+
+	fmt.Fprintf(c.system.Output(), "%s✓ Imported %d context item(s) and %d prompt(s) from %s%s\n",
+		output.GreenColor, len(bundle.Items), len(bundle.Prompts), args[0], output.Reset)
+	if bundle.Model != "" && bundle.Model != c.system.config.Model {
+		fmt.Fprintf(c.system.Output(), "%s⚠️  Exported with model %s, this session uses %s%s\n",
+			output.YellowColor, bundle.Model, c.system.config.Model, output.Reset)
+	}
+	if bundle.Redacted {
+		fmt.Fprintf(c.system.Output(), "%sSensitive state values in this export are masked%s\n", output.DimColor, output.Reset)
+	}
+	return nil
+}
+
+// snippetRadius is how many characters of context are shown around a search hit
+const snippetRadius = 40
+
+// handleContextSearch prints context items that contain term
+func (c *IntelCommand) handleContextSearch(term string) error {
+	matches := c.system.SearchContext(term)
+	
+	fmt.Fprintf(c.system.Output(), "\n%sContext Search: %s%s\n", output.BoldColor, term, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 16+len(term)), output.Reset)
+	
+	if len(matches) == 0 {
+		fmt.Fprintf(c.system.Output(), "No context items contain '%s'\n", term)
+		return nil
+	}
+	
+	for _, item := range matches {
+		fmt.Fprintf(c.system.Output(), "%s%s%s %s(%s, %d tokens)%s\n",
+			output.YellowColor, item.ID, output.Reset,
+			output.DimColor, item.Type.String(), item.TokenCount, output.Reset)
+		fmt.Fprintf(c.system.Output(), "  %s\n", searchSnippet(item.Content, term))
+	}
+	fmt.Fprintf(c.system.Output(), "\n%d matching item(s)\n", len(matches))
+	return nil
+}
+
+// searchSnippet returns a single-line excerpt of content around the first
+// case-insensitive match of term, with the match highlighted
+func searchSnippet(content, term string) string {
+	flat := strings.Join(strings.Fields(content), " ")
+	lower := strings.ToLower(flat)
+	idx := strings.Index(lower, strings.ToLower(term))
+	if idx < 0 || len(lower) != len(flat) {
+		// Case folding changed byte offsets; fall back to the unhighlighted text
+		return flat
+	}
+	
+	start := idx - snippetRadius
+	prefix := "..."
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	end := idx + len(term) + snippetRadius
+	suffix := "..."
+	if end >= len(flat) {
+		end, suffix = len(flat), ""
+	}
+	
+	// Keep the cut points on rune boundaries
+	for start > 0 && !utf8.RuneStart(flat[start]) {
+		start--
+	}
+	for end < len(flat) && !utf8.RuneStart(flat[end]) {
+		end++
+	}
+	
+	return prefix + flat[start:idx] +
+		output.Colorize(flat[idx:idx+len(term)], output.BoldColor) +
+		flat[idx+len(term):end] + suffix
+}
+
+// listContextItems prints every context item with its ID and pin state
+func (c *IntelCommand) listContextItems() {
+	items := c.system.GetContextItems()
+	if len(items) == 0 {
+		fmt.Fprintf(c.system.Output(), "No context items yet. Run 'intel analyze' to build context.\n")
+		return
+	}
+	
+	table := output.NewTable("ID", "Type", "Tokens", "Status").AlignRight(2)
+	for _, item := range items {
+		status := ""
+		switch {
+		case item.Pinned:
+			status = output.Green("pinned")
+		case item.IsEssential:
+			status = "essential"
+		}
+		table.AddRow(item.ID, item.Type.String(), fmt.Sprintf("%d", item.TokenCount), status)
+	}
+	fmt.Fprint(c.system.Output(), table.Render())
+}
+
 // handleValidate validates configuration
 func (c *IntelCommand) handleValidate(args []string) error {
 	validator := NewConfigValidator()
 	
 	if len(args) == 0 {
 		// Validate current configuration
-		fmt.Printf("\n%sValidating Intel Configuration%s\n", output.BoldColor, output.Reset)
-		fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("=", 30), output.Reset)
+		fmt.Fprintf(c.system.Output(), "\n%sValidating Intel Configuration%s\n", output.BoldColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 30), output.Reset)
 		
 		if err := validator.ValidateConfig(c.system.config); err != nil {
 			if intelErr, ok := err.(*IntelError); ok {
-				intelErr.Display()
+				intelErr.DisplayTo(c.system.Output())
 			} else {
-				fmt.Printf("%s❌ Validation failed: %s%s\n", 
+				fmt.Fprintf(c.system.Output(), "%s❌ Validation failed: %s%s\n", 
 					output.RedColor, err.Error(), output.Reset)
 			}
 			return err
 		}
 		
-		fmt.Printf("%s✓ Configuration is valid%s\n", output.GreenColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s✓ Configuration is valid%s\n", output.GreenColor, output.Reset)
 		return nil
 	}
 	
@@ -355,14 +832,14 @@ func (c *IntelCommand) handleValidate(args []string) error {
 		}
 		
 		modelName := args[1]
-		fmt.Printf("\n%sValidating Model: %s%s\n", output.BoldColor, modelName, output.Reset)
-		fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("-", 20), output.Reset)
+		fmt.Fprintf(c.system.Output(), "\n%sValidating Model: %s%s\n", output.BoldColor, modelName, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("-", 20), output.Reset)
 		
 		if err := validator.ValidateModel(modelName); err != nil {
 			if intelErr, ok := err.(*IntelError); ok {
-				intelErr.Display()
+				intelErr.DisplayTo(c.system.Output())
 			} else {
-				fmt.Printf("%s❌ Model validation failed: %s%s\n", 
+				fmt.Fprintf(c.system.Output(), "%s❌ Model validation failed: %s%s\n", 
 					output.RedColor, err.Error(), output.Reset)
 			}
 			return err
@@ -371,22 +848,22 @@ func (c *IntelCommand) handleValidate(args []string) error {
 		// Check system requirements
 		if err := validator.ValidateSystemRequirements(modelName); err != nil {
 			if intelErr, ok := err.(*IntelError); ok {
-				intelErr.Display()
+				intelErr.DisplayTo(c.system.Output())
 			} else {
-				fmt.Printf("%s⚠️  System requirements: %s%s\n", 
+				fmt.Fprintf(c.system.Output(), "%s⚠️  System requirements: %s%s\n", 
 					output.YellowColor, err.Error(), output.Reset)
 			}
 		}
 		
-		fmt.Printf("%s✓ Model is valid%s\n", output.GreenColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s✓ Model is valid%s\n", output.GreenColor, output.Reset)
 		
 		// Show model info
 		if info, exists := validator.GetModelInfo(modelName); exists {
-			fmt.Printf("\n%sModel Info:%s\n", output.BoldColor, output.Reset)
-			fmt.Printf("Size: %s\n", info.Size)
-			fmt.Printf("Description: %s\n", info.Description)
-			fmt.Printf("Specialty: %s\n", info.Specialty)
-			fmt.Printf("Min RAM: %dGB\n", info.MinRAM)
+			fmt.Fprintf(c.system.Output(), "\n%sModel Info:%s\n", output.BoldColor, output.Reset)
+			fmt.Fprintf(c.system.Output(), "Size: %s\n", info.Size)
+			fmt.Fprintf(c.system.Output(), "Description: %s\n", info.Description)
+			fmt.Fprintf(c.system.Output(), "Specialty: %s\n", info.Specialty)
+			fmt.Fprintf(c.system.Output(), "Min RAM: %dGB\n", info.MinRAM)
 		}
 		
 	case "url":
@@ -395,27 +872,407 @@ func (c *IntelCommand) handleValidate(args []string) error {
 		}
 		
 		url := args[1]
-		fmt.Printf("\n%sValidating URL: %s%s\n", output.BoldColor, url, output.Reset)
-		fmt.Printf("%s%s%s\n", output.CyanColor, strings.Repeat("-", 18), output.Reset)
+		fmt.Fprintf(c.system.Output(), "\n%sValidating URL: %s%s\n", output.BoldColor, url, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("-", 18), output.Reset)
 		
 		if err := validator.ValidateURL(url); err != nil {
 			if intelErr, ok := err.(*IntelError); ok {
-				intelErr.Display()
+				intelErr.DisplayTo(c.system.Output())
 			} else {
-				fmt.Printf("%s❌ URL validation failed: %s%s\n", 
+				fmt.Fprintf(c.system.Output(), "%s❌ URL validation failed: %s%s\n", 
 					output.RedColor, err.Error(), output.Reset)
 			}
 			return err
 		}
 		
-		fmt.Printf("%s✓ URL is valid%s\n", output.GreenColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s✓ URL is valid%s\n", output.GreenColor, output.Reset)
 		
 	case "rules":
-		fmt.Printf("\n%s", validator.GetValidationSummary())
+		fmt.Fprintf(c.system.Output(), "\n%s", validator.GetValidationSummary())
 		
 	default:
 		return fmt.Errorf("unknown validate subcommand: %s. Use 'model', 'url', or 'rules'", subcommand)
 	}
 	
 	return nil
+}
+
+// handleSave persists the current session context to disk
+func (c *IntelCommand) handleSave(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: intel save <file>")
+	}
+	
+	if err := c.system.SaveSession(args[0]); err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		}
+		return err
+	}
+	
+	fmt.Fprintf(c.system.Output(), "%s✓ Session saved to %s%s\n", output.GreenColor, args[0], output.Reset)
+	return nil
+}
+
+// handleLoad restores session context from disk
+func (c *IntelCommand) handleLoad(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: intel load <file>")
+	}
+	
+	if err := c.system.LoadSession(args[0]); err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		}
+		return err
+	}
+	
+	stats := c.system.GetContextStats()
+	fmt.Fprintf(c.system.Output(), "%s✓ Session loaded from %s (%d items, %d tokens)%s\n", 
+		output.GreenColor, args[0], stats["total_items"], stats["current_tokens"], output.Reset)
+	return nil
+}
+
+// handleReport writes the last analysis and current findings to a markdown file
+func (c *IntelCommand) handleReport(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: intel report <file.md>")
+	}
+	
+	if err := c.system.WriteReport(args[0]); err != nil {
+		if intelErr, ok := err.(*IntelError); ok {
+			intelErr.DisplayTo(c.system.Output())
+		}
+		return err
+	}
+	
+	fmt.Fprintf(c.system.Output(), "%s✓ Report written to %s%s\n", output.GreenColor, args[0], output.Reset)
+	return nil
+}
+
+// handleQuiet silences or re-enables proactive hints
+func (c *IntelCommand) handleQuiet(args []string) error {
+	quiet := true
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+		case "off":
+			quiet = false
+		default:
+			return fmt.Errorf("usage: intel quiet [on|off]")
+		}
+	}
+	
+	c.system.SetQuiet(quiet)
+	switch {
+	case !c.system.config.Proactive:
+		fmt.Fprintf(c.system.Output(), "%sProactive mode is disabled in config; nothing to silence%s\n", output.YellowColor, output.Reset)
+	case quiet:
+		fmt.Fprintf(c.system.Output(), "%s✓ Proactive hints silenced%s\n", output.GreenColor, output.Reset)
+	default:
+		fmt.Fprintf(c.system.Output(), "%s✓ Proactive hints enabled%s\n", output.GreenColor, output.Reset)
+	}
+	return nil
+}
+
+// handleBench runs the fixed benchmark prompt against each model and prints
+// a comparison table. Missing models are downloaded once the user confirms
+// each one; --yes skips the question for scripts.
+func (c *IntelCommand) handleBench(args []string) error {
+	if !c.system.IsInitialized() {
+		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
+	}
+	
+	yes := false
+	var models []string
+	for _, arg := range args {
+		switch arg {
+		case "-y", "--yes":
+			yes = true
+		default:
+			models = append(models, arg)
+		}
+	}
+	
+	style := GetStyleConstants()
+	var ready []string
+	
+	// Hosted models need no download; default to the configured one
+	if c.system.UsesHostedBackend() {
+		if len(models) == 0 {
+			models = []string{c.system.config.Model}
+		}
+		ready = models
+		models = nil
+	}
+	
+	var local []string
+	if len(ready) == 0 {
+		var err error
+		if local, err = c.system.LocalModels(); err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			models = local
+		}
+		if len(models) == 0 {
+			return fmt.Errorf("no local models to benchmark. Usage: intel bench [model...] [--yes]")
+		}
+	}
+	
+	available := make(map[string]bool, len(local))
+	for _, name := range local {
+		available[name] = true
+	}
+	
+	for _, model := range models {
+		if available[model] {
+			ready = append(ready, model)
+			continue
+		}
+		if !yes {
+			pull, err := output.Confirm(fmt.Sprintf("Model %s is not downloaded. Pull it now?", model))
+			if err != nil || !pull {
+				fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus(fmt.Sprintf("Skipping %s: not downloaded (re-run with --yes to download it)", model), "warning"))
+				continue
+			}
+		}
+		if err := c.system.PullModel(model); err != nil {
+			fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus(fmt.Sprintf("Skipping %s: %v", model, err), "error"))
+			continue
+		}
+		ready = append(ready, model)
+	}
+	if len(ready) == 0 {
+		return fmt.Errorf("no models available to benchmark")
+	}
+	
+	fmt.Fprintf(c.system.Output(), "\n%sIntel Benchmark%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 15), output.Reset)
+	
+	table := output.NewTable("Model", "First token", "Total", "Tokens", "Tokens/s", "Status").AlignRight(1, 2, 3, 4)
+	for n, model := range ready {
+		fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus(fmt.Sprintf("[%d/%d] Benchmarking %s...", n+1, len(ready), model), "progress"))
+		result := c.system.BenchmarkModel(model)
+		if result.Err != nil {
+			table.AddRow(model, "-", "-", "-", "-", output.Red("failed: "+result.Err.Error()))
+			continue
+		}
+		table.AddRow(model,
+			result.TimeToFirstToken.Round(time.Millisecond).String(),
+			result.Total.Round(time.Millisecond).String(),
+			fmt.Sprintf("%d", result.OutputTokens),
+			fmt.Sprintf("%.1f", result.TokensPerSecond),
+			output.Green("ok"))
+	}
+	
+	fmt.Fprintln(c.system.Output())
+	fmt.Fprint(c.system.Output(), table.Render())
+	return nil
+}
+
+// handleBatch analyzes every target listed in a file, one per line, and
+// prints a summary table or writes a combined report with --out
+func (c *IntelCommand) handleBatch(args []string) error {
+	if !c.system.IsInitialized() {
+		return fmt.Errorf("Intel system not initialized. Run 'intel start' first")
+	}
+	
+	var file, out string
+	for n := 0; n < len(args); n++ {
+		switch {
+		case args[n] == "--out" || args[n] == "-o":
+			if n+1 >= len(args) {
+				return fmt.Errorf("usage: intel batch <file> [--out report.md|report.csv]")
+			}
+			n++
+			out = args[n]
+		case file == "":
+			file = args[n]
+		default:
+			return fmt.Errorf("usage: intel batch <file> [--out report.md|report.csv]")
+		}
+	}
+	if file == "" {
+		return fmt.Errorf("usage: intel batch <file> [--out report.md|report.csv]")
+	}
+	
+	lines, err := utils.LoadWordlist(file)
+	if err != nil {
+		return fmt.Errorf("failed to read targets: %w", err)
+	}
+	var targets []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			targets = append(targets, line)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no targets found in %s", file)
+	}
+	
+	results := c.system.AnalyzeBatch(targets, nil)
+	
+	if out != "" {
+		if err := c.system.WriteBatchReport(out, results); err != nil {
+			if intelErr, ok := err.(*IntelError); ok {
+				intelErr.DisplayTo(c.system.Output())
+			}
+			return err
+		}
+		fmt.Fprintf(c.system.Output(), "%s✓ Batch report for %d targets written to %s%s\n", output.GreenColor, len(results), out, output.Reset)
+		return nil
+	}
+	
+	table := output.NewTable("Target", "Status", "Time", "Summary").AlignRight(2)
+	for _, result := range results {
+		status := output.Green("ok")
+		if result.Err != nil {
+			status = output.Red("failed")
+		}
+		table.AddRow(result.Target, status, result.Duration.Round(100*time.Millisecond).String(), batchSummary(result))
+	}
+	fmt.Fprintln(c.system.Output())
+	fmt.Fprint(c.system.Output(), table.Render())
+	return nil
+}
+
+// handleUsage prints query and token totals for this session and, when an
+// audit log is configured, for every session recorded in it
+func (c *IntelCommand) handleUsage(args []string) error {
+	_, asJSON := output.WantsJSON(args)
+	session, total := c.system.Usage(), c.system.TotalUsage()
+	rate := c.system.config.CostPer1KTokens
+	
+	if asJSON {
+		report := map[string]interface{}{"session": session}
+		if c.system.audit != nil {
+			report["total"] = total
+		}
+		if rate > 0 {
+			report["cost_per_1k_tokens"] = rate
+			report["session_cost"] = session.EstimatedCost(rate)
+			report["total_cost"] = total.EstimatedCost(rate)
+		}
+		return output.JSON(report)
+	}
+	
+	fmt.Fprintf(c.system.Output(), "\n%sIntel Usage%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 11), output.Reset)
+	
+	usages := []Usage{session}
+	headers := []string{"", "This session"}
+	if c.system.audit != nil {
+		usages = append(usages, total)
+		headers = append(headers, "All sessions")
+	}
+	
+	table := output.NewTable(headers...).AlignRight(1, 2)
+	row := func(label string, value func(Usage) string) {
+		cells := []string{label}
+		for _, usage := range usages {
+			cells = append(cells, value(usage))
+		}
+		table.AddRow(cells...)
+	}
+	count := func(n int) string { return fmt.Sprintf("%d", n) }
+	
+	row("Queries", func(u Usage) string { return count(u.Queries) })
+	row("  from cache", func(u Usage) string { return count(u.CachedQueries) })
+	row("  failed", func(u Usage) string { return count(u.FailedQueries) })
+	row("Prompt tokens", func(u Usage) string { return count(u.PromptTokens) })
+	row("Response tokens", func(u Usage) string { return count(u.ResponseTokens) })
+	row("Total tokens", func(u Usage) string { return count(u.TotalTokens()) })
+	if rate > 0 {
+		row("Est. cost", func(u Usage) string { return fmt.Sprintf("$%.4f", u.EstimatedCost(rate)) })
+	}
+	
+	fmt.Fprintln(c.system.Output())
+	fmt.Fprint(c.system.Output(), table.Render())
+	
+	if rate > 0 {
+		fmt.Fprintf(c.system.Output(), "\n%sCost at $%g per 1K tokens%s\n", output.DimColor, rate, output.Reset)
+	} else if c.system.UsesHostedBackend() {
+		fmt.Fprintf(c.system.Output(), "\n%sSet cost_per_1k_tokens in the config to estimate cost%s\n", output.DimColor, output.Reset)
+	}
+	if c.system.audit != nil && !total.Since.IsZero() {
+		fmt.Fprintf(c.system.Output(), "%sAll sessions since %s (from %s)%s\n", output.DimColor,
+			total.Since.Local().Format("2006-01-02 15:04"), c.system.config.AuditLog, output.Reset)
+	}
+	return nil
+}
+
+// handleCache manages the response cache
+func (c *IntelCommand) handleCache(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: intel cache <stats|clear>")
+	}
+	
+	subcommand := strings.ToLower(args[0])
+	switch subcommand {
+	case "clear":
+		c.system.ClearCache()
+		fmt.Fprintf(c.system.Output(), "%s✓ Response cache cleared%s\n", output.GreenColor, output.Reset)
+	case "stats":
+		stats := c.system.GetCacheStats()
+		fmt.Fprintf(c.system.Output(), "\n%sResponse Cache%s\n", output.BoldColor, output.Reset)
+		fmt.Fprintf(c.system.Output(), "%s%s%s\n", output.CyanColor, strings.Repeat("=", 14), output.Reset)
+		
+		if enabled, _ := stats["enabled"].(bool); !enabled {
+			fmt.Fprintf(c.system.Output(), "Caching is disabled. Set cache_size in the Intel config to enable it.\n")
+			return nil
+		}
+		
+		fmt.Fprintf(c.system.Output(), "Entries: %d/%d\n", stats["entries"], stats["capacity"])
+		fmt.Fprintf(c.system.Output(), "Hits: %d\n", stats["hits"])
+		fmt.Fprintf(c.system.Output(), "Misses: %d\n", stats["misses"])
+		fmt.Fprintf(c.system.Output(), "Hit rate: %.1f%%\n", stats["hit_rate"].(float64)*100)
+	default:
+		return fmt.Errorf("unknown cache subcommand: %s. Use 'stats' or 'clear'", subcommand)
+	}
+	
+	return nil
+}
+
+// showPrompts lists the task template each prompt type uses and where it
+// comes from, so overrides can be checked without querying the model
+func (c *IntelCommand) showPrompts() {
+	style := GetStyleConstants()
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.CreateHeader("Effective Prompts", "section"))
+	for _, promptType := range bundlePromptTypes {
+		template := c.system.EffectivePrompt(promptType)
+		if template == "" {
+			fmt.Fprintf(c.system.Output(), "  %s%-8s%s %s(none)%s\n", output.GreenColor, promptType, output.Reset, output.DimColor, output.Reset)
+			continue
+		}
+		fmt.Fprintf(c.system.Output(), "  %s%-8s%s %s[%s]%s %s\n", output.GreenColor, promptType, output.Reset,
+			output.DimColor, c.system.PromptSource(promptType), output.Reset, template)
+	}
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.FormatStatus("Precedence: provider template > config custom_prompts > built-in default", "info"))
+}
+// handleContextDump prints the fully-assembled prompt without querying the model
+func (c *IntelCommand) handleContextDump(args []string) error {
+	promptType := PromptAnalyze
+	if len(args) > 0 {
+		switch PromptType(strings.ToLower(args[0])) {
+		case PromptAnalyze, PromptSuggest, PromptExplain, PromptDebug:
+			promptType = PromptType(strings.ToLower(args[0]))
+			args = args[1:]
+		}
+	}
+	
+	query := "Analyze the current session"
+	if len(args) > 0 {
+		query = strings.Join(args, " ")
+	}
+	
+	prompt := c.system.DumpPrompt(query, promptType)
+	
+	style := GetStyleConstants()
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.CreateHeader(fmt.Sprintf("Prompt Dump (%s)", promptType), "section"))
+	fmt.Fprintln(c.system.Output(), prompt)
+	fmt.Fprintf(c.system.Output(), "\n%s\n", style.CreateSeparator(50, "single"))
+	fmt.Fprintf(c.system.Output(), "%s\n", style.FormatStatus(fmt.Sprintf("%d characters, ~%d tokens", 
+		len(prompt), c.system.contextManager.CountTokens(prompt)), "info"))
+	return nil
 }
\ No newline at end of file
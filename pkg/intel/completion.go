@@ -0,0 +1,68 @@
+package intel
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/command"
+	"github.com/jacobdavidalcock/consolekit/pkg/console"
+)
+
+// CompletionOptions returns the options every CompletionProvider offers for
+// a command, merged and sorted
+func (i *IntelSystem) CompletionOptions(commandName string) []string {
+	i.mu.RLock()
+	providers := make([]ContextProvider, len(i.providers))
+	copy(providers, i.providers)
+	i.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var options []string
+	for _, provider := range providers {
+		completer, ok := provider.(CompletionProvider)
+		if !ok {
+			continue
+		}
+		for _, option := range completer.CompletionOptions()[commandName] {
+			if option != "" && !seen[option] {
+				seen[option] = true
+				options = append(options, option)
+			}
+		}
+	}
+	sort.Strings(options)
+	return options
+}
+
+// CompleteFromProviders makes the first argument of each named command
+// complete from the providers' CompletionOptions, asked afresh on every Tab
+// so options discovered during the session appear straight away. Existing
+// completions for that argument are kept. Call it after the commands are
+// added and before the console runs. Commands that implement
+// command.Completer complete themselves and are left alone.
+func CompleteFromProviders(app *console.Console, intel *IntelSystem, commands ...string) error {
+	for _, name := range commands {
+		cmd, exists := app.Commands.GetCommand(name)
+		if !exists {
+			return fmt.Errorf("unknown command: %s", name)
+		}
+		if _, ok := cmd.Handler.(command.Completer); ok {
+			continue
+		}
+
+		if cmd.Completions == nil {
+			cmd.Completions = make(map[int]command.ArgumentCompletion)
+		}
+		completion := cmd.Completions[0]
+		existing, name := completion.Dynamic, cmd.Name
+		completion.Dynamic = func() []string {
+			options := intel.CompletionOptions(name)
+			if existing != nil {
+				options = append(existing(), options...)
+			}
+			return options
+		}
+		cmd.Completions[0] = completion
+	}
+	return nil
+}
\ No newline at end of file
@@ -1,6 +1,7 @@
 package intel
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -13,17 +14,28 @@ type ContextManager struct {
 	currentTokens int
 	relevanceDecay float64
 	items         []ContextItem
+	tokenizer     Tokenizer
+	embedder      Embedder
+	embeddings    map[string][]float32 // embeddings keyed by content hash
+	similarity    map[string]float64   // item ID -> similarity to the last query
+	pinned        map[string]bool      // item IDs pinned by the user
+	compactInsteadOfDrop bool          // summarize pruned items rather than losing them
 }
 
+// compactedID is the ID of the item summarizing pruned context
+const compactedID = "compacted"
+
 // ContextItem represents a piece of context with metadata
 type ContextItem struct {
-	ID          string
-	Type        ContextType
-	Content     string
-	Timestamp   time.Time
-	Relevance   float64
-	TokenCount  int
-	IsEssential bool
+	ID          string      `json:"id"`
+	Type        ContextType `json:"type"`
+	Content     string      `json:"content"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Relevance   float64     `json:"relevance"`
+	TokenCount  int         `json:"token_count"`
+	IsEssential bool        `json:"is_essential"`
+	Provider    string      `json:"provider,omitempty"` // provider that contributed the item, if any
+	Pinned      bool        `json:"pinned,omitempty"`   // pinned by the user; essential and exempt from decay
 }
 
 // ContextType defines different types of context
@@ -58,6 +70,38 @@ func (ct ContextType) String() string {
 	}
 }
 
+// MarshalText encodes the ContextType by name so persisted sessions survive enum reordering
+func (ct ContextType) MarshalText() ([]byte, error) {
+	return []byte(ct.String()), nil
+}
+
+// UnmarshalText decodes a ContextType from its name
+func (ct *ContextType) UnmarshalText(text []byte) error {
+	parsed, ok := parseContextType(string(text))
+	if !ok {
+		return fmt.Errorf("unknown context type: %s", string(text))
+	}
+	*ct = parsed
+	return nil
+}
+
+// parseContextType returns the ContextType matching a name
+func parseContextType(name string) (ContextType, bool) {
+	for _, ct := range []ContextType{
+		ContextTypeSystem,
+		ContextTypeDomain,
+		ContextTypeState,
+		ContextTypeHistory,
+		ContextTypePrompt,
+		ContextTypeUser,
+	} {
+		if ct.String() == name {
+			return ct, true
+		}
+	}
+	return ContextTypeSystem, false
+}
+
 // NewContextManager creates a new context manager
 func NewContextManager(maxTokens int) *ContextManager {
 	return &ContextManager{
@@ -65,17 +109,152 @@ func NewContextManager(maxTokens int) *ContextManager {
 		currentTokens: 0,
 		relevanceDecay: 0.9, // Decay factor for aging context
 		items:         make([]ContextItem, 0),
+		tokenizer:     heuristicTokenizer{},
+		embeddings:    make(map[string][]float32),
+		similarity:    make(map[string]float64),
+		pinned:        make(map[string]bool),
+		compactInsteadOfDrop: true,
+	}
+}
+
+// clone returns an independent copy of the manager, so a prompt can be
+// built without changing the scores and items the next query sees
+func (cm *ContextManager) clone() *ContextManager {
+	c := *cm
+	c.items = append([]ContextItem(nil), cm.items...)
+	c.embeddings = make(map[string][]float32, len(cm.embeddings))
+	for hash, vector := range cm.embeddings {
+		c.embeddings[hash] = vector
+	}
+	c.similarity = make(map[string]float64, len(cm.similarity))
+	for id, score := range cm.similarity {
+		c.similarity[id] = score
 	}
+	c.pinned = make(map[string]bool, len(cm.pinned))
+	for id := range cm.pinned {
+		c.pinned[id] = true
+	}
+	return &c
+}
+
+// SetCompaction controls what optimize does with the items it prunes: when
+// enabled (the default) they are summarized into a single history item so
+// the model keeps a trace of them, otherwise they are dropped
+func (cm *ContextManager) SetCompaction(enabled bool) {
+	cm.compactInsteadOfDrop = enabled
+}
+
+// SetEmbedder enables semantic relevance scoring; nil disables it
+func (cm *ContextManager) SetEmbedder(embedder Embedder) {
+	cm.embedder = embedder
+	cm.similarity = make(map[string]float64)
 }
 
-// estimateTokens provides a rough estimate of token count for text
+// ScoreQuery embeds the query and any items not yet embedded, and records each
+// item's cosine similarity to the query. It is a no-op without an embedder, and
+// embedding failures leave the previous scores untouched.
+func (cm *ContextManager) ScoreQuery(query string) {
+	if cm.embedder == nil || query == "" {
+		return
+	}
+	
+	// Collect texts whose embeddings aren't cached yet
+	var pending []string
+	seen := make(map[string]bool)
+	for _, text := range append([]string{query}, cm.itemContents()...) {
+		hash := contentHash(text)
+		if _, ok := cm.embeddings[hash]; ok || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		pending = append(pending, text)
+	}
+	
+	if len(pending) > 0 {
+		vectors, err := cm.embedder.Embed(context.Background(), pending)
+		if err != nil {
+			return
+		}
+		for i, text := range pending {
+			cm.embeddings[contentHash(text)] = vectors[i]
+		}
+	}
+	
+	queryVector := cm.embeddings[contentHash(query)]
+	cm.similarity = make(map[string]float64, len(cm.items))
+	for _, item := range cm.items {
+		cm.similarity[item.ID] = cosineSimilarity(queryVector, cm.embeddings[contentHash(item.Content)])
+	}
+	
+	cm.pruneEmbeddings(query)
+}
+
+// itemContents returns the content of every context item
+func (cm *ContextManager) itemContents() []string {
+	contents := make([]string, 0, len(cm.items))
+	for _, item := range cm.items {
+		contents = append(contents, item.Content)
+	}
+	return contents
+}
+
+// pruneEmbeddings drops cached embeddings for content no longer in context
+func (cm *ContextManager) pruneEmbeddings(query string) {
+	if len(cm.embeddings) <= len(cm.items)*2+16 {
+		return
+	}
+	
+	live := map[string]bool{contentHash(query): true}
+	for _, item := range cm.items {
+		live[contentHash(item.Content)] = true
+	}
+	for hash := range cm.embeddings {
+		if !live[hash] {
+			delete(cm.embeddings, hash)
+		}
+	}
+}
+
+// score combines an item's decayed relevance with its similarity to the last query
+func (cm *ContextManager) score(item ContextItem) float64 {
+	similarity, ok := cm.similarity[item.ID]
+	if !ok {
+		return item.Relevance
+	}
+	return item.Relevance*(1-semanticWeight) + similarity*semanticWeight
+}
+
+// SetTokenizer replaces the tokenizer and recounts tokens for existing items
+func (cm *ContextManager) SetTokenizer(tokenizer Tokenizer) {
+	if tokenizer == nil {
+		tokenizer = heuristicTokenizer{}
+	}
+	cm.tokenizer = tokenizer
+	cm.LoadItems(cm.items)
+}
+
+// CountTokens returns the token count for text using the active tokenizer
+func (cm *ContextManager) CountTokens(text string) int {
+	return cm.estimateTokens(text)
+}
+
+// estimateTokens counts tokens with the configured tokenizer, falling back
+// to ~4 characters per token when none is set
 func (cm *ContextManager) estimateTokens(text string) int {
-	// Rough estimation: ~4 characters per token for English text
-	return len(text) / 4
+	if cm.tokenizer == nil {
+		return heuristicTokenizer{}.CountTokens(text)
+	}
+	return cm.tokenizer.CountTokens(text)
 }
 
 // AddContext adds a new context item
 func (cm *ContextManager) AddContext(id string, contextType ContextType, content string, isEssential bool) {
+	cm.AddProviderContext("", id, contextType, content, isEssential)
+}
+
+// AddProviderContext adds a context item attributed to a provider so its
+// token usage can be reported per provider
+func (cm *ContextManager) AddProviderContext(provider, id string, contextType ContextType, content string, isEssential bool) {
 	tokens := cm.estimateTokens(content)
 	
 	item := ContextItem{
@@ -86,6 +265,13 @@ func (cm *ContextManager) AddContext(id string, contextType ContextType, content
 		Relevance:   cm.calculateInitialRelevance(contextType),
 		TokenCount:  tokens,
 		IsEssential: isEssential,
+		Provider:    provider,
+	}
+	
+	// Pins outlive the item being replaced with fresh content
+	if cm.pinned[id] {
+		item.IsEssential = true
+		item.Pinned = true
 	}
 	
 	// Remove existing item with same ID
@@ -101,6 +287,52 @@ func (cm *ContextManager) AddContext(id string, contextType ContextType, content
 	}
 }
 
+// Pin marks an item as essential so optimize and PruneHistory never drop it
+// and its relevance no longer decays. Items that were added as essential
+// are left unchanged.
+func (cm *ContextManager) Pin(id string) error {
+	item := cm.findItem(id)
+	if item == nil {
+		return fmt.Errorf("no context item with ID: %s", id)
+	}
+	if item.IsEssential && !item.Pinned {
+		return nil
+	}
+	item.IsEssential = true
+	item.Pinned = true
+	cm.pinned[id] = true
+	return nil
+}
+
+// Unpin reverses Pin, making the item subject to pruning again
+func (cm *ContextManager) Unpin(id string) error {
+	item := cm.findItem(id)
+	if item == nil {
+		return fmt.Errorf("no context item with ID: %s", id)
+	}
+	if !item.Pinned {
+		return fmt.Errorf("context item is not pinned: %s", id)
+	}
+	item.IsEssential = false
+	item.Pinned = false
+	delete(cm.pinned, id)
+	
+	if cm.currentTokens > cm.maxTokens {
+		cm.optimize()
+	}
+	return nil
+}
+
+// findItem returns a pointer to the item with the given ID, or nil
+func (cm *ContextManager) findItem(id string) *ContextItem {
+	for i := range cm.items {
+		if cm.items[i].ID == id {
+			return &cm.items[i]
+		}
+	}
+	return nil
+}
+
 // calculateInitialRelevance calculates initial relevance based on context type
 func (cm *ContextManager) calculateInitialRelevance(contextType ContextType) float64 {
 	switch contextType {
@@ -142,12 +374,23 @@ func (cm *ContextManager) optimize() {
 		if cm.items[i].IsEssential != cm.items[j].IsEssential {
 			return cm.items[i].IsEssential
 		}
-		return cm.items[i].Relevance > cm.items[j].Relevance
+		return cm.score(cm.items[i]) > cm.score(cm.items[j])
 	})
 	
 	// Remove items until we're under the limit
 	targetTokens := int(float64(cm.maxTokens) * 0.8) // Leave 20% buffer
 	
+	// Reserve room for the summary of what gets pruned, folding in any
+	// earlier summary so it doesn't compete with the items it replaces
+	var dropped []ContextItem
+	if cm.compactInsteadOfDrop {
+		if previous := cm.findItem(compactedID); previous != nil {
+			dropped = append(dropped, *previous)
+			cm.removeItem(compactedID)
+		}
+		targetTokens -= cm.maxTokens / 10
+	}
+	
 	for cm.currentTokens > targetTokens && len(cm.items) > 0 {
 		// Find the least relevant non-essential item
 		var removeIndex = -1
@@ -164,9 +407,77 @@ func (cm *ContextManager) optimize() {
 		}
 		
 		// Remove the item
+		dropped = append(dropped, cm.items[removeIndex])
 		cm.currentTokens -= cm.items[removeIndex].TokenCount
 		cm.items = append(cm.items[:removeIndex], cm.items[removeIndex+1:]...)
 	}
+	
+	if cm.compactInsteadOfDrop && len(dropped) > 0 {
+		summary := cm.trimToTokens(summarizeDropped(dropped), cm.maxTokens/10)
+		if summary != "" {
+			tokens := cm.estimateTokens(summary)
+			cm.items = append(cm.items, ContextItem{
+				ID:         compactedID,
+				Type:       ContextTypeHistory,
+				Content:    summary,
+				Timestamp:  time.Now(),
+				Relevance:  cm.calculateInitialRelevance(ContextTypeHistory),
+				TokenCount: tokens,
+			})
+			cm.currentTokens += tokens
+		}
+	}
+}
+
+// summarizeDropped describes pruned items in one line per context type,
+// e.g. "3 earlier history items omitted: ...", using the first line of
+// each item. Lines from an earlier summary are carried over.
+func summarizeDropped(items []ContextItem) string {
+	var carried []string
+	byType := make(map[ContextType][]string)
+	var order []ContextType
+	for _, item := range items {
+		if item.ID == compactedID {
+			carried = append(carried, strings.Split(item.Content, "\n")...)
+			continue
+		}
+		if _, seen := byType[item.Type]; !seen {
+			order = append(order, item.Type)
+		}
+		byType[item.Type] = append(byType[item.Type], snippet(item))
+	}
+	
+	lines := make([]string, 0, len(order)+len(carried))
+	for _, contextType := range order {
+		snippets := byType[contextType]
+		noun := "item"
+		if len(snippets) > 1 {
+			noun = "items"
+		}
+		lines = append(lines, fmt.Sprintf("%d earlier %s %s omitted: %s",
+			len(snippets), contextType, noun, strings.Join(snippets, "; ")))
+	}
+	// Newest summaries first so trimming drops the oldest
+	return strings.Join(append(lines, carried...), "\n")
+}
+
+// snippet is a short description of an item: its ID and the start of its
+// first non-empty line
+func snippet(item ContextItem) string {
+	line := ""
+	for _, l := range strings.Split(item.Content, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	if runes := []rune(line); len(runes) > 60 {
+		line = string(runes[:57]) + "..."
+	}
+	if line == "" {
+		return item.ID
+	}
+	return fmt.Sprintf("%s (%s)", item.ID, line)
 }
 
 // updateRelevanceScores updates relevance scores based on age and usage
@@ -176,6 +487,11 @@ func (cm *ContextManager) updateRelevanceScores() {
 	for i := range cm.items {
 		item := &cm.items[i]
 		
+		// Pinned items keep their relevance
+		if item.Pinned {
+			continue
+		}
+		
 		// Age-based decay
 		age := now.Sub(item.Timestamp)
 		ageFactor := 1.0
@@ -210,6 +526,7 @@ func (cm *ContextManager) BuildPrompt(userQuery string, promptType PromptType) s
 	
 	// Update relevance scores
 	cm.updateRelevanceScores()
+	cm.ScoreQuery(userQuery)
 	
 	// Sort items by type priority and relevance
 	sortedItems := make([]ContextItem, len(cm.items))
@@ -220,7 +537,7 @@ func (cm *ContextManager) BuildPrompt(userQuery string, promptType PromptType) s
 		if sortedItems[i].Type != sortedItems[j].Type {
 			return cm.getTypePriority(sortedItems[i].Type) > cm.getTypePriority(sortedItems[j].Type)
 		}
-		return sortedItems[i].Relevance > sortedItems[j].Relevance
+		return cm.score(sortedItems[i]) > cm.score(sortedItems[j])
 	})
 	
 	// Build prompt with context items
@@ -268,22 +585,39 @@ func (cm *ContextManager) GetStats() map[string]interface{} {
 		"current_tokens": cm.currentTokens,
 		"max_tokens":     cm.maxTokens,
 		"utilization":    float64(cm.currentTokens) / float64(cm.maxTokens),
+		"tokenizer":      cm.tokenizerName(),
+		"semantic":       cm.embedder != nil,
 	}
 	
 	// Count by type
 	typeCounts := make(map[string]int)
+	providerTokens := make(map[string]int)
 	for _, item := range cm.items {
 		typeCounts[item.Type.String()]++
+		if item.Provider != "" {
+			providerTokens[item.Provider] += item.TokenCount
+		}
 	}
 	stats["by_type"] = typeCounts
+	stats["by_provider"] = providerTokens
 	
 	return stats
 }
 
+// tokenizerName returns the name of the active tokenizer
+func (cm *ContextManager) tokenizerName() string {
+	if cm.tokenizer == nil {
+		return heuristicTokenizer{}.Name()
+	}
+	return cm.tokenizer.Name()
+}
+
 // Clear removes all context items
 func (cm *ContextManager) Clear() {
 	cm.items = make([]ContextItem, 0)
 	cm.currentTokens = 0
+	cm.similarity = make(map[string]float64)
+	cm.pinned = make(map[string]bool)
 }
 
 // GetContextSummary returns a summary of current context
@@ -358,4 +692,50 @@ func (cm *ContextManager) GetMostRelevantItems(count int) []ContextItem {
 	}
 	
 	return sortedItems[:count]
+}
+
+// Search returns items whose content contains query, ignoring case,
+// sorted by relevance with the most relevant first
+func (cm *ContextManager) Search(query string) []ContextItem {
+	query = strings.ToLower(query)
+	matches := make([]ContextItem, 0)
+	for _, item := range cm.items {
+		if strings.Contains(strings.ToLower(item.Content), query) {
+			matches = append(matches, item)
+		}
+	}
+	
+	sort.SliceStable(matches, func(i, j int) bool {
+		return cm.score(matches[i]) > cm.score(matches[j])
+	})
+	return matches
+}
+
+// GetItems returns a copy of all context items
+func (cm *ContextManager) GetItems() []ContextItem {
+	items := make([]ContextItem, len(cm.items))
+	copy(items, cm.items)
+	return items
+}
+
+// LoadItems replaces the current context with the given items, recomputing
+// token counts and re-optimizing against the token budget
+func (cm *ContextManager) LoadItems(items []ContextItem) {
+	cm.items = make([]ContextItem, 0, len(items))
+	cm.currentTokens = 0
+	cm.pinned = make(map[string]bool)
+	
+	for _, item := range items {
+		item.TokenCount = cm.estimateTokens(item.Content)
+		if item.Pinned {
+			item.IsEssential = true
+			cm.pinned[item.ID] = true
+		}
+		cm.items = append(cm.items, item)
+		cm.currentTokens += item.TokenCount
+	}
+	
+	if cm.currentTokens > cm.maxTokens {
+		cm.optimize()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,190 @@
+package intel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// DoctorCheck is the outcome of one step of Doctor
+type DoctorCheck struct {
+	Name     string   `json:"name"`
+	OK       bool     `json:"ok"`
+	Critical bool     `json:"critical"` // Intel can't work while this check fails
+	Skipped  bool     `json:"skipped,omitempty"`
+	Detail   string   `json:"detail"`
+	Fixes    []string `json:"fixes,omitempty"`
+}
+
+// doctorTimeout bounds each network step of Doctor
+const doctorTimeout = 10 * time.Second
+
+// Doctor checks the whole Intel stack in order: Ollama installed, the
+// service reachable at the configured URL, the model present, enough RAM
+// for it and a tiny test completion. Steps that depend on a failed step are
+// skipped. When the model is missing and offerPull returns true it is
+// pulled. Doctor does not need Initialize and changes no state.
+func (i *IntelSystem) Doctor(offerPull func(model string) bool) []DoctorCheck {
+	if isHostedBackend(i.config.Backend) {
+		return i.doctorHosted()
+	}
+
+	var checks []DoctorCheck
+	local := isLocalURL(i.config.OllamaURL)
+
+	// 1. Ollama installed (only meaningful for a local server)
+	installed := DoctorCheck{Name: "Ollama installed", Critical: true, OK: true}
+	if !local {
+		installed.Detail = "remote server, nothing to install"
+	} else if err := i.ollamaManager.checkInstallation(); err != nil {
+		installed.OK = false
+		installed.Detail = err.Error()
+		installed.Fixes = []string{
+			"Run 'intel start' to install it automatically",
+			"Or install manually from https://ollama.com/download",
+		}
+	} else {
+		installed.Detail = i.ollamaManager.GetBinaryPath()
+	}
+	checks = append(checks, installed)
+
+	// 2. Service reachable
+	reachable := DoctorCheck{Name: "Service reachable", Critical: true}
+	client, err := newOllamaClient(i.config)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		var version string
+		version, err = client.Version(ctx)
+		cancel()
+		if err == nil {
+			reachable.OK = true
+			reachable.Detail = fmt.Sprintf("%s (Ollama %s)", i.config.OllamaURL, version)
+		}
+	}
+	if err != nil {
+		reachable.Detail = fmt.Sprintf("%s: %v", i.config.OllamaURL, err)
+		if local {
+			reachable.Fixes = []string{"Start the service: 'ollama serve'", "Or run 'intel start'"}
+		} else {
+			reachable.Fixes = []string{
+				"Check the URL, VPN and firewall",
+				"If the server is behind an authenticating proxy, set headers or URL credentials",
+			}
+		}
+	}
+	checks = append(checks, reachable)
+
+	// 3. Model present
+	present := DoctorCheck{Name: "Model present", Critical: true}
+	if !reachable.OK {
+		present.Skipped = true
+		present.Detail = "skipped: service unreachable"
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		list, err := client.List(ctx)
+		cancel()
+		switch {
+		case err != nil:
+			present.Detail = fmt.Sprintf("failed to list models: %v", err)
+		case hasModel(list, i.config.Model):
+			present.OK = true
+			present.Detail = i.config.Model
+		case offerPull != nil && offerPull(i.config.Model):
+			if err := i.pullModel(context.Background(), client); err != nil {
+				present.Detail = err.Error()
+			} else {
+				present.OK = true
+				present.Detail = i.config.Model + " (downloaded)"
+			}
+		default:
+			present.Detail = fmt.Sprintf("%s is not downloaded", i.config.Model)
+		}
+		if !present.OK {
+			present.Fixes = []string{
+				fmt.Sprintf("Pull it: 'ollama pull %s'", i.config.Model),
+				"Or set model in the intel config to one from 'ollama list'",
+			}
+		}
+	}
+	checks = append(checks, present)
+
+	// 4. Enough RAM for the model (a warning, models can still run slowly)
+	memory := DoctorCheck{Name: "System memory", OK: true}
+	validator := NewConfigValidator()
+	if info, known := validator.knownModels[i.config.Model]; !known {
+		memory.Detail = "requirement unknown for this model"
+	} else if err := validator.ValidateSystemRequirements(i.config.Model); err != nil {
+		memory.OK = false
+		memory.Detail = err.Error()
+		if intelErr, ok := err.(*IntelError); ok {
+			memory.Detail = intelErr.Message
+			memory.Fixes = intelErr.Suggestions
+		}
+	} else {
+		memory.Detail = fmt.Sprintf("~%dGB available, %dGB needed", NewModelManager(nil).EstimateSystemRAM(), info.MinRAM)
+	}
+	checks = append(checks, memory)
+
+	// 5. Test completion
+	checks = append(checks, i.doctorCompletion(client, present.OK))
+	return checks
+}
+
+// doctorHosted checks a hosted backend: an API key and a test completion
+func (i *IntelSystem) doctorHosted() []DoctorCheck {
+	key := DoctorCheck{Name: "API key", Critical: true, OK: apiKeyFor(i.config) != ""}
+	if key.OK {
+		key.Detail = fmt.Sprintf("set for the %s backend", i.config.Backend)
+	} else {
+		key.Detail = fmt.Sprintf("no API key for the %s backend", i.config.Backend)
+		key.Fixes = []string{"Set api_key in the intel config", "Or export ANTHROPIC_API_KEY"}
+	}
+
+	var backend Backend
+	if key.OK {
+		var err error
+		if backend, err = newHostedBackend(i.config); err != nil {
+			key.OK = false
+			key.Detail = err.Error()
+		}
+	}
+	return []DoctorCheck{key, i.doctorCompletion(backend, key.OK)}
+}
+
+// doctorCompletion sends a tiny prompt through backend and expects text back
+func (i *IntelSystem) doctorCompletion(backend Backend, ready bool) DoctorCheck {
+	check := DoctorCheck{Name: "Test completion", Critical: true}
+	if !ready || backend == nil {
+		check.Skipped = true
+		check.Detail = "skipped: model unavailable"
+		return check
+	}
+
+	req := i.newChatRequest("Reply with the single word OK.")
+	req.Options = map[string]interface{}{"num_predict": 8}
+
+	ctx, cancel := context.WithTimeout(context.Background(), i.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var reply strings.Builder
+	err := backend.Chat(ctx, req, func(resp api.ChatResponse) error {
+		reply.WriteString(resp.Message.Content)
+		return nil
+	})
+	switch {
+	case err != nil:
+		check.Detail = err.Error()
+		check.Fixes = HandleError(err).Suggestions
+	case strings.TrimSpace(reply.String()) == "":
+		check.Detail = "model returned empty response"
+		check.Fixes = i.emptyResponseError().Suggestions
+	default:
+		check.OK = true
+		check.Detail = fmt.Sprintf("answered in %s", time.Since(start).Round(10*time.Millisecond))
+	}
+	return check
+}
\ No newline at end of file
@@ -2,7 +2,11 @@ package intel
 
 import (
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
 )
@@ -17,6 +21,7 @@ const (
 	ErrorTypeConfig
 	ErrorTypePrompt
 	ErrorTypeContext
+	ErrorTypeRateLimit
 	ErrorTypeUnknown
 )
 
@@ -35,6 +40,8 @@ func (et ErrorType) String() string {
 		return "Prompt"
 	case ErrorTypeContext:
 		return "Context"
+	case ErrorTypeRateLimit:
+		return "RateLimit"
 	default:
 		return "Unknown"
 	}
@@ -57,29 +64,34 @@ func (ie *IntelError) Error() string {
 
 // Display shows a user-friendly error message with suggestions
 func (ie *IntelError) Display() {
+	ie.DisplayTo(os.Stdout)
+}
+
+// DisplayTo is Display writing to w
+func (ie *IntelError) DisplayTo(w io.Writer) {
 	// Show the main error
-	fmt.Printf("\n%s❌ %s Error:%s %s\n", 
+	fmt.Fprintf(w, "\n%s❌ %s Error:%s %s\n", 
 		output.RedColor, ie.Type, output.Reset, ie.Message)
 	
 	// Show suggestions if available
 	if len(ie.Suggestions) > 0 {
-		fmt.Printf("\n%s💡 Suggestions:%s\n", output.YellowColor, output.Reset)
+		fmt.Fprintf(w, "\n%s💡 Suggestions:%s\n", output.YellowColor, output.Reset)
 		for _, suggestion := range ie.Suggestions {
-			fmt.Printf("  • %s\n", suggestion)
+			fmt.Fprintf(w, "  • %s\n", suggestion)
 		}
 	}
 	
 	// Show context if available
 	if len(ie.Context) > 0 {
-		fmt.Printf("\n%s🔍 Context:%s\n", output.CyanColor, output.Reset)
+		fmt.Fprintf(w, "\n%s🔍 Context:%s\n", output.CyanColor, output.Reset)
 		for key, value := range ie.Context {
-			fmt.Printf("  • %s: %v\n", key, value)
+			fmt.Fprintf(w, "  • %s: %v\n", key, value)
 		}
 	}
 	
 	// Show underlying cause if available
 	if ie.Cause != nil {
-		fmt.Printf("\n%s🔧 Technical Details:%s %s\n", 
+		fmt.Fprintf(w, "\n%s🔧 Technical Details:%s %s\n", 
 			output.CyanColor, output.Reset, ie.Cause.Error())
 	}
 }
@@ -166,6 +178,18 @@ func NewModelError(code, message string, cause error) *IntelError {
 			"Try a smaller model (e.g., 'phi3:3.8b')",
 			"Free up disk space",
 		)
+	case "out_of_memory":
+		err.WithSuggestions(
+			"Switch to a smaller model or a more heavily quantized variant",
+			"Free up memory by closing other applications",
+			"Check 'intel doctor' for the memory the configured model needs",
+		)
+	case "empty_response":
+		err.WithSuggestions(
+			"Run the command again",
+			"Switch to a different model or a less aggressive quantization",
+			"Increase num_predict or the timeout in config",
+		)
 	}
 	
 	return err
@@ -188,6 +212,11 @@ func NewNetworkError(code, message string, cause error) *IntelError {
 			"Verify the service URL",
 			"Check firewall settings",
 		)
+	case "unavailable":
+		err.WithSuggestions(
+			"Wait a moment and try again",
+			"Lower max_queries_per_minute to stay under the provider's limits",
+		)
 	}
 	
 	return err
@@ -221,6 +250,27 @@ func NewConfigError(code, message string, cause error) *IntelError {
 	return err
 }
 
+// NewRateLimitError creates an error for queries rejected by the rate limiter
+func NewRateLimitError(code, message string, cause error) *IntelError {
+	err := NewIntelError(ErrorTypeRateLimit, code, message, cause)
+	
+	switch code {
+	case "exceeded":
+		err.WithSuggestions(
+			"Wait a moment and try again",
+			"Raise max_queries_per_minute in config",
+			"Set rate_limit_wait to queue queries instead of rejecting them",
+		)
+	case "wait_timeout":
+		err.WithSuggestions(
+			"Raise max_queries_per_minute in config",
+			"Increase the timeout for this prompt type",
+		)
+	}
+	
+	return err
+}
+
 // HandleError processes errors and returns structured IntelError
 func HandleError(err error) *IntelError {
 	if err == nil {
@@ -235,6 +285,11 @@ func HandleError(err error) *IntelError {
 	// Parse common error patterns
 	errStr := err.Error()
 	
+	// Ollama couldn't load the model into memory; retrying won't help
+	if isOutOfMemory(errStr) {
+		return outOfMemoryError(err)
+	}
+	
 	// Ollama-related errors
 	if strings.Contains(errStr, "connection refused") || strings.Contains(errStr, "11434") {
 		return NewOllamaError("connection_failed", "Cannot connect to Ollama service", err)
@@ -266,11 +321,45 @@ func HandleError(err error) *IntelError {
 	return NewIntelError(ErrorTypeUnknown, "generic", errStr, err)
 }
 
+// outOfMemoryMarkers are fragments of Ollama errors for models too large
+// for the machine
+var outOfMemoryMarkers = []string{
+	"out of memory",
+	"failed to load model",
+	"requires more system memory",
+}
+
+// isOutOfMemory reports whether an error message says a model didn't fit
+func isOutOfMemory(errStr string) bool {
+	lower := strings.ToLower(errStr)
+	for _, marker := range outOfMemoryMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// outOfMemoryError explains a model that failed to load for lack of memory,
+// naming the detected RAM and a model that fits it
+func outOfMemoryError(cause error) *IntelError {
+	manager := NewModelManager(nil)
+	ram := manager.EstimateSystemRAM()
+	err := NewModelError("out_of_memory", "Model is too large for the available memory", cause).
+		WithContext("available_ram", fmt.Sprintf("%dGB", ram))
+	if smaller := manager.AutoSelectModel("fast"); smaller != "" {
+		err.Suggestions = append([]string{
+			fmt.Sprintf("This system has ~%dGB RAM; set model to '%s' or another model that fits", ram, smaller),
+		}, err.Suggestions...)
+	}
+	return err
+}
+
 // RetryableError indicates if an error can be retried
 func (ie *IntelError) RetryableError() bool {
 	switch ie.Type {
 	case ErrorTypeNetwork:
-		return ie.Code == "timeout" || ie.Code == "connection_refused"
+		return ie.Code == "timeout" || ie.Code == "connection_refused" || ie.Code == "unavailable"
 	case ErrorTypeOllama:
 		return ie.Code == "not_running"
 	case ErrorTypeModel:
@@ -294,17 +383,47 @@ func (ie *IntelError) GetRetryDelay() int {
 	}
 }
 
+// maxRetryDelay caps the exponential backoff between retries
+const maxRetryDelay = 30 * time.Second
+
+// BackoffDelay returns the delay before the given retry attempt (1-based).
+// The base delay from GetRetryDelay doubles with each attempt, gets ±20% jitter
+// so concurrent clients don't retry in lockstep, and is capped at maxRetryDelay.
+func (ie *IntelError) BackoffDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	
+	delay := time.Duration(ie.GetRetryDelay()) * time.Second
+	for n := 1; n < attempt && delay < maxRetryDelay; n++ {
+		delay *= 2
+	}
+	
+	jitter := 0.8 + rand.Float64()*0.4
+	delay = time.Duration(float64(delay) * jitter)
+	
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
 // ShowQuickHelp displays quick help for common errors
 func ShowQuickHelp() {
-	fmt.Printf("\n%s🆘 Quick Help:%s\n", output.BoldColor, output.Reset)
-	fmt.Printf("• %sOllama not found:%s intel status, then install from https://ollama.com\n", 
+	ShowQuickHelpTo(os.Stdout)
+}
+
+// ShowQuickHelpTo is ShowQuickHelp writing to w
+func ShowQuickHelpTo(w io.Writer) {
+	fmt.Fprintf(w, "\n%s🆘 Quick Help:%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(w, "• %sOllama not found:%s intel status, then install from https://ollama.com\n", 
 		output.YellowColor, output.Reset)
-	fmt.Printf("• %sService not running:%s ollama serve, or intel start\n", 
+	fmt.Fprintf(w, "• %sService not running:%s ollama serve, or intel start\n", 
 		output.YellowColor, output.Reset)
-	fmt.Printf("• %sModel not found:%s ollama list, then ollama pull <model>\n", 
+	fmt.Fprintf(w, "• %sModel not found:%s ollama list, then ollama pull <model>\n", 
 		output.YellowColor, output.Reset)
-	fmt.Printf("• %sConnection issues:%s Check firewall, verify URL in config\n", 
+	fmt.Fprintf(w, "• %sConnection issues:%s Check firewall, verify URL in config\n", 
 		output.YellowColor, output.Reset)
-	fmt.Printf("• %sTimeout errors:%s Use smaller model, increase timeout\n", 
+	fmt.Fprintf(w, "• %sTimeout errors:%s Use smaller model, increase timeout\n", 
 		output.YellowColor, output.Reset)
 }
\ No newline at end of file
@@ -0,0 +1,318 @@
+package intel
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/command"
+	"github.com/jacobdavidalcock/consolekit/pkg/console"
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+)
+
+// ExportFormats lists the formats accepted by ExportFile
+var ExportFormats = []string{"csv", "sarif", "json", "markdown"}
+
+// sarifLevels maps finding severities to SARIF result levels
+var sarifLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "note",
+	"info":     "note",
+}
+
+// securitySeverity is the score GitHub code scanning uses to rank alerts
+var securitySeverity = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+	"info":     "0.0",
+}
+
+// ExportCSV writes the findings as CSV with a header row. Evidence is
+// encoded as a JSON object in the last column.
+func (s *FindingStore) ExportCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"severity", "type", "title", "location", "description", "timestamp", "evidence"})
+
+	for _, f := range s.All() {
+		evidence := ""
+		if len(f.Evidence) > 0 {
+			data, err := json.Marshal(f.Evidence)
+			if err != nil {
+				return fmt.Errorf("failed to encode evidence for %q: %w", f.Title, err)
+			}
+			evidence = string(data)
+		}
+		writer.Write([]string{
+			strings.ToLower(f.Severity), f.Type, f.Title, f.Location, f.Description,
+			f.Timestamp.Format(time.RFC3339), evidence,
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// SARIF 2.1.0 document structure, limited to the fields findings populate
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// ExportSARIF writes the findings as a SARIF 2.1.0 log, one rule per finding
+// type. Severity maps to the result level, locations that look like paths
+// or URLs become physical locations and others logical ones, and evidence
+// is kept in the result properties. The driver is named after ToolName.
+func (s *FindingStore) ExportSARIF(w io.Writer) error {
+	tool := s.ToolName
+	if tool == "" {
+		tool = "consolekit"
+	}
+
+	findings := s.All()
+	rules := make(map[string]sarifRule)
+	results := make([]sarifResult, 0, len(findings))
+
+	for _, f := range findings {
+		severity := strings.ToLower(f.Severity)
+		ruleID := f.Type
+		if ruleID == "" {
+			ruleID = "finding"
+		}
+
+		// A rule takes the highest severity among its findings
+		rule, exists := rules[ruleID]
+		if !exists || severityOrder(severity) < severityOrder(rule.Properties["severity"].(string)) {
+			rule = sarifRule{
+				ID:               ruleID,
+				ShortDescription: sarifMessage{Text: ruleID},
+				Properties:       map[string]interface{}{"severity": severity},
+			}
+			if score, ok := securitySeverity[severity]; ok {
+				rule.Properties["security-severity"] = score
+			}
+			rules[ruleID] = rule
+		}
+
+		level, ok := sarifLevels[severity]
+		if !ok {
+			level = "none"
+		}
+
+		message := f.Title
+		if f.Description != "" {
+			message += ": " + f.Description
+		}
+
+		properties := map[string]interface{}{"severity": severity}
+		if !f.Timestamp.IsZero() {
+			properties["timestamp"] = f.Timestamp.Format(time.RFC3339)
+		}
+		if len(f.Evidence) > 0 {
+			properties["evidence"] = f.Evidence
+		}
+
+		result := sarifResult{
+			RuleID:              ruleID,
+			Level:               level,
+			Message:             sarifMessage{Text: message},
+			PartialFingerprints: map[string]string{"consolekitFingerprint/v1": f.Fingerprint()},
+			Properties:          properties,
+		}
+		if f.Location != "" {
+			result.Locations = []sarifLocation{sarifLocationFor(f.Location)}
+		}
+		results = append(results, result)
+	}
+
+	ruleList := make([]sarifRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleList = append(ruleList, rule)
+	}
+	sort.Slice(ruleList, func(a, b int) bool { return ruleList[a].ID < ruleList[b].ID })
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: tool, Rules: ruleList}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(log); err != nil {
+		return fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+	return nil
+}
+
+// sarifLocationFor treats URLs and paths as artifacts and anything else,
+// such as "Query.user", as a logical location
+func sarifLocationFor(location string) sarifLocation {
+	if strings.Contains(location, "://") || strings.ContainsAny(location, `/\`) {
+		return sarifLocation{PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(location)},
+		}}
+	}
+	return sarifLocation{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: location}}}
+}
+
+// ExportFile writes the findings to path in one of ExportFormats. The file
+// is replaced atomically.
+func (s *FindingStore) ExportFile(format, path string) error {
+	var buf bytes.Buffer
+	switch strings.ToLower(format) {
+	case "csv":
+		if err := s.ExportCSV(&buf); err != nil {
+			return err
+		}
+	case "sarif":
+		if err := s.ExportSARIF(&buf); err != nil {
+			return err
+		}
+	case "json":
+		data, err := s.JSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	case "markdown", "md":
+		buf.WriteString(s.Markdown())
+	default:
+		return fmt.Errorf("unknown export format: %s (use %s)", format, strings.Join(ExportFormats, ", "))
+	}
+
+	if err := utils.WriteFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RegisterFindingsCommand adds a "findings" command that summarizes, lists
+// and exports the findings in store
+func RegisterFindingsCommand(app *console.Console, store *FindingStore) {
+	builder := command.NewCompletionBuilder().
+		AddPosition(0, "list", "export").
+		AddPosition(1, ExportFormats...)
+	app.AddCommandWithBuilder("findings", &FindingsCommand{store: store, out: app.Stdout()}, "Summarize, list or export findings", builder)
+}
+
+// FindingsCommand handles "findings", "findings list" and
+// "findings export <format> <file>"
+type FindingsCommand struct {
+	store *FindingStore
+	out   io.Writer // where output is written; nil is os.Stdout
+}
+
+// Execute runs the findings subcommand
+func (c *FindingsCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		c.showSummary()
+		return nil
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list":
+		fmt.Fprint(c.writer(), output.RenderMarkdown(c.store.Markdown()))
+		return nil
+	case "export":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: findings export <%s> <file>", strings.Join(ExportFormats, "|"))
+		}
+		if err := c.store.ExportFile(args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.writer(), "%s Exported %d findings to %s\n", output.Green(StatusSuccess), c.store.Len(), args[2])
+		return nil
+	}
+	return fmt.Errorf("unknown findings command: %s (use list or export)", args[0])
+}
+
+// writer returns where the command prints
+func (c *FindingsCommand) writer() io.Writer {
+	if c.out == nil {
+		return os.Stdout
+	}
+	return c.out
+}
+
+// showSummary prints the number of findings per severity
+func (c *FindingsCommand) showSummary() {
+	counts := c.store.CountBySeverity()
+	fmt.Fprintf(c.writer(), "\n%sFindings: %d%s\n", output.BoldColor, c.store.Len(), output.Reset)
+	for _, severity := range []string{"critical", "high", "medium", "low", "info"} {
+		if counts[severity] > 0 {
+			fmt.Fprintf(c.writer(), "  %-9s %d\n", severity, counts[severity])
+		}
+	}
+}
+
+// Description returns the command description
+func (c *FindingsCommand) Description() string {
+	return "Summarize, list or export findings"
+}
\ No newline at end of file
@@ -0,0 +1,252 @@
+package intel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/httpclient"
+)
+
+// FindingStore collects findings for a tool and answers the common questions
+// about them. Findings with the same fingerprint are stored once. It is safe
+// for concurrent use and its zero value is ready, so providers can embed it
+// and return All() from GetContext.
+type FindingStore struct {
+	ToolName string // names the tool in SARIF exports
+
+	mu       sync.RWMutex
+	findings []Finding
+	index    map[string]int // fingerprint -> position in findings
+}
+
+// Fingerprint identifies a finding by its type, location and title, ignoring
+// case and surrounding whitespace, so re-running a scan yields the same value
+func (f Finding) Fingerprint() string {
+	key := strings.Join([]string{
+		strings.ToLower(strings.TrimSpace(f.Type)),
+		strings.ToLower(strings.TrimSpace(f.Location)),
+		strings.ToLower(strings.TrimSpace(f.Title)),
+	}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// MergeEvidence copies src's evidence into dst, overwriting keys both have
+// with the newer value from src, and returns the merged map
+func MergeEvidence(dst, src map[string]interface{}) map[string]interface{} {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for key, value := range src {
+		dst[key] = value
+	}
+	return dst
+}
+
+// FindingFromResponse builds a finding located at the response's URL with
+// the request, status, headers and start of the body as evidence
+func FindingFromResponse(resp *httpclient.Response, findingType, severity, title string) Finding {
+	return Finding{
+		Type:      findingType,
+		Severity:  severity,
+		Title:     title,
+		Location:  resp.URL,
+		Evidence:  resp.Evidence(),
+		Timestamp: time.Now(),
+	}
+}
+
+// NewFindingStore creates an empty store
+func NewFindingStore() *FindingStore {
+	return &FindingStore{}
+}
+
+// Add records a finding and reports whether it was new. A finding with the
+// fingerprint of one already stored isn't added again; its evidence is merged
+// into the existing finding instead. New findings without a timestamp are
+// stamped with the current time.
+func (s *FindingStore) Add(finding Finding) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index == nil {
+		s.reindex()
+	}
+	fingerprint := finding.Fingerprint()
+	if n, exists := s.index[fingerprint]; exists {
+		existing := &s.findings[n]
+		existing.Evidence = MergeEvidence(copyEvidence(existing.Evidence), finding.Evidence)
+		return false
+	}
+
+	if finding.Timestamp.IsZero() {
+		finding.Timestamp = time.Now()
+	}
+	s.index[fingerprint] = len(s.findings)
+	s.findings = append(s.findings, finding)
+	return true
+}
+
+// AddAll records several findings and returns how many were new
+func (s *FindingStore) AddAll(findings ...Finding) int {
+	added := 0
+	for _, finding := range findings {
+		if s.Add(finding) {
+			added++
+		}
+	}
+	return added
+}
+
+// reindex rebuilds the fingerprint index after the findings were reordered
+func (s *FindingStore) reindex() {
+	s.index = make(map[string]int, len(s.findings))
+	for n, finding := range s.findings {
+		s.index[finding.Fingerprint()] = n
+	}
+}
+
+// copyEvidence returns a shallow copy so merging doesn't modify a map the
+// caller may still hold
+func copyEvidence(evidence map[string]interface{}) map[string]interface{} {
+	if evidence == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(evidence))
+	for key, value := range evidence {
+		copied[key] = value
+	}
+	return copied
+}
+
+// All returns a copy of every finding in the order they are stored
+func (s *FindingStore) All() []Finding {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Finding(nil), s.findings...)
+}
+
+// Len returns the number of findings
+func (s *FindingStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.findings)
+}
+
+// Clear removes all findings
+func (s *FindingStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.findings = nil
+	s.index = nil
+}
+
+// FilterBySeverity returns the findings with any of the given severities,
+// ignoring case
+func (s *FindingStore) FilterBySeverity(severities ...string) []Finding {
+	return s.filter(func(f Finding) bool { return matchesAny(f.Severity, severities) })
+}
+
+// FilterByType returns the findings of any of the given types, ignoring case
+func (s *FindingStore) FilterByType(types ...string) []Finding {
+	return s.filter(func(f Finding) bool { return matchesAny(f.Type, types) })
+}
+
+// filter returns the findings keep accepts
+func (s *FindingStore) filter(keep func(Finding) bool) []Finding {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []Finding
+	for _, finding := range s.findings {
+		if keep(finding) {
+			matched = append(matched, finding)
+		}
+	}
+	return matched
+}
+
+// CountBySeverity tallies findings by lower-cased severity
+func (s *FindingStore) CountBySeverity() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, finding := range s.findings {
+		counts[strings.ToLower(finding.Severity)]++
+	}
+	return counts
+}
+
+// SortBySeverity orders the stored findings from critical to info, keeping
+// the existing order within a severity. Unknown severities sort last.
+func (s *FindingStore) SortBySeverity() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sortBySeverity(s.findings)
+	s.reindex()
+}
+
+// JSON returns the findings as an indented JSON array
+func (s *FindingStore) JSON() ([]byte, error) {
+	findings := s.All()
+	if findings == nil {
+		findings = []Finding{}
+	}
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode findings: %w", err)
+	}
+	return data, nil
+}
+
+// Markdown returns the findings, most severe first, as a markdown table
+func (s *FindingStore) Markdown() string {
+	findings := s.All()
+	if len(findings) == 0 {
+		return "No findings recorded.\n"
+	}
+	sortBySeverity(findings)
+
+	var b strings.Builder
+	writeFindingsTable(&b, findings)
+	return b.String()
+}
+
+// sortBySeverity stably sorts findings from most to least severe
+func sortBySeverity(findings []Finding) {
+	sort.SliceStable(findings, func(a, b int) bool {
+		return severityOrder(findings[a].Severity) < severityOrder(findings[b].Severity)
+	})
+}
+
+// writeFindingsTable writes findings as a markdown table
+func writeFindingsTable(b *strings.Builder, findings []Finding) {
+	b.WriteString("| Severity | Type | Title | Location | Description |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, f := range findings {
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+			escapeTableCell(strings.ToUpper(f.Severity)),
+			escapeTableCell(f.Type),
+			escapeTableCell(f.Title),
+			escapeTableCell(f.Location),
+			escapeTableCell(f.Description))
+	}
+}
+
+// matchesAny reports whether value equals one of options, ignoring case
+func matchesAny(value string, options []string) bool {
+	for _, option := range options {
+		if strings.EqualFold(value, option) {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -2,26 +2,72 @@ package intel
 
 import (
 	"fmt"
-	"regexp"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
+	"github.com/ollama/ollama/api"
 )
 
+// DefaultTypingDelay is the per-character delay of the typing effect
+const DefaultTypingDelay = 15 * time.Millisecond
+
 // StreamingFormatter handles real-time markdown formatting and streaming
 type StreamingFormatter struct {
 	buffer       strings.Builder
-	inCodeBlock  bool
+	renderer     *output.MarkdownRenderer
 	lastOutput   time.Time
 	minDelay     time.Duration
+	typingDelay  time.Duration // per character; 0 prints without animation
+	out          io.Writer
+	processors   *PostProcessorChain
 }
 
 // NewStreamingFormatter creates a new streaming formatter
 func NewStreamingFormatter() *StreamingFormatter {
-	return &StreamingFormatter{
-		minDelay: 30 * time.Millisecond, // Minimum delay between characters for typing effect
+	f := &StreamingFormatter{
+		minDelay:    2 * DefaultTypingDelay, // Minimum delay between tokens for typing effect
+		typingDelay: DefaultTypingDelay,
+		out:         os.Stdout,
+		processors:  DefaultPostProcessors(),
+	}
+	
+	// LLM output gets artifact cleanup before inline markdown formatting
+	f.renderer = output.NewMarkdownRenderer()
+	f.renderer.Preprocess = f.cleanLLMOutput
+	return f
+}
+
+// WithWriter sends the formatter's output to w, such as a console's
+// Stdout() so streamed text doesn't interleave with the prompt
+func (f *StreamingFormatter) WithWriter(w io.Writer) *StreamingFormatter {
+	if w != nil {
+		f.out = w
+	}
+	return f
+}
+
+// WithTypingDelay sets the per-character delay of the typing effect. Lines
+// of a complete response are paced at four times the delay; 0 turns the
+// animation off.
+func (f *StreamingFormatter) WithTypingDelay(delay time.Duration) *StreamingFormatter {
+	if delay < 0 {
+		delay = 0
+	}
+	f.typingDelay = delay
+	f.minDelay = 2 * delay
+	return f
+}
+
+// WithPostProcessors replaces the cleanup applied to model output before
+// rendering; an empty chain renders the output as the model wrote it
+func (f *StreamingFormatter) WithPostProcessors(chain *PostProcessorChain) *StreamingFormatter {
+	if chain != nil {
+		f.processors = chain
 	}
+	return f
 }
 
 // ProcessToken processes a single token from the LLM stream
@@ -35,244 +81,24 @@ func (f *StreamingFormatter) ProcessToken(token string) {
 
 // showTypingEffect displays text with a typing animation
 func (f *StreamingFormatter) showTypingEffect(text string) {
+	if f.typingDelay == 0 {
+		fmt.Fprint(f.out, text)
+		return
+	}
 	if time.Since(f.lastOutput) < f.minDelay {
 		time.Sleep(f.minDelay - time.Since(f.lastOutput))
 	}
 	
 	for _, char := range text {
-		fmt.Print(string(char))
+		fmt.Fprint(f.out, string(char))
 		f.lastOutput = time.Now()
-		time.Sleep(15 * time.Millisecond) // Typing effect delay
-	}
-}
-
-// formatLine applies markdown formatting to a line
-func (f *StreamingFormatter) formatLine(line string) string {
-	// Handle code blocks
-	if strings.HasPrefix(strings.TrimSpace(line), "```") {
-		f.inCodeBlock = !f.inCodeBlock
-		if f.inCodeBlock {
-			return fmt.Sprintf("\n%s╭─ Code Block ──────────────────────────────────────────────────╮%s", 
-				output.CyanColor, output.Reset)
-		} else {
-			return fmt.Sprintf("%s╰──────────────────────────────────────────────────────────────╯%s", 
-				output.CyanColor, output.Reset)
-		}
-	}
-	
-	if f.inCodeBlock {
-		return fmt.Sprintf("%s│ %s%s", output.CyanColor, line, output.Reset)
-	}
-	
-	// Handle headers with ASCII art
-	if strings.HasPrefix(strings.TrimSpace(line), "## ") {
-		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "## "))
-		length := len(text)
-		if length > 60 {
-			length = 60
-		}
-		border := strings.Repeat("─", length+4)
-		return fmt.Sprintf("\n%s╭─%s─╮%s\n%s│ %s%s%s │%s\n%s╰─%s─╯%s", 
-			output.CyanColor, border, output.Reset,
-			output.CyanColor, output.BoldColor, text, output.Reset, output.CyanColor,
-			output.CyanColor, border, output.Reset)
-	}
-	
-	if strings.HasPrefix(strings.TrimSpace(line), "# ") {
-		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "# "))
-		length := len(text)
-		if length > 60 {
-			length = 60
-		}
-		border := strings.Repeat("═", length+4)
-		return fmt.Sprintf("\n%s╔═%s═╗%s\n%s║ %s%s%s ║%s\n%s╚═%s═╝%s", 
-			output.CyanColor, border, output.Reset,
-			output.CyanColor, output.BoldColor, text, output.Reset, output.CyanColor,
-			output.CyanColor, border, output.Reset)
-	}
-	
-	// Handle bullet points with enhanced ASCII
-	if strings.HasPrefix(strings.TrimSpace(line), "- ") {
-		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
-		return fmt.Sprintf("  %s▸%s %s", output.YellowColor, output.Reset, f.formatInlineMarkdown(text))
-	}
-	
-	if strings.HasPrefix(strings.TrimSpace(line), "* ") {
-		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "* "))
-		return fmt.Sprintf("  %s▸%s %s", output.YellowColor, output.Reset, f.formatInlineMarkdown(text))
+		time.Sleep(f.typingDelay)
 	}
-	
-	// Handle numbered lists with enhanced formatting
-	if matched, _ := regexp.MatchString(`^\s*\d+\.\s`, line); matched {
-		re := regexp.MustCompile(`^(\s*)(\d+\.)(\s*)(.*)$`)
-		matches := re.FindStringSubmatch(line)
-		if len(matches) == 5 {
-			indent := matches[1]
-			number := matches[2]
-			space := matches[3]
-			text := matches[4]
-			return fmt.Sprintf("%s%s%s%s%s %s", 
-				indent, output.GreenColor, number, output.Reset, space, f.formatInlineMarkdown(text))
-		}
-	}
-	
-	// Handle long lines by wrapping them
-	if len(line) > 100 {
-		return f.wrapLongLine(line)
-	}
-	
-	// Regular line with inline formatting
-	return f.formatInlineMarkdown(line)
-}
-
-// formatInlineMarkdown handles inline markdown formatting
-func (f *StreamingFormatter) formatInlineMarkdown(text string) string {
-	// Clean up the text first - remove common LLM artifacts
-	text = f.cleanLLMOutput(text)
-	
-	// Handle bold text **text**
-	boldRegex := regexp.MustCompile(`\*\*([^*]+)\*\*`)
-	text = boldRegex.ReplaceAllStringFunc(text, func(match string) string {
-		content := strings.Trim(match, "*")
-		return fmt.Sprintf("%s%s%s", output.BoldColor, content, output.Reset)
-	})
-	
-	// Handle italic text *text* (but avoid conflicting with bold)
-	italicRegex := regexp.MustCompile(`(?:\*\*)?\*([^*]+)\*(?:\*\*)?`)
-	text = italicRegex.ReplaceAllStringFunc(text, func(match string) string {
-		// Skip if this is part of a bold pattern
-		if strings.Contains(match, "**") {
-			return match
-		}
-		content := strings.Trim(match, "*")
-		return fmt.Sprintf("%s%s%s", output.CyanColor, content, output.Reset)
-	})
-	
-	// Handle inline code `code`
-	codeRegex := regexp.MustCompile("`([^`]+)`")
-	text = codeRegex.ReplaceAllStringFunc(text, func(match string) string {
-		content := strings.Trim(match, "`")
-		return fmt.Sprintf("%s%s%s", output.YellowColor, content, output.Reset)
-	})
-	
-	return text
 }
 
-// cleanLLMOutput removes common artifacts and symbols from LLM output
+// cleanLLMOutput runs text through the post-processor chain
 func (f *StreamingFormatter) cleanLLMOutput(text string) string {
-	// Remove common LLM artifacts and formatting symbols
-	patterns := []struct {
-		pattern string
-		replacement string
-	}{
-		// Remove markdown table separators that appear randomly
-		{`\|\s*-+\s*\|`, ""},
-		{`\|\s*:?-+:?\s*\|`, ""},
-		
-		// Clean up excessive formatting
-		{`\*{3,}`, "**"}, // Reduce multiple asterisks to bold
-		{`_{3,}`, "__"},  // Reduce multiple underscores
-		
-		// Remove orphaned formatting characters
-		{`(?:^|\s)\*(?:\s|$)`, " "},     // Standalone asterisks
-		{`(?:^|\s)_(?:\s|$)`, " "},      // Standalone underscores
-		{`(?:^|\s)\|(?:\s|$)`, " "},     // Standalone pipes
-		
-		// Clean up extra whitespace
-		{`\s{3,}`, "  "},                // Reduce multiple spaces
-		{`\n{3,}`, "\n\n"},              // Reduce multiple newlines
-		
-		// Remove common formatting artifacts
-		{`^\s*[\|\-\+\=]{1,}\s*$`, ""}, // Lines with only formatting chars
-		{`^\s*\.\.\.\s*$`, ""},          // Lines with just dots
-		
-		// Fix broken formatting
-		{`\*\s+\*`, ""},                 // Broken asterisks with spaces
-		{`_\s+_`, ""},                   // Broken underscores with spaces
-		
-		// Remove verbose phrases common in LLM output
-		{`(?i)here's?\s+(?:a\s+)?(?:comprehensive\s+)?(?:summary|overview|breakdown|explanation)\s*:?\s*`, ""},
-		{`(?i)let me\s+(?:provide|explain|show|give)\s+(?:you\s+)?(?:a\s+)?`, ""},
-		{`(?i)to\s+(?:help\s+)?(?:you\s+)?(?:understand|get\s+started|begin)`, ""},
-		{`(?i)(?:as\s+)?(?:you\s+)?(?:can\s+)?(?:see|notice|observe)`, ""},
-		{`(?i)(?:it's\s+)?(?:important\s+)?(?:to\s+)?(?:note|remember|keep\s+in\s+mind)\s+that`, ""},
-		{`(?i)(?:please\s+)?(?:also\s+)?(?:note\s+)?(?:that\s+)?(?:these\s+)?(?:steps\s+)?(?:are\s+)?(?:designed\s+)?(?:to\s+)?`, ""},
-		
-		// Remove redundant transitions
-		{`(?i)(?:now\s+)?(?:let's\s+)?(?:next\s+)?(?:step\s+)?(?:we'll\s+)?(?:move\s+)?(?:to\s+)?(?:the\s+)?(?:next\s+)?(?:part\s+)?`, ""},
-		{`(?i)(?:in\s+)?(?:this\s+)?(?:section\s+)?(?:we\s+)?(?:will\s+)?(?:cover\s+)?(?:discuss\s+)?`, ""},
-		
-		// Clean up numbered items format
-		{`(?i)(?:step\s+)?(\d+)\.?\s*[:.]?\s*`, "$1. "},
-		
-		// Remove filler words and phrases
-		{`(?i)(?:essentially|basically|fundamentally|primarily|generally|typically|usually|often|commonly)`, ""},
-		{`(?i)(?:as\s+mentioned|as\s+noted|as\s+discussed)(?:\s+(?:before|above|previously|earlier))?`, ""},
-	}
-	
-	for _, p := range patterns {
-		re := regexp.MustCompile(p.pattern)
-		text = re.ReplaceAllString(text, p.replacement)
-	}
-	
-	// Final cleanup - remove leading/trailing whitespace from lines
-	lines := strings.Split(text, "\n")
-	cleanedLines := make([]string, 0, len(lines))
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Skip empty lines and lines with only formatting
-		if line != "" && !regexp.MustCompile(`^[\s\-\*\|\.]+$`).MatchString(line) {
-			cleanedLines = append(cleanedLines, line)
-		}
-	}
-	
-	text = strings.Join(cleanedLines, "\n")
-	
-	// Remove empty lines at start and end
-	text = strings.TrimSpace(text)
-	
-	return text
-}
-
-// wrapLongLine wraps long lines for better readability
-func (f *StreamingFormatter) wrapLongLine(line string) string {
-	const maxWidth = 80
-	words := strings.Fields(line)
-	if len(words) == 0 {
-		return line
-	}
-	
-	var result strings.Builder
-	var currentLine strings.Builder
-	currentLength := 0
-	
-	for _, word := range words {
-		wordLen := len(word)
-		
-		// If adding this word would exceed max width, start a new line
-		if currentLength > 0 && currentLength+wordLen+1 > maxWidth {
-			result.WriteString(f.formatInlineMarkdown(currentLine.String()))
-			result.WriteString("\n")
-			currentLine.Reset()
-			currentLength = 0
-		}
-		
-		// Add word to current line
-		if currentLength > 0 {
-			currentLine.WriteString(" ")
-			currentLength++
-		}
-		currentLine.WriteString(word)
-		currentLength += wordLen
-	}
-	
-	// Add the last line
-	if currentLength > 0 {
-		result.WriteString(f.formatInlineMarkdown(currentLine.String()))
-	}
-	
-	return result.String()
+	return f.processors.Apply(text)
 }
 
 // Complete finishes the formatting process
@@ -282,9 +108,9 @@ func (f *StreamingFormatter) Complete() {
 	formatted := f.formatCompleteText(completeText)
 	
 	// Clear the screen line and print formatted version
-	fmt.Printf("\r%s\r", strings.Repeat(" ", 80))
-	fmt.Print(formatted)
-	fmt.Print("\n")
+	fmt.Fprintf(f.out, "\r%s\r", strings.Repeat(" ", output.TerminalWidth()))
+	fmt.Fprint(f.out, formatted)
+	fmt.Fprint(f.out, "\n")
 }
 
 // FormatAndDisplayResponse formats and displays a complete response with proper line handling
@@ -297,23 +123,34 @@ func (f *StreamingFormatter) FormatAndDisplayResponse(content string) {
 	
 	lines := strings.Split(content, "\n")
 	
+	var rendered []string
 	for _, line := range lines {
 		// Handle empty lines
 		if strings.TrimSpace(line) == "" {
-			fmt.Printf("\n")
+			rendered = append(rendered, "")
 			continue
 		}
 		
 		// Format the line
-		formatted := f.formatLine(line)
-		
-		// Display the formatted line
-		if formatted != "" {
-			fmt.Printf("%s\n", formatted)
+		if formatted := f.renderer.RenderLine(line); formatted != "" {
+			rendered = append(rendered, formatted)
+		}
+	}
+	
+	// Responses taller than the window go to the pager when enabled
+	if text := strings.Join(rendered, "\n") + "\n"; output.NeedsPaging(text) {
+		if err := output.Page(text); err == nil {
+			return
 		}
+	}
+	
+	for _, line := range rendered {
+		fmt.Fprintf(f.out, "%s\n", line)
 		
-		// Small delay for readability
-		time.Sleep(60 * time.Millisecond)
+		// Small delay for readability, skipped for blank lines
+		if line != "" && f.typingDelay > 0 {
+			time.Sleep(4 * f.typingDelay)
+		}
 	}
 }
 
@@ -353,18 +190,7 @@ func (f *StreamingFormatter) truncateResponse(content string) string {
 
 // formatCompleteText formats the complete response text
 func (f *StreamingFormatter) formatCompleteText(text string) string {
-	lines := strings.Split(text, "\n")
-	var result strings.Builder
-	
-	for _, line := range lines {
-		formatted := f.formatLine(line)
-		result.WriteString(formatted)
-		if !strings.HasSuffix(formatted, "\n") {
-			result.WriteString("\n")
-		}
-	}
-	
-	return result.String()
+	return f.renderer.Render(text)
 }
 
 // GetPersonalityMessage returns a contextual personality message
@@ -397,6 +223,12 @@ func GetPersonalityMessage(context string) string {
 			"Breaking down concept...",
 			"Crafting explanation...",
 		},
+		"debugging": {
+			"Tracing the failure...",
+			"Reading error output...",
+			"Narrowing down causes...",
+			"Checking recent commands...",
+		},
 		"initializing": {
 			"Initializing Intel system...",
 			"Starting AI services...",
@@ -424,69 +256,74 @@ func GetPersonalityMessage(context string) string {
 
 // ShowPersonalityMessage displays a personality message with animation
 func ShowPersonalityMessage(context string) {
-	message := GetPersonalityMessage(context)
-	fmt.Printf("%s%s%s", output.CyanColor, message, output.Reset)
-	
-	// More sophisticated animation like Claude CLI
-	spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-	
-	for i := 0; i < 20; i++ {
-		fmt.Printf("\r%s%s%s %s", output.CyanColor, message, output.Reset, spinner[i%len(spinner)])
-		time.Sleep(100 * time.Millisecond)
-	}
-	fmt.Print("\r")
+	ShowPersonalityMessageTo(os.Stdout, context)
+}
+
+// ShowPersonalityMessageTo is ShowPersonalityMessage drawing to w
+func ShowPersonalityMessageTo(w io.Writer, context string) {
+	spinner := output.NewSpinner(GetPersonalityMessage(context)).WithFrames(output.SpinnerDots).WithWriter(w)
+	spinner.Start()
+	time.Sleep(2 * time.Second)
+	spinner.Stop()
 }
 
 // DownloadTracker tracks and displays download progress
 type DownloadTracker struct {
-	totalSize    int64
-	downloaded   int64
-	startTime    time.Time
-	lastUpdate   time.Time
-	lastPrint    time.Time
-	currentPhase string
-	speedSamples []float64
-	maxSamples   int
+	totalSize     int64
+	downloaded    int64
+	startTime     time.Time
+	layerStart    time.Time
+	lastUpdate    time.Time
+	lastPrint     time.Time
+	currentPhase  string
+	currentDigest string
+	speed         *output.RateSampler
+	out           io.Writer
 }
 
 // NewDownloadTracker creates a new download tracker
 func NewDownloadTracker() *DownloadTracker {
 	return &DownloadTracker{
 		startTime:    time.Now(),
+		layerStart:   time.Now(),
 		lastUpdate:   time.Now(),
 		lastPrint:    time.Now(),
-		speedSamples: make([]float64, 0),
-		maxSamples:   10,
+		speed:        output.NewRateSampler(10),
+		out:          os.Stdout,
+	}
+}
+
+// WithWriter draws the progress display on w
+func (d *DownloadTracker) WithWriter(w io.Writer) *DownloadTracker {
+	if w != nil {
+		d.out = w
 	}
+	return d
 }
 
 // Update processes a progress response from Ollama
-func (d *DownloadTracker) Update(resp interface{}) {
-	// Try to extract progress information safely
-	// Since the API might change, we'll use interface{} and type assertions
+func (d *DownloadTracker) Update(resp api.ProgressResponse) {
 	now := time.Now()
 	
+	d.currentPhase = resp.Status
+	
+	// Each layer is reported under its own digest, so restart the speed
+	// measurement whenever a new layer begins downloading
+	if resp.Digest != d.currentDigest {
+		d.currentDigest = resp.Digest
+		d.layerStart = now
+		d.speed.Reset()
+	}
+	
+	d.downloaded = resp.Completed
+	d.totalSize = resp.Total
+	
 	// Only update display every 100ms to avoid spam
 	if now.Sub(d.lastPrint) < 100*time.Millisecond {
 		return
 	}
 	d.lastPrint = now
 	
-	// Try to extract status and progress information
-	if respMap, ok := resp.(map[string]interface{}); ok {
-		if status, exists := respMap["status"].(string); exists {
-			d.currentPhase = status
-		}
-		
-		if completed, exists := respMap["completed"].(int64); exists {
-			d.downloaded = completed
-		}
-		
-		if total, exists := respMap["total"].(int64); exists {
-			d.totalSize = total
-		}
-	}
-	
 	// Calculate speed
 	if d.totalSize > 0 && d.downloaded > 0 {
 		speed := d.calculateSpeed()
@@ -494,7 +331,7 @@ func (d *DownloadTracker) Update(resp interface{}) {
 		eta := d.calculateETA(speed)
 		
 		// Clear line and show progress
-		fmt.Printf("\r%s[%s] %.1f%% (%.1f MB/s) ETA: %s%s", 
+		fmt.Fprintf(d.out, "\r%s[%s] %.1f%% (%.1f MB/s) ETA: %s%s", 
 			output.CyanColor,
 			d.createProgressBar(percentage),
 			percentage,
@@ -503,7 +340,7 @@ func (d *DownloadTracker) Update(resp interface{}) {
 			output.Reset)
 	} else {
 		// Show phase information when no progress data available
-		fmt.Printf("\r%s%s...%s", output.CyanColor, d.currentPhase, output.Reset)
+		fmt.Fprintf(d.out, "\r%s%s...%s", output.CyanColor, d.currentPhase, output.Reset)
 	}
 	d.lastUpdate = now
 }
@@ -514,25 +351,15 @@ func (d *DownloadTracker) calculateSpeed() float64 {
 		return 0
 	}
 	
-	elapsed := time.Since(d.startTime).Seconds()
+	elapsed := time.Since(d.layerStart).Seconds()
 	if elapsed == 0 {
 		return 0
 	}
 	
 	speed := float64(d.downloaded) / (1024 * 1024) / elapsed // MB/s
 	
-	// Add to samples for smoothing
-	d.speedSamples = append(d.speedSamples, speed)
-	if len(d.speedSamples) > d.maxSamples {
-		d.speedSamples = d.speedSamples[1:]
-	}
-	
-	// Return average speed
-	sum := 0.0
-	for _, s := range d.speedSamples {
-		sum += s
-	}
-	return sum / float64(len(d.speedSamples))
+	// Average over recent samples for smoothing
+	return d.speed.Add(speed)
 }
 
 // calculateETA calculates estimated time of arrival
@@ -545,27 +372,24 @@ func (d *DownloadTracker) calculateETA(speed float64) string {
 	remainingMB := float64(remaining) / (1024 * 1024)
 	eta := time.Duration(remainingMB/speed) * time.Second
 	
-	if eta > time.Hour {
-		return fmt.Sprintf("%.1fh", eta.Hours())
-	} else if eta > time.Minute {
-		return fmt.Sprintf("%.1fm", eta.Minutes())
-	} else {
-		return fmt.Sprintf("%.0fs", eta.Seconds())
-	}
+	return output.FormatETA(eta)
 }
 
 // createProgressBar creates a visual progress bar
 func (d *DownloadTracker) createProgressBar(percentage float64) string {
 	width := 20
 	filled := int(percentage / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
 	return bar
 }
 
 // Complete finishes the download tracking
 func (d *DownloadTracker) Complete() {
-	fmt.Printf("\r%80s\r", "") // Clear line
+	fmt.Fprintf(d.out, "\r%80s\r", "") // Clear line
 	elapsed := time.Since(d.startTime)
-	fmt.Printf("%s✅ Download completed in %s%s\n", 
+	fmt.Fprintf(d.out, "%s✅ Download completed in %s%s\n", 
 		output.GreenColor, elapsed.Round(time.Second), output.Reset)
 }
\ No newline at end of file
@@ -0,0 +1,93 @@
+package intel
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gpuInfo is the cached result of GPU detection
+type gpuInfo struct {
+	name   string
+	vramGB int
+	ok     bool
+}
+
+var (
+	detectedGPU gpuInfo
+	detectOnce  sync.Once
+)
+
+// DetectGPU reports the most capable GPU and its memory in GB. Detection is
+// best effort (nvidia-smi on Linux and Windows, system_profiler on macOS)
+// and runs once per process; ok is false when no GPU was found.
+func (m *ModelManager) DetectGPU() (name string, vramGB int, ok bool) {
+	detectOnce.Do(func() {
+		switch runtime.GOOS {
+		case "darwin":
+			detectedGPU = detectGPUDarwin()
+		default:
+			detectedGPU = detectGPUNvidia()
+		}
+	})
+	return detectedGPU.name, detectedGPU.vramGB, detectedGPU.ok
+}
+
+// detectGPUNvidia parses nvidia-smi output, picking the card with the most memory
+func detectGPUNvidia() gpuInfo {
+	out, err := exec.Command("nvidia-smi", "--query-gpu=name,memory.total", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return gpuInfo{}
+	}
+
+	var best gpuInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 2 {
+			continue
+		}
+		// memory.total is reported in MiB
+		mib, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			continue
+		}
+		if gb := mib / 1024; !best.ok || gb > best.vramGB {
+			best = gpuInfo{name: strings.TrimSpace(fields[0]), vramGB: gb, ok: true}
+		}
+	}
+	return best
+}
+
+var (
+	darwinChipsetRegex = regexp.MustCompile(`Chipset Model:\s*(.+)`)
+	darwinVRAMRegex    = regexp.MustCompile(`VRAM \((?:Total|Dynamic, Max)\):\s*(\d+)\s*(MB|GB)`)
+)
+
+// detectGPUDarwin reads the display section of system_profiler. Apple silicon
+// has unified memory, so Metal can use roughly three quarters of system RAM.
+func detectGPUDarwin() gpuInfo {
+	out, err := exec.Command("system_profiler", "SPDisplaysDataType").Output()
+	if err != nil {
+		return gpuInfo{}
+	}
+
+	match := darwinChipsetRegex.FindSubmatch(out)
+	if match == nil {
+		return gpuInfo{}
+	}
+	info := gpuInfo{name: strings.TrimSpace(string(match[1])), ok: true}
+
+	if vram := darwinVRAMRegex.FindSubmatch(out); vram != nil {
+		size, _ := strconv.Atoi(string(vram[1]))
+		if string(vram[2]) == "MB" {
+			size /= 1024
+		}
+		info.vramGB = size
+	} else if strings.HasPrefix(info.name, "Apple") {
+		info.vramGB = getSystemRAM() * 3 / 4
+	}
+	return info
+}
\ No newline at end of file
@@ -0,0 +1,270 @@
+package intel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/config"
+)
+
+// OfflineBanner is shown above heuristic output when the model is unavailable
+const OfflineBanner = "AI offline — showing heuristic summary"
+
+// maxHeuristicFindings caps how many individual findings are listed
+const maxHeuristicFindings = 5
+
+// severityRank orders finding severities from most to least urgent
+var severityRank = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"info":     4,
+}
+
+// domainNextSteps holds canned next steps for well-known provider domains
+var domainNextSteps = map[string][]string{
+	"graphql": {
+		"Run introspection to map the schema",
+		"Test queries and mutations without authentication",
+		"Check for excessive query depth and batching",
+	},
+	"firebase": {
+		"Check database and storage rules for public read/write",
+		"Enumerate exposed collections and buckets",
+		"Test authentication providers for open sign-up",
+	},
+	"kubernetes": {
+		"List service accounts and their role bindings",
+		"Check for privileged pods and host mounts",
+		"Look for secrets exposed through environment variables",
+	},
+	"web": {
+		"Enumerate endpoints and parameters",
+		"Check authentication and session handling",
+		"Review security headers and TLS configuration",
+	},
+}
+
+// genericNextSteps is used for domains without canned steps
+var genericNextSteps = []string{
+	"Review the current target and configuration",
+	"Run discovery to collect more findings",
+	"Export results before ending the session",
+}
+
+// offlineSnapshot is the provider data heuristics are derived from
+type offlineSnapshot struct {
+	domains  []string
+	findings []Finding
+	state    map[string]map[string]interface{} // provider -> state
+	failed   []Action
+}
+
+// snapshot collects discoveries, state and failed actions from providers
+func (i *IntelSystem) snapshot() *offlineSnapshot {
+	i.mu.RLock()
+	providers := append([]ContextProvider(nil), i.providers...)
+	i.mu.RUnlock()
+
+	snap := &offlineSnapshot{state: make(map[string]map[string]interface{})}
+	seen := make(map[string]bool)
+
+	for _, provider := range providers {
+		if data, err := provider.GetContext(); err == nil && data != nil {
+			domain := strings.ToLower(data.Domain)
+			if domain != "" && !seen[domain] {
+				seen[domain] = true
+				snap.domains = append(snap.domains, domain)
+			}
+			snap.findings = append(snap.findings, data.Discoveries...)
+		}
+		if state := provider.GetCurrentState(); len(state) > 0 {
+			snap.state[provider.Name()] = state
+		}
+	}
+
+	sortBySeverity(snap.findings)
+
+	i.context.mu.RLock()
+	for _, action := range i.context.RecentActions {
+		if !action.Success {
+			snap.failed = append(snap.failed, action)
+		}
+	}
+	i.context.mu.RUnlock()
+
+	return snap
+}
+
+// severityOrder returns the sort rank of a severity, unknown severities last
+func severityOrder(severity string) int {
+	if rank, ok := severityRank[strings.ToLower(severity)]; ok {
+		return rank
+	}
+	return len(severityRank)
+}
+
+// severityCounts tallies findings by lower-cased severity
+func (s *offlineSnapshot) severityCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, finding := range s.findings {
+		counts[strings.ToLower(finding.Severity)]++
+	}
+	return counts
+}
+
+// urgent returns the number of critical and high severity findings
+func (s *offlineSnapshot) urgent() int {
+	counts := s.severityCounts()
+	return counts["critical"] + counts["high"]
+}
+
+// HeuristicAnalyze builds a rule-based session summary from provider data.
+// It needs no model and is used when Intel is offline.
+func (i *IntelSystem) HeuristicAnalyze() *Response {
+	snap := i.snapshot()
+	var b strings.Builder
+
+	b.WriteString("## Findings\n")
+	if len(snap.findings) == 0 {
+		b.WriteString("- No findings recorded yet\n")
+	} else {
+		counts := snap.severityCounts()
+		var parts []string
+		for _, severity := range []string{"critical", "high", "medium", "low", "info"} {
+			if counts[severity] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+			}
+		}
+		fmt.Fprintf(&b, "- %d total: %s\n", len(snap.findings), strings.Join(parts, ", "))
+
+		for n, finding := range snap.findings {
+			if n == maxHeuristicFindings || severityOrder(finding.Severity) > severityRank["high"] {
+				break
+			}
+			line := fmt.Sprintf("- **%s** %s", strings.ToUpper(finding.Severity), finding.Title)
+			if finding.Location != "" {
+				line += fmt.Sprintf(" (`%s`)", finding.Location)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if len(snap.state) > 0 {
+		b.WriteString("\n## State\n")
+		providers := make([]string, 0, len(snap.state))
+		for name := range snap.state {
+			providers = append(providers, name)
+		}
+		sort.Strings(providers)
+		for _, name := range providers {
+			keys := make([]string, 0, len(snap.state[name]))
+			for key := range snap.state[name] {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				value := snap.state[name][key]
+				if config.IsSensitiveKey(key) {
+					value = "********"
+				}
+				fmt.Fprintf(&b, "- %s.%s: %v\n", name, key, value)
+			}
+		}
+	}
+
+	if len(snap.failed) > 0 {
+		b.WriteString("\n## Failed commands\n")
+		for _, action := range snap.failed {
+			fmt.Fprintf(&b, "- `%s`\n", strings.TrimSpace(action.Command+" "+strings.Join(action.Args, " ")))
+		}
+	}
+
+	return &Response{
+		Content:   b.String(),
+		Type:      "analysis",
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"model":          "heuristic",
+			"prompt_type":    "analyze",
+			"provider_count": len(snap.state),
+			"offline":        true,
+		},
+	}
+}
+
+// HeuristicSuggest builds rule-based next steps from findings and each
+// provider's domain. It needs no model and is used when Intel is offline.
+func (i *IntelSystem) HeuristicSuggest() *Suggestions {
+	snap := i.snapshot()
+	suggestions := &Suggestions{
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"model":       "heuristic",
+			"prompt_type": "suggest",
+			"offline":     true,
+		},
+	}
+
+	if urgent := snap.urgent(); urgent > 0 {
+		suggestions.NextSteps = append(suggestions.NextSteps,
+			fmt.Sprintf("Verify and document the %d critical/high severity finding(s) first", urgent))
+	}
+	for _, action := range snap.failed {
+		suggestions.Warnings = append(suggestions.Warnings,
+			fmt.Sprintf("'%s' failed; check its arguments and re-run it", action.Command))
+	}
+
+	matched := false
+	for _, domain := range snap.domains {
+		if steps, ok := domainNextSteps[domain]; ok {
+			suggestions.NextSteps = append(suggestions.NextSteps, steps...)
+			matched = true
+		}
+	}
+	if !matched {
+		suggestions.NextSteps = append(suggestions.NextSteps, genericNextSteps...)
+	}
+
+	if len(snap.findings) == 0 {
+		suggestions.Tips = append(suggestions.Tips, "No findings yet; start with discovery before deeper testing")
+	}
+	suggestions.Tips = append(suggestions.Tips, "Run 'intel start' again once Ollama is reachable for AI analysis")
+
+	return suggestions
+}
+
+// FormatSuggestions renders suggestions as markdown
+func FormatSuggestions(s *Suggestions) string {
+	var b strings.Builder
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Next steps", s.NextSteps},
+		{"Commands", s.Commands},
+		{"Warnings", s.Warnings},
+		{"Tips", s.Tips},
+	}
+
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n", section.title)
+		for n, item := range section.items {
+			if section.title == "Next steps" {
+				fmt.Fprintf(&b, "%d. %s\n", n+1, item)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", item)
+			}
+		}
+	}
+	return b.String()
+}
\ No newline at end of file
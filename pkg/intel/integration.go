@@ -22,6 +22,12 @@ func (i *Integration) WithProvider(provider ContextProvider) *Integration {
 	return i
 }
 
+// WithWeightedProvider adds a context provider with a token budget weight
+func (i *Integration) WithWeightedProvider(provider ContextProvider, weight float64) *Integration {
+	i.system.RegisterProviderWithWeight(provider, weight)
+	return i
+}
+
 // WithConfig updates the Intel system configuration
 func (i *Integration) WithConfig(config *Config) *Integration {
 	i.system.config = config
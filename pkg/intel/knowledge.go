@@ -0,0 +1,77 @@
+package intel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadKnowledgeFromFile reads domain knowledge from a file, or from every
+// .md file in a directory concatenated in filename order, so tools can ship
+// knowledge packs instead of large string literals
+func LoadKnowledgeFromFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", knowledgeReadError(path, err)
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", knowledgeReadError(path, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.md"))
+	if err != nil {
+		return "", knowledgeReadError(path, err)
+	}
+	if len(files) == 0 {
+		return "", NewIntelError(ErrorTypeContext, "knowledge_empty",
+			fmt.Sprintf("No .md knowledge files in %s", path), nil)
+	}
+	sort.Strings(files)
+
+	sections := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", knowledgeReadError(file, err)
+		}
+		if text := strings.TrimSpace(string(data)); text != "" {
+			sections = append(sections, text)
+		}
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// knowledgeReadError reports a knowledge file that couldn't be read
+func knowledgeReadError(path string, err error) error {
+	return NewIntelError(ErrorTypeContext, "knowledge_read_failed",
+		fmt.Sprintf("Failed to read knowledge from %s", path), err).
+		WithSuggestions(
+			"Check that the file or directory exists",
+			"Knowledge directories are read for *.md files",
+		)
+}
+
+// AppendKnowledgeFile adds the knowledge in a file or directory of .md files
+// (see LoadKnowledgeFromFile) after the provider's existing knowledge. Call
+// it several times to combine packs.
+func (b *BaseContextProvider) AppendKnowledgeFile(path string) error {
+	text, err := LoadKnowledgeFromFile(path)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return nil
+	}
+	if strings.TrimSpace(b.knowledge) == "" {
+		b.knowledge = text
+	} else {
+		b.knowledge = strings.TrimRight(b.knowledge, "\n") + "\n\n" + text
+	}
+	return nil
+}
\ No newline at end of file
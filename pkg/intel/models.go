@@ -3,26 +3,32 @@ package intel
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/ollama/ollama/api"
+	"gopkg.in/yaml.v3"
 )
 
 // ModelManager handles LLM model management and selection
 type ModelManager struct {
 	client    *api.Client
 	available []ModelInfo
+	out       io.Writer
 }
 
 // ModelInfo contains information about available models
 type ModelInfo struct {
-	Name        string `json:"name"`
-	Size        string `json:"size"`
-	Description string `json:"description"`
-	Specialty   string `json:"specialty"`
-	MinRAM      int    `json:"min_ram_gb"`
-	Recommended bool   `json:"recommended"`
+	Name        string `json:"name" yaml:"name"`
+	Size        string `json:"size" yaml:"size"`
+	Description string `json:"description" yaml:"description"`
+	Specialty   string `json:"specialty" yaml:"specialty"`
+	MinRAM      int    `json:"min_ram_gb" yaml:"min_ram_gb"`
+	Recommended bool   `json:"recommended" yaml:"recommended"`
 }
 
 // Specialty constants for model categorization
@@ -85,23 +91,119 @@ var RecommendedModels = []ModelInfo{
 	},
 }
 
+// userModels holds models registered at runtime or loaded from models.yaml,
+// shared by every ConfigValidator
+var (
+	userModels   = make(map[string]ModelInfo)
+	userModelsMu sync.RWMutex
+)
+
+// knownModelList returns the curated models followed by registered ones.
+// A registered model replaces a curated model of the same name.
+func knownModelList() []ModelInfo {
+	userModelsMu.RLock()
+	defer userModelsMu.RUnlock()
+
+	models := make([]ModelInfo, 0, len(RecommendedModels)+len(userModels))
+	for _, model := range RecommendedModels {
+		if _, overridden := userModels[model.Name]; !overridden {
+			models = append(models, model)
+		}
+	}
+	for _, model := range userModels {
+		models = append(models, model)
+	}
+	return models
+}
+
+// ModelsFilePath returns the per-user models file for an application,
+// ~/.config/<app>/models.yaml
+func ModelsFilePath(appName string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", appName, "models.yaml")
+}
+
+// LoadModelsFile registers every model listed in a YAML file of the form
+//
+//	models:
+//	  - name: mistral:7b
+//	    size: 4.1GB
+//	    specialty: general
+//	    min_ram_gb: 8
+//
+// and returns how many were registered
+func LoadModelsFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var file struct {
+		Models []ModelInfo `yaml:"models"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return 0, NewConfigError("invalid_models_file",
+			fmt.Sprintf("Failed to parse models file: %s", path), err).
+			WithSuggestions(
+				"List models under a top-level 'models:' key",
+				"Each entry needs at least a 'name'",
+			)
+	}
+
+	validator := NewConfigValidator()
+	for n, model := range file.Models {
+		if err := validator.RegisterModel(model); err != nil {
+			return n, err
+		}
+	}
+	return len(file.Models), nil
+}
+
+// loadUserModels loads the application's models file if it exists. New
+// shows the error as a warning so a bad file never blocks startup.
+func loadUserModels(appName string) error {
+	path := ModelsFilePath(appName)
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	if _, err := LoadModelsFile(path); err != nil {
+		return fmt.Errorf("Models file %s: %w", path, err)
+	}
+	return nil
+}
+
 // NewModelManager creates a new model manager
 func NewModelManager(client *api.Client) *ModelManager {
 	return &ModelManager{
 		client:    client,
 		available: RecommendedModels,
+		out:       os.Stdout,
+	}
+}
+
+// WithWriter sends download messages and progress to w
+func (m *ModelManager) WithWriter(w io.Writer) *ModelManager {
+	if w != nil {
+		m.out = w
 	}
+	return m
 }
 
 // AutoSelectModel chooses the best model based on system resources and preferences
 func (m *ModelManager) AutoSelectModel(preferences ...string) string {
 	// Get system memory
-	var memInfo runtime.MemStats
-	runtime.ReadMemStats(&memInfo)
-	systemRAM := int(memInfo.Sys / (1024 * 1024 * 1024)) // Convert to GB
+	systemRAM := m.EstimateSystemRAM()
 
-	// If system RAM is low, prefer fast models
-	preferFast := systemRAM < 8
+	// A GPU runs larger models comfortably, so only prefer fast models
+	// when RAM is low and there is no GPU to offload to
+	_, vramGB, hasGPU := m.DetectGPU()
+	preferFast := systemRAM < 8 && !hasGPU
 
 	// Check preferences
 	var preferredSpecialty string
@@ -147,6 +249,11 @@ func (m *ModelManager) AutoSelectModel(preferences ...string) string {
 			score += 5
 		}
 
+		// Prefer larger models that fit in GPU memory
+		if hasGPU && model.MinRAM <= vramGB {
+			score += model.MinRAM
+		}
+
 		// Default preference for general models
 		if preferredSpecialty == "" && model.Specialty == SpecialtyGeneral {
 			score += 3
@@ -183,14 +290,14 @@ func (m *ModelManager) EnsureModel(modelName string) error {
 	}
 
 	// Start download
-	fmt.Printf("📥 Downloading model %s...\n", modelName)
+	fmt.Fprintf(m.out, "📥 Downloading model %s...\n", modelName)
 	
 	pullReq := &api.PullRequest{
 		Name: modelName,
 	}
 
 	// Enhanced progress reporting with download tracker
-	tracker := NewDownloadTracker()
+	tracker := NewDownloadTracker().WithWriter(m.out)
 	err := m.client.Pull(ctx, pullReq, func(resp api.ProgressResponse) error {
 		tracker.Update(resp)
 		return nil
@@ -201,7 +308,7 @@ func (m *ModelManager) EnsureModel(modelName string) error {
 	}
 
 	tracker.Complete()
-	fmt.Printf("✅ Model %s downloaded successfully\n", modelName)
+	fmt.Fprintf(m.out, "✅ Model %s downloaded successfully\n", modelName)
 	return nil
 }
 
@@ -242,7 +349,7 @@ func (m *ModelManager) ListAvailableModels() ([]string, error) {
 
 // GetModelInfo returns information about a specific model
 func (m *ModelManager) GetModelInfo(modelName string) (*ModelInfo, bool) {
-	for _, model := range RecommendedModels {
+	for _, model := range knownModelList() {
 		if model.Name == modelName {
 			return &model, true
 		}
@@ -292,20 +399,6 @@ func getSystemRAM() int {
 	return estimateRAMFromRuntime()
 }
 
-// getSystemRAMWindows gets system RAM on Windows using wmic
-func getSystemRAMWindows() int {
-	// This is a simplified approach - in a real implementation you'd use proper Windows APIs
-	// For now, we'll use a reasonable default for Windows systems
-	return 8 // Assume 8GB as a reasonable default for Windows systems
-}
-
-// getSystemRAMUnix gets system RAM on Unix-like systems
-func getSystemRAMUnix() int {
-	// This would typically read from /proc/meminfo on Linux
-	// For now, return 0 to indicate we couldn't determine it
-	return 0
-}
-
 // estimateRAMFromRuntime provides a fallback estimation
 func estimateRAMFromRuntime() int {
 	var memInfo runtime.MemStats
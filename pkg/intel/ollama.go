@@ -21,12 +21,14 @@ type OllamaManager struct {
 	downloadURL  string
 	isInstalled  bool
 	isRunning    bool
+	out          io.Writer // where install and startup progress is printed
 }
 
 // NewOllamaManager creates a new Ollama manager
 func NewOllamaManager() *OllamaManager {
 	manager := &OllamaManager{
 		serviceURL: "http://localhost:11434",
+		out:        os.Stdout,
 	}
 	
 	// Set platform-specific paths and URLs
@@ -35,6 +37,14 @@ func NewOllamaManager() *OllamaManager {
 	return manager
 }
 
+// WithWriter prints install and startup progress to w
+func (om *OllamaManager) WithWriter(w io.Writer) *OllamaManager {
+	if w != nil {
+		om.out = w
+	}
+	return om
+}
+
 // setPlatformDefaults sets OS-specific default paths and URLs
 func (om *OllamaManager) setPlatformDefaults() {
 	switch runtime.GOOS {
@@ -129,7 +139,7 @@ func (om *OllamaManager) checkService() error {
 
 // installOllama attempts to install Ollama automatically
 func (om *OllamaManager) installOllama() error {
-	fmt.Printf("%s🔧 Installing Ollama...%s\n", output.YellowColor, output.Reset)
+	fmt.Fprintf(om.out, "%s🔧 Installing Ollama...%s\n", output.YellowColor, output.Reset)
 	
 	switch runtime.GOOS {
 	case "linux":
@@ -158,7 +168,7 @@ func (om *OllamaManager) installLinux() error {
 	om.binaryPath = "/usr/local/bin/ollama"
 	om.isInstalled = true
 	
-	fmt.Printf("%s✅ Ollama installed successfully%s\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(om.out, "%s✅ Ollama installed successfully%s\n", output.GreenColor, output.Reset)
 	return nil
 }
 
@@ -166,7 +176,7 @@ func (om *OllamaManager) installLinux() error {
 func (om *OllamaManager) installMacOS() error {
 	// Check if Homebrew is available
 	if _, err := exec.LookPath("brew"); err == nil {
-		fmt.Printf("%s📦 Installing via Homebrew...%s\n", output.CyanColor, output.Reset)
+		fmt.Fprintf(om.out, "%s📦 Installing via Homebrew...%s\n", output.CyanColor, output.Reset)
 		cmd := exec.Command("brew", "install", "ollama")
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
@@ -178,7 +188,7 @@ func (om *OllamaManager) installMacOS() error {
 		om.binaryPath = "/opt/homebrew/bin/ollama" // Common Homebrew path
 		om.isInstalled = true
 		
-		fmt.Printf("%s✅ Ollama installed via Homebrew%s\n", output.GreenColor, output.Reset)
+		fmt.Fprintf(om.out, "%s✅ Ollama installed via Homebrew%s\n", output.GreenColor, output.Reset)
 		return nil
 	}
 	
@@ -189,19 +199,19 @@ func (om *OllamaManager) installMacOS() error {
 // installWindows provides Windows installation instructions
 func (om *OllamaManager) installWindows() error {
 	// Windows installation is more complex, provide instructions
-	fmt.Printf("%s⚠️  Automatic installation not available on Windows%s\n", output.YellowColor, output.Reset)
-	fmt.Printf("Please install Ollama manually:\n")
-	fmt.Printf("1. Download from: %s%s%s\n", output.CyanColor, om.downloadURL, output.Reset)
-	fmt.Printf("2. Run the installer\n")
-	fmt.Printf("3. Restart your terminal\n")
-	fmt.Printf("4. Run '%sintel start%s' again\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(om.out, "%s⚠️  Automatic installation not available on Windows%s\n", output.YellowColor, output.Reset)
+	fmt.Fprintf(om.out, "Please install Ollama manually:\n")
+	fmt.Fprintf(om.out, "1. Download from: %s%s%s\n", output.CyanColor, om.downloadURL, output.Reset)
+	fmt.Fprintf(om.out, "2. Run the installer\n")
+	fmt.Fprintf(om.out, "3. Restart your terminal\n")
+	fmt.Fprintf(om.out, "4. Run '%sintel start%s' again\n", output.GreenColor, output.Reset)
 	
 	return fmt.Errorf("manual installation required")
 }
 
 // startOllama attempts to start the Ollama service
 func (om *OllamaManager) startOllama() error {
-	fmt.Printf("%s🚀 Starting Ollama service...%s\n", output.YellowColor, output.Reset)
+	fmt.Fprintf(om.out, "%s🚀 Starting Ollama service...%s\n", output.YellowColor, output.Reset)
 	
 	// Try to start Ollama in the background
 	cmd := exec.Command(om.binaryPath, "serve")
@@ -219,7 +229,7 @@ func (om *OllamaManager) startOllama() error {
 		return fmt.Errorf("Ollama service failed to start properly: %w", err)
 	}
 	
-	fmt.Printf("%s✅ Ollama service started successfully%s\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(om.out, "%s✅ Ollama service started successfully%s\n", output.GreenColor, output.Reset)
 	return nil
 }
 
@@ -253,7 +263,7 @@ func (om *OllamaManager) IsAvailable() bool {
 
 // downloadFile downloads a file from a URL to a local path
 func (om *OllamaManager) downloadFile(url, filepath string) error {
-	fmt.Printf("%s⬇️  Downloading %s...%s\n", output.CyanColor, url, output.Reset)
+	fmt.Fprintf(om.out, "%s⬇️  Downloading %s...%s\n", output.CyanColor, url, output.Reset)
 	
 	// Create the file
 	out, err := os.Create(filepath)
@@ -287,27 +297,27 @@ func (om *OllamaManager) downloadFile(url, filepath string) error {
 		}
 	}
 	
-	fmt.Printf("%s✅ Download completed%s\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(om.out, "%s✅ Download completed%s\n", output.GreenColor, output.Reset)
 	return nil
 }
 
 // ShowManualInstructions displays manual installation instructions
 func (om *OllamaManager) ShowManualInstructions() {
-	fmt.Printf("\n%s📖 Manual Installation Instructions:%s\n", output.BoldColor, output.Reset)
-	fmt.Printf("1. Visit: %s%s%s\n", output.CyanColor, om.downloadURL, output.Reset)
-	fmt.Printf("2. Download the appropriate installer for your OS\n")
-	fmt.Printf("3. Install and restart your terminal\n")
-	fmt.Printf("4. Run '%sintel start%s' again\n", output.GreenColor, output.Reset)
-	fmt.Printf("\nAlternatively, you can install via package managers:\n")
+	fmt.Fprintf(om.out, "\n%s📖 Manual Installation Instructions:%s\n", output.BoldColor, output.Reset)
+	fmt.Fprintf(om.out, "1. Visit: %s%s%s\n", output.CyanColor, om.downloadURL, output.Reset)
+	fmt.Fprintf(om.out, "2. Download the appropriate installer for your OS\n")
+	fmt.Fprintf(om.out, "3. Install and restart your terminal\n")
+	fmt.Fprintf(om.out, "4. Run '%sintel start%s' again\n", output.GreenColor, output.Reset)
+	fmt.Fprintf(om.out, "\nAlternatively, you can install via package managers:\n")
 	
 	switch runtime.GOOS {
 	case "linux":
-		fmt.Printf("• Ubuntu/Debian: Use the official install script\n")
-		fmt.Printf("• Arch Linux: %syay -S ollama%s\n", output.CyanColor, output.Reset)
+		fmt.Fprintf(om.out, "• Ubuntu/Debian: Use the official install script\n")
+		fmt.Fprintf(om.out, "• Arch Linux: %syay -S ollama%s\n", output.CyanColor, output.Reset)
 	case "darwin":
-		fmt.Printf("• Homebrew: %sbrew install ollama%s\n", output.CyanColor, output.Reset)
+		fmt.Fprintf(om.out, "• Homebrew: %sbrew install ollama%s\n", output.CyanColor, output.Reset)
 	case "windows":
-		fmt.Printf("• Chocolatey: %schoco install ollama%s\n", output.CyanColor, output.Reset)
-		fmt.Printf("• Scoop: %sscoop install ollama%s\n", output.CyanColor, output.Reset)
+		fmt.Fprintf(om.out, "• Chocolatey: %schoco install ollama%s\n", output.CyanColor, output.Reset)
+		fmt.Fprintf(om.out, "• Scoop: %sscoop install ollama%s\n", output.CyanColor, output.Reset)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,149 @@
+package intel
+
+import (
+	"regexp"
+	"strings"
+)
+
+// suggestionSection identifies which Suggestions bucket a line belongs to
+type suggestionSection int
+
+const (
+	sectionNextSteps suggestionSection = iota
+	sectionCommands
+	sectionTips
+	sectionWarnings
+)
+
+var (
+	listPrefixRegex   = regexp.MustCompile(`^(?:[-*+▸•]|\d+[.)])\s+`)
+	inlineCodeOnly    = regexp.MustCompile("^`([^`]+)`$")
+	sectionLabelRegex = regexp.MustCompile(`^(?:#{1,6}\s*)?(?:\*\*)?([A-Za-z][A-Za-z ]{0,30}?)(?:\*\*)?\s*:?(?:\*\*)?$`)
+)
+
+// ParseSuggestions splits a markdown model response into NextSteps, Commands, Tips and Warnings.
+// Section headers ("## Commands", "Tips:") select the bucket for the lines beneath them,
+// fenced code blocks are treated as commands and lines marked with ⚠ are treated as warnings.
+func ParseSuggestions(content string) *Suggestions {
+	suggestions := &Suggestions{
+		NextSteps: []string{},
+		Commands:  []string{},
+		Tips:      []string{},
+		Warnings:  []string{},
+	}
+
+	section := sectionNextSteps
+	inCodeBlock := false
+
+	for _, rawLine := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(rawLine)
+
+		// Fenced code blocks always contribute commands
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			if line != "" {
+				suggestions.Commands = append(suggestions.Commands, line)
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		// Section headers switch the active bucket
+		if next, ok := parseSectionHeader(line); ok {
+			section = next
+			continue
+		}
+
+		isListItem := listPrefixRegex.MatchString(line)
+		text := strings.TrimSpace(listPrefixRegex.ReplaceAllString(line, ""))
+		if text == "" {
+			continue
+		}
+
+		// Explicit markers override the active section
+		lower := strings.ToLower(text)
+		switch {
+		case strings.Contains(text, "⚠") || strings.HasPrefix(lower, "warning:"):
+			suggestions.Warnings = append(suggestions.Warnings, cleanMarker(text, "warning:"))
+			continue
+		case strings.HasPrefix(lower, "tip:"):
+			suggestions.Tips = append(suggestions.Tips, cleanMarker(text, "tip:"))
+			continue
+		}
+
+		// A list item that is nothing but inline code is a command
+		if match := inlineCodeOnly.FindStringSubmatch(text); match != nil {
+			suggestions.Commands = append(suggestions.Commands, strings.TrimSpace(match[1]))
+			continue
+		}
+
+		switch section {
+		case sectionCommands:
+			suggestions.Commands = append(suggestions.Commands, strings.Trim(text, "`"))
+		case sectionTips:
+			suggestions.Tips = append(suggestions.Tips, text)
+		case sectionWarnings:
+			suggestions.Warnings = append(suggestions.Warnings, text)
+		default:
+			// Outside an explicit section only list items are treated as steps
+			if isListItem {
+				suggestions.NextSteps = append(suggestions.NextSteps, text)
+			}
+		}
+	}
+
+	// Unstructured replies are kept whole so callers never lose the content
+	if len(suggestions.NextSteps) == 0 && len(suggestions.Commands) == 0 &&
+		len(suggestions.Tips) == 0 && len(suggestions.Warnings) == 0 {
+		if trimmed := strings.TrimSpace(content); trimmed != "" {
+			suggestions.NextSteps = append(suggestions.NextSteps, trimmed)
+		}
+	}
+
+	return suggestions
+}
+
+// parseSectionHeader reports whether a line is a section header and which bucket it selects
+func parseSectionHeader(line string) (suggestionSection, bool) {
+	isHeading := strings.HasPrefix(line, "#")
+	isLabel := strings.HasSuffix(line, ":") || strings.HasSuffix(line, ":**") ||
+		(strings.HasPrefix(line, "**") && strings.HasSuffix(line, "**"))
+	if !isHeading && !isLabel {
+		return sectionNextSteps, false
+	}
+
+	match := sectionLabelRegex.FindStringSubmatch(line)
+	if match == nil {
+		return sectionNextSteps, false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(match[1])) {
+	case "commands", "command", "commands to run", "example commands":
+		return sectionCommands, true
+	case "tips", "tip", "notes", "note", "pro tips":
+		return sectionTips, true
+	case "warnings", "warning", "caution", "risks", "cautions":
+		return sectionWarnings, true
+	case "next steps", "steps", "suggestions", "recommendations", "recommended steps", "actions":
+		return sectionNextSteps, true
+	}
+
+	// Unknown headings reset to the default bucket
+	return sectionNextSteps, isHeading
+}
+
+// cleanMarker strips a leading marker word and warning glyph from a line
+func cleanMarker(text, marker string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "⚠️", ""))
+	text = strings.TrimSpace(strings.ReplaceAll(text, "⚠", ""))
+	if strings.HasPrefix(strings.ToLower(text), marker) {
+		text = strings.TrimSpace(text[len(marker):])
+	}
+	return text
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package intel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSuggestions(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		nextSteps []string
+		commands  []string
+		tips      []string
+		warnings  []string
+	}{
+		{
+			name:      "bullets",
+			content:   "- Enumerate the schema\n* Check authentication\n+ Review the logs\n▸ Retry with a token",
+			nextSteps: []string{"Enumerate the schema", "Check authentication", "Review the logs", "Retry with a token"},
+		},
+		{
+			name:      "numbered",
+			content:   "Here is what to do:\n1. Run introspection\n2) Test mutations",
+			nextSteps: []string{"Run introspection", "Test mutations"},
+		},
+		{
+			name:      "code fenced",
+			content:   "## Next Steps\n- Scan open ports\n\n```bash\nnmap -sV example.com\ncurl -I https://example.com\n```",
+			nextSteps: []string{"Scan open ports"},
+			commands:  []string{"nmap -sV example.com", "curl -I https://example.com"},
+		},
+		{
+			name:     "sections",
+			content:  "**Commands:**\n- `ls -la`\n- grep -r token .\nTips:\n- Use verbose output\n## Warnings\n- Don't scan production",
+			commands: []string{"ls -la", "grep -r token ."},
+			tips:     []string{"Use verbose output"},
+			warnings: []string{"Don't scan production"},
+		},
+		{
+			name:      "inline markers",
+			content:   "- Check the config\n- ⚠️ This is a production host\n- Tip: add -v for detail\n- `whoami`",
+			nextSteps: []string{"Check the config"},
+			commands:  []string{"whoami"},
+			tips:      []string{"add -v for detail"},
+			warnings:  []string{"This is a production host"},
+		},
+		{
+			name:      "malformed prose",
+			content:   "  Just run the scanner again and see what happens.  ",
+			nextSteps: []string{"Just run the scanner again and see what happens."},
+		},
+		{
+			name:     "unterminated fence",
+			content:  "```\nnmap example.com\n\nnikto -h example.com",
+			commands: []string{"nmap example.com", "nikto -h example.com"},
+		},
+		{
+			name:    "empty",
+			content: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSuggestions(tt.content)
+			check := func(field string, got, want []string) {
+				if want == nil {
+					want = []string{}
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %q, want %q", field, got, want)
+				}
+			}
+			check("NextSteps", got.NextSteps, tt.nextSteps)
+			check("Commands", got.Commands, tt.commands)
+			check("Tips", got.Tips, tt.tips)
+			check("Warnings", got.Warnings, tt.warnings)
+		})
+	}
+}
\ No newline at end of file
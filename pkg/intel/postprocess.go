@@ -0,0 +1,183 @@
+package intel
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// PostProcessor rewrites model output before it is rendered
+type PostProcessor func(text string) string
+
+// Names of the default post-processors, in the order they run
+const (
+	PostProcessMarkup     = "markup"     // stray table separators and formatting characters
+	PostProcessWhitespace = "whitespace" // runs of spaces and blank lines
+	PostProcessFiller     = "filler"     // verbose phrases, transitions and filler words
+	PostProcessNumbering  = "numbering"  // "Step 1:" style items become "1. "
+	PostProcessLines      = "lines"      // trims lines and drops empty or formatting-only ones
+)
+
+// PostProcessorChain is an ordered set of named post-processors. It is safe
+// for concurrent use.
+type PostProcessorChain struct {
+	names      []string
+	processors []PostProcessor
+	mu         sync.RWMutex
+}
+
+// DefaultPostProcessors returns the chain the formatter applies to model
+// output unless told otherwise
+func DefaultPostProcessors() *PostProcessorChain {
+	chain := &PostProcessorChain{}
+	chain.Add(PostProcessMarkup, replacer(markupPatterns))
+	chain.Add(PostProcessWhitespace, replacer(whitespacePatterns))
+	chain.Add(PostProcessFiller, replacer(fillerPatterns))
+	chain.Add(PostProcessNumbering, replacer(numberingPatterns))
+	chain.Add(PostProcessLines, cleanLines)
+	return chain
+}
+
+// Add appends a post-processor to the end of the chain, or replaces the
+// one already registered under name in place
+func (c *PostProcessorChain) Add(name string, processor PostProcessor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for n, existing := range c.names {
+		if existing == name {
+			c.processors[n] = processor
+			return
+		}
+	}
+	c.names = append(c.names, name)
+	c.processors = append(c.processors, processor)
+}
+
+// Remove drops the post-processor registered under name and reports
+// whether there was one
+func (c *PostProcessorChain) Remove(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for n, existing := range c.names {
+		if existing == name {
+			c.names = append(c.names[:n], c.names[n+1:]...)
+			c.processors = append(c.processors[:n], c.processors[n+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the registered post-processors in the order they run
+func (c *PostProcessorChain) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.names...)
+}
+
+// Apply runs text through every post-processor in order
+func (c *PostProcessorChain) Apply(text string) string {
+	c.mu.RLock()
+	processors := append([]PostProcessor(nil), c.processors...)
+	c.mu.RUnlock()
+
+	for _, process := range processors {
+		text = process(text)
+	}
+	return text
+}
+
+// Clone returns an independent copy of the chain
+func (c *PostProcessorChain) Clone() *PostProcessorChain {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &PostProcessorChain{
+		names:      append([]string(nil), c.names...),
+		processors: append([]PostProcessor(nil), c.processors...),
+	}
+}
+
+// rewrite is a regular expression replacement applied by a post-processor
+type rewrite struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// replacer returns a post-processor applying rewrites in order
+func replacer(rewrites []rewrite) PostProcessor {
+	return func(text string) string {
+		for _, r := range rewrites {
+			text = r.pattern.ReplaceAllString(text, r.replacement)
+		}
+		return text
+	}
+}
+
+var markupPatterns = []rewrite{
+	// Remove markdown table separators that appear randomly
+	{regexp.MustCompile(`\|\s*-+\s*\|`), ""},
+	{regexp.MustCompile(`\|\s*:?-+:?\s*\|`), ""},
+
+	// Clean up excessive formatting
+	{regexp.MustCompile(`\*{3,}`), "**"}, // Reduce multiple asterisks to bold
+	{regexp.MustCompile(`_{3,}`), "__"},  // Reduce multiple underscores
+
+	// Remove orphaned formatting characters
+	{regexp.MustCompile(`(?:^|\s)\*(?:\s|$)`), " "}, // Standalone asterisks
+	{regexp.MustCompile(`(?:^|\s)_(?:\s|$)`), " "},  // Standalone underscores
+	{regexp.MustCompile(`(?:^|\s)\|(?:\s|$)`), " "}, // Standalone pipes
+
+	// Remove common formatting artifacts
+	{regexp.MustCompile(`^\s*[\|\-\+\=]{1,}\s*$`), ""}, // Lines with only formatting chars
+	{regexp.MustCompile(`^\s*\.\.\.\s*$`), ""},         // Lines with just dots
+
+	// Fix broken formatting
+	{regexp.MustCompile(`\*\s+\*`), ""}, // Broken asterisks with spaces
+	{regexp.MustCompile(`_\s+_`), ""},   // Broken underscores with spaces
+}
+
+var whitespacePatterns = []rewrite{
+	{regexp.MustCompile(`\s{3,}`), "  "},    // Reduce multiple spaces
+	{regexp.MustCompile(`\n{3,}`), "\n\n"},  // Reduce multiple newlines
+}
+
+var fillerPatterns = []rewrite{
+	// Remove verbose phrases common in LLM output
+	{regexp.MustCompile(`(?i)here's?\s+(?:a\s+)?(?:comprehensive\s+)?(?:summary|overview|breakdown|explanation)\s*:?\s*`), ""},
+	{regexp.MustCompile(`(?i)let me\s+(?:provide|explain|show|give)\s+(?:you\s+)?(?:a\s+)?`), ""},
+	{regexp.MustCompile(`(?i)to\s+(?:help\s+)?(?:you\s+)?(?:understand|get\s+started|begin)`), ""},
+	{regexp.MustCompile(`(?i)(?:as\s+)?(?:you\s+)?(?:can\s+)?(?:see|notice|observe)`), ""},
+	{regexp.MustCompile(`(?i)(?:it's\s+)?(?:important\s+)?(?:to\s+)?(?:note|remember|keep\s+in\s+mind)\s+that`), ""},
+	{regexp.MustCompile(`(?i)(?:please\s+)?(?:also\s+)?(?:note\s+)?(?:that\s+)?(?:these\s+)?(?:steps\s+)?(?:are\s+)?(?:designed\s+)?(?:to\s+)?`), ""},
+
+	// Remove redundant transitions
+	{regexp.MustCompile(`(?i)(?:now\s+)?(?:let's\s+)?(?:next\s+)?(?:step\s+)?(?:we'll\s+)?(?:move\s+)?(?:to\s+)?(?:the\s+)?(?:next\s+)?(?:part\s+)?`), ""},
+	{regexp.MustCompile(`(?i)(?:in\s+)?(?:this\s+)?(?:section\s+)?(?:we\s+)?(?:will\s+)?(?:cover\s+)?(?:discuss\s+)?`), ""},
+
+	// Remove filler words and phrases
+	{regexp.MustCompile(`(?i)(?:essentially|basically|fundamentally|primarily|generally|typically|usually|often|commonly)`), ""},
+	{regexp.MustCompile(`(?i)(?:as\s+mentioned|as\s+noted|as\s+discussed)(?:\s+(?:before|above|previously|earlier))?`), ""},
+}
+
+var numberingPatterns = []rewrite{
+	{regexp.MustCompile(`(?i)(?:step\s+)?(\d+)\.?\s*[:.]?\s*`), "$1. "},
+}
+
+// formattingOnlyLine matches lines made only of formatting characters
+var formattingOnlyLine = regexp.MustCompile(`^[\s\-\*\|\.]+$`)
+
+// cleanLines trims each line and drops empty and formatting-only lines
+func cleanLines(text string) string {
+	lines := strings.Split(text, "\n")
+	cleanedLines := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !formattingOnlyLine.MatchString(line) {
+			cleanedLines = append(cleanedLines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(cleanedLines, "\n"))
+}
\ No newline at end of file
@@ -0,0 +1,116 @@
+package intel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/output"
+)
+
+// DefaultProactiveInterval is the minimum gap between proactive hints when
+// Config.ProactiveInterval is not set
+const DefaultProactiveInterval = 2 * time.Minute
+
+// discoveryCommandWords mark commands whose output is worth analyzing
+var discoveryCommandWords = []string{"scan", "enum", "discover", "introspect", "fuzz", "crawl", "probe", "audit"}
+
+// proactiveState tracks what has already been hinted
+type proactiveState struct {
+	quiet    bool
+	lastHint time.Time
+	hinted   map[string]bool // findings already suggested
+}
+
+// SetQuiet silences or re-enables proactive hints for this session
+func (i *IntelSystem) SetQuiet(quiet bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.proactive.quiet = quiet
+}
+
+// IsQuiet reports whether proactive hints are silenced
+func (i *IntelSystem) IsQuiet() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.proactive.quiet
+}
+
+// proactiveHint decides whether a just-recorded command deserves an
+// unsolicited suggestion and returns it, or "" for none. New high or critical
+// findings take priority over discovery-style commands. Hints are spaced by
+// the proactive interval and each finding is only mentioned once.
+func (i *IntelSystem) proactiveHint(command string, success bool) string {
+	if !i.config.Proactive || command == "intel" {
+		return ""
+	}
+
+	interval := i.config.ProactiveInterval
+	if interval <= 0 {
+		interval = DefaultProactiveInterval
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.proactive.quiet || time.Since(i.proactive.lastHint) < interval {
+		return ""
+	}
+	if i.proactive.hinted == nil {
+		i.proactive.hinted = make(map[string]bool)
+	}
+
+	hint := ""
+	var top *Finding
+	topKey := ""
+	for _, provider := range i.providers {
+		data, err := provider.GetContext()
+		if err != nil || data == nil {
+			continue
+		}
+		for n := range data.Discoveries {
+			finding := &data.Discoveries[n]
+			if severityOrder(finding.Severity) > severityRank["high"] {
+				continue
+			}
+			key := provider.Name() + "|" + finding.Title + "|" + finding.Location
+			if i.proactive.hinted[key] {
+				continue
+			}
+			if top == nil || severityOrder(finding.Severity) < severityOrder(top.Severity) {
+				top, topKey = finding, key
+			}
+		}
+	}
+
+	switch {
+	case top != nil:
+		// Only the finding mentioned is marked; the rest wait for later hints
+		i.proactive.hinted[topKey] = true
+		hint = fmt.Sprintf("intel explain %s", strings.ToLower(top.Title))
+	case success && isDiscoveryCommand(command):
+		hint = "intel analyze"
+	}
+
+	if hint != "" {
+		i.proactive.lastHint = time.Now()
+	}
+	return hint
+}
+
+// showProactiveHint prints a proactive suggestion below the command output
+func (i *IntelSystem) showProactiveHint(hint string) {
+	fmt.Fprintf(i.Output(), "%s▸ Consider: %s%s%s  %s(intel quiet to silence)%s\n",
+		output.CyanColor, output.BoldColor, hint, output.Reset, output.DimColor, output.Reset)
+}
+
+// isDiscoveryCommand reports whether a command name suggests it gathers findings
+func isDiscoveryCommand(command string) bool {
+	name := strings.ToLower(command)
+	for _, word := range discoveryCommandWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package intel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// findingsProvider reports a fixed set of discoveries
+type findingsProvider struct {
+	*BaseContextProvider
+	findings []Finding
+}
+
+func (p *findingsProvider) GetContext() (*ContextData, error) {
+	data, err := p.BaseContextProvider.GetContext()
+	if err != nil {
+		return nil, err
+	}
+	data.Discoveries = p.findings
+	return data, nil
+}
+
+func TestProactiveHintsEachFinding(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config := DefaultConfig()
+	config.Proactive = true
+	config.ProactiveInterval = time.Nanosecond
+	system := New("proactive-test", config)
+	var out bytes.Buffer
+	system.SetOutput(&out)
+
+	system.RegisterProvider(&findingsProvider{
+		BaseContextProvider: NewBaseContextProvider("web", "testing", ""),
+		findings: []Finding{
+			{Severity: "high", Title: "SQL Injection", Location: "/login"},
+			{Severity: "low", Title: "Server Banner", Location: "/"},
+			{Severity: "high", Title: "Open Redirect", Location: "/next"},
+			{Severity: "critical", Title: "Exposed Admin", Location: "/admin"},
+		},
+	})
+
+	var hints []string
+	for n := 0; n < 4; n++ {
+		out.Reset()
+		time.Sleep(time.Millisecond)
+		system.AddAction("show", nil, "", true)
+		hints = append(hints, strings.TrimSpace(out.String()))
+	}
+
+	for n, want := range []string{"exposed admin", "sql injection", "open redirect"} {
+		if !strings.Contains(hints[n], "intel explain "+want) {
+			t.Errorf("hint %d = %q, want it to explain %q", n+1, hints[n], want)
+		}
+	}
+	if hints[3] != "" {
+		t.Errorf("hint 4 = %q, want none once every finding was mentioned", hints[3])
+	}
+}
\ No newline at end of file
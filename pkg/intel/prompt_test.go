@@ -0,0 +1,68 @@
+package intel
+
+import "testing"
+
+func TestEffectivePromptPrecedence(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config := DefaultConfig()
+	config.CustomPrompts[string(PromptAnalyze)] = "config analyze template"
+	system := New("prompt-test", config)
+
+	first := NewBaseContextProvider("first", "testing", "")
+	second := NewBaseContextProvider("second", "testing", "")
+	first.SetPromptTemplate(PromptAnalyze, "first analyze template")
+	second.SetPromptTemplate(PromptAnalyze, "second analyze template")
+	system.RegisterProvider(first)
+	system.RegisterProvider(second)
+
+	steps := []struct {
+		name       string
+		remove     func()
+		wantPrompt string
+		wantSource string
+	}{
+		{
+			name:       "first provider",
+			remove:     func() {},
+			wantPrompt: "first analyze template",
+			wantSource: "provider first",
+		},
+		{
+			name:       "second provider",
+			remove:     func() { first.SetPromptTemplate(PromptAnalyze, "") },
+			wantPrompt: "second analyze template",
+			wantSource: "provider second",
+		},
+		{
+			name:       "config",
+			remove:     func() { second.SetPromptTemplate(PromptAnalyze, "  ") },
+			wantPrompt: "config analyze template",
+			wantSource: "config",
+		},
+		{
+			name:       "default",
+			remove:     func() { delete(config.CustomPrompts, string(PromptAnalyze)) },
+			wantPrompt: defaultPromptTemplates[PromptAnalyze],
+			wantSource: "default",
+		},
+	}
+
+	for _, step := range steps {
+		step.remove()
+		if got := system.EffectivePrompt(PromptAnalyze); got != step.wantPrompt {
+			t.Errorf("%s: EffectivePrompt = %q, want %q", step.name, got, step.wantPrompt)
+		}
+		if got := system.PromptSource(PromptAnalyze); got != step.wantSource {
+			t.Errorf("%s: PromptSource = %q, want %q", step.name, got, step.wantSource)
+		}
+	}
+
+	// A type without any template at all resolves to nothing
+	if got := system.EffectivePrompt(PromptHelp); got != "" {
+		t.Errorf("EffectivePrompt(help) = %q, want empty", got)
+	}
+	if got := system.PromptSource(PromptHelp); got != "" {
+		t.Errorf("PromptSource(help) = %q, want empty", got)
+	}
+}
\ No newline at end of file
@@ -11,6 +11,14 @@ type ContextProvider interface {
 	GetPromptTemplates() map[string]string
 }
 
+// CompletionProvider is implemented by context providers that know values
+// worth tab-completing, such as discovered endpoints or operations. Options
+// are keyed by command name and offered for that command's first argument
+// once it is bridged with CompleteFromProviders.
+type CompletionProvider interface {
+	CompletionOptions() map[string][]string
+}
+
 // ContextData represents the current context for AI analysis
 type ContextData struct {
 	Domain      string                 `json:"domain"`      // "firebase", "graphql", "kubernetes", etc.
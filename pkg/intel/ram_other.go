@@ -0,0 +1,70 @@
+//go:build !windows
+
+package intel
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// getSystemRAMWindows is only available on Windows
+func getSystemRAMWindows() int {
+	return 0
+}
+
+// getSystemRAMUnix gets system RAM on Unix-like systems
+func getSystemRAMUnix() int {
+	switch runtime.GOOS {
+	case "linux":
+		return getSystemRAMLinux()
+	case "darwin":
+		return getSystemRAMDarwin()
+	default:
+		return 0
+	}
+}
+
+// getSystemRAMLinux reads MemTotal from /proc/meminfo
+func getSystemRAMLinux() int {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+
+		// MemTotal is reported in kB
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return int(kb / (1024 * 1024))
+	}
+
+	return 0
+}
+
+// getSystemRAMDarwin queries hw.memsize via sysctl on macOS
+func getSystemRAMDarwin() int {
+	out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return 0
+	}
+
+	bytes, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return int(bytes / (1024 * 1024 * 1024))
+}
\ No newline at end of file
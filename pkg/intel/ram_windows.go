@@ -0,0 +1,46 @@
+//go:build windows
+
+package intel
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// memoryStatusEx mirrors the Win32 MEMORYSTATUSEX structure
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+var procGlobalMemoryStatusEx = windows.NewLazySystemDLL("kernel32.dll").NewProc("GlobalMemoryStatusEx")
+
+// getSystemRAMWindows gets system RAM on Windows using GlobalMemoryStatusEx
+func getSystemRAMWindows() int {
+	if err := procGlobalMemoryStatusEx.Find(); err != nil {
+		return 0
+	}
+
+	status := memoryStatusEx{}
+	status.Length = uint32(unsafe.Sizeof(status))
+
+	ret, _, _ := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return 0
+	}
+
+	return int(status.TotalPhys / (1024 * 1024 * 1024))
+}
+
+// getSystemRAMUnix is not available on Windows
+func getSystemRAMUnix() int {
+	return 0
+}
\ No newline at end of file
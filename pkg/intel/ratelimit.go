@@ -0,0 +1,110 @@
+package intel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket allowing bursts of up to capacity queries and
+// refilling at capacity per minute
+type rateLimiter struct {
+	capacity float64
+	tokens   float64
+	perSec   float64
+	last     time.Time
+	mu       sync.Mutex
+}
+
+// newRateLimiter creates a limiter allowing perMinute queries per minute
+func newRateLimiter(perMinute int) *rateLimiter {
+	return &rateLimiter{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		perSec:   float64(perMinute) / 60,
+		last:     time.Now(),
+	}
+}
+
+// refill adds tokens for the time elapsed since the last call; the caller
+// must hold the lock
+func (r *rateLimiter) refill() {
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.perSec
+	if r.tokens > r.capacity {
+		r.tokens = r.capacity
+	}
+	r.last = now
+}
+
+// Allow takes a token if one is available. Otherwise it reports how long
+// until the next token.
+func (r *rateLimiter) Allow() (bool, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refill()
+	if r.tokens >= 1 {
+		r.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - r.tokens) / r.perSec * float64(time.Second))
+	return false, wait
+}
+
+// Wait blocks until a token is available or ctx is done
+func (r *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		ok, wait := r.Allow()
+		if ok {
+			return nil
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Remaining returns the number of whole queries currently available
+func (r *rateLimiter) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refill()
+	return int(r.tokens)
+}
+
+// acquireQuery applies the configured rate limit before a model query. It
+// waits when RateLimitWait is set, otherwise it rejects the query at once.
+func (i *IntelSystem) acquireQuery(ctx context.Context) error {
+	if i.limiter == nil {
+		return nil
+	}
+
+	if i.config.RateLimitWait {
+		if err := i.limiter.Wait(ctx); err != nil {
+			return NewRateLimitError("wait_timeout", "Timed out waiting for the query rate limit", err).
+				WithContext("limit_per_minute", i.config.MaxQueriesPerMinute)
+		}
+		return nil
+	}
+
+	if ok, wait := i.limiter.Allow(); !ok {
+		return NewRateLimitError("exceeded",
+			fmt.Sprintf("Query rate limit of %d per minute exceeded", i.config.MaxQueriesPerMinute), nil).
+			WithContext("retry_after", wait.Round(time.Second).String())
+	}
+	return nil
+}
+
+// RateLimitStatus returns the remaining query budget and the per-minute limit.
+// The limit is 0 when rate limiting is disabled.
+func (i *IntelSystem) RateLimitStatus() (remaining, limit int) {
+	if i.limiter == nil {
+		return 0, 0
+	}
+	return i.limiter.Remaining(), i.config.MaxQueriesPerMinute
+}
\ No newline at end of file
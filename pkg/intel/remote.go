@@ -0,0 +1,105 @@
+package intel
+
+import (
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+	"github.com/ollama/ollama/envconfig"
+)
+
+// defaultOllamaURL is used when neither the config nor OLLAMA_HOST names a server
+const defaultOllamaURL = "http://localhost:11434"
+
+// ollamaURLFromEnv returns the server named by OLLAMA_HOST, parsed the way
+// the ollama CLI does ("host", "host:port" or "scheme://host:port"), or the
+// local default when it is unset
+func ollamaURLFromEnv() string {
+	if strings.TrimSpace(os.Getenv("OLLAMA_HOST")) == "" {
+		return defaultOllamaURL
+	}
+	return strings.TrimRight(envconfig.Host().String(), "/")
+}
+
+// isLocalURL reports whether rawURL points at this machine, where Intel can
+// install and start Ollama itself
+func isLocalURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := parsed.Hostname()
+	if host == "localhost" || host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// moveURLCredentials strips user:password from config.OllamaURL and sends
+// them as an Authorization header instead: basic auth for "user:pass@", a
+// bearer token for ":token@". An Authorization header set explicitly in
+// config.Headers wins.
+func moveURLCredentials(config *Config) {
+	parsed, err := url.Parse(config.OllamaURL)
+	if err != nil || parsed.User == nil {
+		return
+	}
+
+	username := parsed.User.Username()
+	password, _ := parsed.User.Password()
+	parsed.User = nil
+	config.OllamaURL = strings.TrimRight(parsed.String(), "/")
+
+	if config.Headers == nil {
+		config.Headers = make(map[string]string)
+	}
+	for key := range config.Headers {
+		if strings.EqualFold(key, "Authorization") {
+			return
+		}
+	}
+	if username == "" {
+		config.Headers["Authorization"] = "Bearer " + password
+	} else {
+		config.Headers["Authorization"] = "Basic " +
+			base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+}
+
+// headerTransport adds fixed headers to every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip sends req with the configured headers added
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newOllamaClient creates a client for config.OllamaURL that sends
+// config.Headers with every request
+func newOllamaClient(config *Config) (*api.Client, error) {
+	base, err := url.Parse(config.OllamaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := http.DefaultClient
+	if len(config.Headers) > 0 {
+		httpClient = &http.Client{Transport: headerTransport{
+			base:    http.DefaultTransport,
+			headers: config.Headers,
+		}}
+	}
+	return api.NewClient(base, httpClient), nil
+}
\ No newline at end of file
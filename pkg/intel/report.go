@@ -0,0 +1,184 @@
+package intel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+)
+
+// targetStateKeys are provider state keys checked, in order, for the report target
+var targetStateKeys = []string{"target", "target_url", "url", "host"}
+
+// recordAnalysis remembers the most recent analysis for reports
+func (i *IntelSystem) recordAnalysis(query string, response *Response) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lastQuery = query
+	i.lastAnalysis = response
+}
+
+// LastAnalysis returns the most recent analysis and the query that produced it,
+// or nil if nothing has been analyzed yet
+func (i *IntelSystem) LastAnalysis() (*Response, string) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.lastAnalysis, i.lastQuery
+}
+
+// BuildReport renders the last analysis, session metadata and every provider's
+// findings as a markdown document. If nothing has been analyzed yet, a fresh
+// analysis is run (or a heuristic one when Intel is offline).
+func (i *IntelSystem) BuildReport() (string, error) {
+	analysis, query := i.LastAnalysis()
+	if analysis == nil {
+		query = "Analyze the current session"
+		switch {
+		case i.IsInitialized():
+			response, err := i.Analyze(query)
+			if err != nil {
+				return "", err
+			}
+			analysis = response
+		case i.IsOffline():
+			analysis = i.HeuristicAnalyze()
+		default:
+			return "", NewIntelError(ErrorTypeContext, "report_no_analysis",
+				"No analysis available for the report", nil).
+				WithSuggestions(
+					"Run 'intel start' to initialize Intel",
+					"Run 'intel analyze' before generating a report",
+				)
+		}
+	}
+
+	i.mu.RLock()
+	providers := append([]ContextProvider(nil), i.providers...)
+	appName := i.appName
+	i.mu.RUnlock()
+
+	now := time.Now()
+	model, _ := analysis.Metadata["model"].(string)
+	if model == "" {
+		model = i.config.Model
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s Intel Report\n\n", appName)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Generated | %s |\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Model | %s |\n", escapeTableCell(model))
+	if target := reportTarget(providers); target != "" {
+		fmt.Fprintf(&b, "| Target | %s |\n", escapeTableCell(target))
+	}
+	i.context.mu.RLock()
+	start := i.context.StartTime
+	actions := append([]Action(nil), i.context.RecentActions...)
+	i.context.mu.RUnlock()
+	fmt.Fprintf(&b, "| Session started | %s |\n", start.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Session duration | %s |\n", now.Sub(start).Round(time.Second))
+	if len(providers) > 0 {
+		names := make([]string, 0, len(providers))
+		for _, provider := range providers {
+			names = append(names, provider.Name())
+		}
+		fmt.Fprintf(&b, "| Providers | %s |\n", escapeTableCell(strings.Join(names, ", ")))
+	}
+	if offline, _ := analysis.Metadata["offline"].(bool); offline {
+		fmt.Fprintf(&b, "\n> %s\n", OfflineBanner)
+	}
+
+	b.WriteString("\n## Analysis\n\n")
+	if query != "" {
+		fmt.Fprintf(&b, "_Query: %s_\n\n", query)
+	}
+	b.WriteString(demoteHeadings(strings.TrimSpace(analysis.Content), 2))
+	b.WriteString("\n")
+
+	b.WriteString("\n## Findings\n")
+	total := 0
+	for _, provider := range providers {
+		data, err := provider.GetContext()
+		if err != nil || data == nil || len(data.Discoveries) == 0 {
+			continue
+		}
+		findings := append([]Finding(nil), data.Discoveries...)
+		sortBySeverity(findings)
+		total += len(findings)
+
+		fmt.Fprintf(&b, "\n### %s\n\n", provider.Name())
+		writeFindingsTable(&b, findings)
+	}
+	if total == 0 {
+		b.WriteString("\nNo findings recorded.\n")
+	}
+
+	if len(actions) > 0 {
+		b.WriteString("\n## Recent Commands\n\n")
+		for _, action := range actions {
+			status := "✓"
+			if !action.Success {
+				status = "✗"
+			}
+			fmt.Fprintf(&b, "- %s `%s` (%s)\n", status,
+				strings.TrimSpace(action.Command+" "+strings.Join(action.Args, " ")),
+				action.Timestamp.Format("15:04:05"))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// WriteReport builds a report and writes it to path
+func (i *IntelSystem) WriteReport(path string) error {
+	report, err := i.BuildReport()
+	if err != nil {
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(path, []byte(report), 0644); err != nil {
+		return NewIntelError(ErrorTypeContext, "report_write_failed",
+			fmt.Sprintf("Failed to write report file: %s", path), err).
+			WithSuggestions(
+				"Check that the directory exists and is writable",
+				"Try a different path",
+			)
+	}
+	return nil
+}
+
+// reportTarget returns the first target-like value found in provider state
+func reportTarget(providers []ContextProvider) string {
+	for _, key := range targetStateKeys {
+		for _, provider := range providers {
+			if value, ok := provider.GetCurrentState()[key]; ok && value != nil && fmt.Sprint(value) != "" {
+				return fmt.Sprint(value)
+			}
+		}
+	}
+	return ""
+}
+
+// demoteHeadings pushes markdown headings down by levels so model output
+// nests under the report's own sections
+func demoteHeadings(text string, levels int) string {
+	lines := strings.Split(text, "\n")
+	inCode := false
+	for n, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "#") {
+			lines[n] = strings.Repeat("#", levels) + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeTableCell makes text safe to place inside a markdown table cell
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	text = strings.ReplaceAll(text, "\r\n", " ")
+	return strings.ReplaceAll(text, "\n", " ")
+}
\ No newline at end of file
@@ -0,0 +1,125 @@
+package intel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
+)
+
+// SessionFormatVersion is the current on-disk session format version.
+// Bump it whenever the layout of sessionFile changes incompatibly.
+const SessionFormatVersion = 1
+
+// sessionFile is the on-disk representation of a saved Intel session
+type sessionFile struct {
+	Version     int                    `json:"version"`
+	AppName     string                 `json:"app_name"`
+	Model       string                 `json:"model"`
+	SavedAt     time.Time              `json:"saved_at"`
+	StartTime   time.Time              `json:"start_time"`
+	MaxTokens   int                    `json:"max_tokens"`
+	Items       []ContextItem          `json:"items"`
+	Actions     []Action               `json:"actions"`
+	SessionData map[string]interface{} `json:"session_data,omitempty"`
+}
+
+// SaveSession writes the accumulated context, recent actions and session data to a JSON file
+func (i *IntelSystem) SaveSession(path string) error {
+	i.contextMu.Lock()
+	i.mu.RLock()
+	session := sessionFile{
+		Version:   SessionFormatVersion,
+		AppName:   i.appName,
+		Model:     i.config.Model,
+		SavedAt:   time.Now(),
+		MaxTokens: i.contextManager.maxTokens,
+		Items:     i.contextManager.GetItems(),
+	}
+	i.mu.RUnlock()
+	i.contextMu.Unlock()
+
+	i.context.mu.RLock()
+	session.StartTime = i.context.StartTime
+	session.Actions = make([]Action, len(i.context.RecentActions))
+	copy(session.Actions, i.context.RecentActions)
+	session.SessionData = make(map[string]interface{}, len(i.context.SessionData))
+	for key, value := range i.context.SessionData {
+		session.SessionData[key] = value
+	}
+	i.context.mu.RUnlock()
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return NewIntelError(ErrorTypeContext, "session_encode_failed", "Failed to encode session", err)
+	}
+
+	if err := utils.WriteFileAtomic(path, data, 0600); err != nil {
+		return NewIntelError(ErrorTypeContext, "session_write_failed",
+			fmt.Sprintf("Failed to write session file: %s", path), err).
+			WithSuggestions(
+				"Check that the directory exists and is writable",
+				"Try a different path",
+			)
+	}
+
+	return nil
+}
+
+// LoadSession restores context, recent actions and session data from a JSON file.
+// Token counts are recomputed and the token budget re-optimized after loading.
+func (i *IntelSystem) LoadSession(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return NewIntelError(ErrorTypeContext, "session_read_failed",
+			fmt.Sprintf("Failed to read session file: %s", path), err).
+			WithSuggestions(
+				"Check that the file exists",
+				"Save a session first with 'intel save <file>'",
+			)
+	}
+
+	var session sessionFile
+	if err := json.Unmarshal(data, &session); err != nil {
+		return NewIntelError(ErrorTypeContext, "session_decode_failed", "Session file is not valid", err)
+	}
+
+	if session.Version < 1 || session.Version > SessionFormatVersion {
+		return NewIntelError(ErrorTypeContext, "session_version_unsupported",
+			fmt.Sprintf("Unsupported session format version: %d", session.Version), nil).
+			WithSuggestions(
+				fmt.Sprintf("This build supports session format versions 1-%d", SessionFormatVersion),
+				"Upgrade the tool to load sessions saved by newer versions",
+			)
+	}
+
+	i.contextMu.Lock()
+	i.mu.Lock()
+	if session.MaxTokens > 0 {
+		i.contextManager.maxTokens = session.MaxTokens
+	}
+	i.contextManager.LoadItems(session.Items)
+	i.mu.Unlock()
+	i.contextMu.Unlock()
+
+	i.context.mu.Lock()
+	if session.Actions == nil {
+		session.Actions = make([]Action, 0)
+	}
+	i.context.RecentActions = session.Actions
+	if len(i.context.RecentActions) > i.config.ContextDepth {
+		i.context.RecentActions = i.context.RecentActions[len(i.context.RecentActions)-i.config.ContextDepth:]
+	}
+	if session.SessionData == nil {
+		session.SessionData = make(map[string]interface{})
+	}
+	i.context.SessionData = session.SessionData
+	if !session.StartTime.IsZero() {
+		i.context.StartTime = session.StartTime
+	}
+	i.context.mu.Unlock()
+
+	return nil
+}
\ No newline at end of file
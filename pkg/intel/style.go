@@ -34,20 +34,20 @@ func (s *StyleConstants) CreateHeader(title string, style string) string {
 	case "main":
 		// Main header with double lines
 		border := strings.Repeat("═", length+4)
-		return output.CyanColor + "╔═" + border + "═╗\n" +
-			"║ " + output.BoldColor + title + output.Reset + output.CyanColor + " ║\n" +
+		return output.CurrentTheme().Primary + "╔═" + border + "═╗\n" +
+			"║ " + output.BoldColor + title + output.Reset + output.CurrentTheme().Primary + " ║\n" +
 			"╚═" + border + "═╝" + output.Reset
 	case "section":
 		// Section header with single lines
 		border := strings.Repeat("─", length+4)
-		return output.CyanColor + "╭─" + border + "─╮\n" +
-			"│ " + output.BoldColor + title + output.Reset + output.CyanColor + " │\n" +
+		return output.CurrentTheme().Primary + "╭─" + border + "─╮\n" +
+			"│ " + output.BoldColor + title + output.Reset + output.CurrentTheme().Primary + " │\n" +
 			"╰─" + border + "─╯" + output.Reset
 	case "simple":
 		// Simple underlined header
 		underline := strings.Repeat("─", length)
 		return output.BoldColor + title + output.Reset + "\n" +
-			output.CyanColor + underline + output.Reset
+			output.CurrentTheme().Primary + underline + output.Reset
 	default:
 		return output.BoldColor + title + output.Reset
 	}
@@ -56,18 +56,18 @@ func (s *StyleConstants) CreateHeader(title string, style string) string {
 // Create consistent separators
 func (s *StyleConstants) CreateSeparator(width int, style string) string {
 	if width <= 0 {
-		width = 50
+		width = output.TerminalWidth()
 	}
 	
 	switch style {
 	case "double":
-		return output.CyanColor + strings.Repeat("═", width) + output.Reset
+		return output.CurrentTheme().Primary + strings.Repeat("═", width) + output.Reset
 	case "single":
-		return output.CyanColor + strings.Repeat("─", width) + output.Reset
+		return output.CurrentTheme().Primary + strings.Repeat("─", width) + output.Reset
 	case "dotted":
-		return output.CyanColor + strings.Repeat("·", width) + output.Reset
+		return output.CurrentTheme().Primary + strings.Repeat("·", width) + output.Reset
 	default:
-		return output.CyanColor + strings.Repeat("─", width) + output.Reset
+		return output.CurrentTheme().Primary + strings.Repeat("─", width) + output.Reset
 	}
 }
 
@@ -79,22 +79,22 @@ func (s *StyleConstants) FormatStatus(message string, status string) string {
 	switch status {
 	case "success":
 		indicator = StatusSuccess
-		color = output.GreenColor
+		color = output.CurrentTheme().Success
 	case "error":
 		indicator = StatusError
-		color = output.RedColor
+		color = output.CurrentTheme().Error
 	case "warning":
 		indicator = StatusWarning
-		color = output.YellowColor
+		color = output.CurrentTheme().Warning
 	case "info":
 		indicator = StatusInfo
-		color = output.CyanColor
+		color = output.CurrentTheme().Primary
 	case "progress":
 		indicator = StatusProgress
-		color = output.CyanColor
+		color = output.CurrentTheme().Primary
 	default:
 		indicator = StatusInfo
-		color = output.CyanColor
+		color = output.CurrentTheme().Primary
 	}
 	
 	return color + indicator + " " + message + output.Reset
@@ -102,12 +102,12 @@ func (s *StyleConstants) FormatStatus(message string, status string) string {
 
 // Format bullet points consistently
 func (s *StyleConstants) FormatBullet(text string) string {
-	return output.YellowColor + "▸" + output.Reset + " " + text
+	return output.CurrentTheme().Accent + "▸" + output.Reset + " " + text
 }
 
 // Format numbered items consistently
 func (s *StyleConstants) FormatNumbered(number int, text string) string {
-	return output.GreenColor + string(rune('0'+number)) + "." + output.Reset + " " + text
+	return output.CurrentTheme().Success + string(rune('0'+number)) + "." + output.Reset + " " + text
 }
 
 // Format code blocks consistently
@@ -115,7 +115,7 @@ func (s *StyleConstants) FormatCodeBlock(code string) string {
 	lines := strings.Split(code, "\n")
 	var result strings.Builder
 	
-	result.WriteString(output.CyanColor + "╭─ Code Block " + strings.Repeat("─", 50) + "╮\n")
+	result.WriteString(output.CurrentTheme().Primary + "╭─ Code Block " + strings.Repeat("─", 50) + "╮\n")
 	for _, line := range lines {
 		result.WriteString("│ " + line + "\n")
 	}
@@ -126,7 +126,7 @@ func (s *StyleConstants) FormatCodeBlock(code string) string {
 
 // Format inline code consistently
 func (s *StyleConstants) FormatInlineCode(code string) string {
-	return output.YellowColor + "`" + code + "`" + output.Reset
+	return output.CurrentTheme().Accent + "`" + code + "`" + output.Reset
 }
 
 // Format emphasis consistently
@@ -135,9 +135,9 @@ func (s *StyleConstants) FormatEmphasis(text string, level string) string {
 	case "strong":
 		return output.BoldColor + text + output.Reset
 	case "emphasis":
-		return output.CyanColor + text + output.Reset
+		return output.CurrentTheme().Primary + text + output.Reset
 	case "highlight":
-		return output.YellowColor + text + output.Reset
+		return output.CurrentTheme().Accent + text + output.Reset
 	default:
 		return text
 	}
@@ -158,7 +158,7 @@ CLI STYLING GUIDELINES FOR INTEL SYSTEM:
    - Section headers: single lines (─)
    - Simple headers: underlined text
 
-3. COLORS:
+3. COLORS (output.DefaultTheme, changeable with output.SetTheme):
    - Success: Green
    - Error: Red
    - Warning: Yellow
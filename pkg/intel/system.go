@@ -3,38 +3,126 @@ package intel
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ollama/ollama/api"
+	"github.com/jacobdavidalcock/consolekit/pkg/config"
 	"github.com/jacobdavidalcock/consolekit/pkg/output"
+	"github.com/jacobdavidalcock/consolekit/pkg/utils"
 )
 
 // IntelSystem is the core AI assistant system for ConsoleKit
 type IntelSystem struct {
 	appName        string
-	client         *api.Client
+	client         *api.Client // Ollama client; nil for hosted backends
+	backend        Backend
 	model          string
 	context        *Context
 	contextManager *ContextManager
 	providers      []ContextProvider
+	weights        map[string]float64
 	config         *Config
 	ollamaManager  *OllamaManager
+	cache          *responseCache
+	limiter        *rateLimiter
+	audit          *auditLogger
 	initialized    bool
+	offline        bool // initialization failed; analyze/suggest fall back to heuristics
+	lastAnalysis   *Response
+	lastQuery      string
+	proactive      proactiveState
+	out            io.Writer // where responses and spinners are drawn; nil is os.Stdout
+	postProcessors *PostProcessorChain
+	tokens         usageTracker // query and token totals, see Usage
+	state          *config.State // application state deciding which keys are masked, see SetState
+	contextMu      sync.Mutex    // guards contextManager; taken before mu when both are held
 	mu             sync.RWMutex
 }
 
 // Config holds configuration for the Intel system
 type Config struct {
-	Model         string            `yaml:"model"`
-	AutoDownload  bool              `yaml:"auto_download"`
-	Proactive     bool              `yaml:"proactive"`
-	ContextDepth  int               `yaml:"context_depth"`
-	SystemPrompt  string            `yaml:"system_prompt"`
-	CustomPrompts map[string]string `yaml:"custom_prompts"`
-	OllamaURL     string            `yaml:"ollama_url"`
-	Timeout       time.Duration     `yaml:"timeout"`
+	Model           string                   `yaml:"model"`
+	AutoDownload    bool                     `yaml:"auto_download"`
+	Proactive       bool                     `yaml:"proactive"`
+	ContextDepth    int                      `yaml:"context_depth"`
+	SystemPrompt    string                   `yaml:"system_prompt"`
+	CustomPrompts   map[string]string        `yaml:"custom_prompts"`
+	OllamaURL       string                   `yaml:"ollama_url"`       // defaults to $OLLAMA_HOST; user:pass@ moves into Headers
+	Headers         map[string]string        `yaml:"headers"`          // sent with every Ollama request, e.g. Authorization for a proxy
+	Timeout         time.Duration            `yaml:"timeout"`
+	Timeouts        map[string]time.Duration `yaml:"timeouts"`         // per prompt type overrides, e.g. analyze: 90s
+	Options         ModelOptions             `yaml:"options"`
+	CacheSize       int                      `yaml:"cache_size"`       // number of responses to cache (0 disables caching)
+	SemanticContext bool                     `yaml:"semantic_context"` // rank context by embedding similarity to the query (extra model calls)
+
+	MaxQueriesPerMinute int  `yaml:"max_queries_per_minute"` // 0 disables rate limiting
+	RateLimitWait       bool `yaml:"rate_limit_wait"`        // wait for budget instead of rejecting queries
+
+	AuditLog        string `yaml:"audit_log"`          // append a JSON line per query to this file
+	AuditLogMaxSize int64  `yaml:"audit_log_max_size"` // bytes before rotating to <audit_log>.1 (0 = 10MB)
+
+	ProactiveInterval time.Duration `yaml:"proactive_interval"` // minimum gap between proactive hints (0 = 2m)
+
+	Backend string `yaml:"backend"`  // "ollama" (default) or "anthropic"
+	APIKey  string `yaml:"api_key"`  // hosted backend key; falls back to e.g. ANTHROPIC_API_KEY
+	BaseURL string `yaml:"base_url"` // hosted backend URL (empty = provider default)
+
+	KeepAlive   *time.Duration `yaml:"keep_alive,omitempty"` // how long Ollama keeps the model loaded after a query: 0 unloads at once, negative keeps it resident, nil uses Ollama's default (5m)
+	Prewarm     bool          `yaml:"prewarm"`      // load the model in the background after 'intel start' so the first query is fast
+	TypingDelay time.Duration `yaml:"typing_delay"` // per-character typing animation (0 = off); forced off when not on a terminal or in JSON mode
+
+	MaxRetries   int  `yaml:"max_retries"`   // retries after a failed query (0 = fail at once); backoff never outlasts the timeout
+	QuietRetries bool `yaml:"quiet_retries"` // don't print a notice before each retry
+
+	BatchConcurrency int `yaml:"batch_concurrency"` // targets AnalyzeBatch runs at once with a custom BatchFunc (0 = 1)
+
+	KeepFiller bool `yaml:"keep_filler"` // don't strip filler phrases and words from displayed responses
+
+	CostPer1KTokens float64 `yaml:"cost_per_1k_tokens"` // price used by 'intel usage' to estimate cost (0 = not shown)
+}
+
+// ModelOptions holds generation parameters passed to the model on every request.
+// Unset fields leave the model's own defaults in place.
+type ModelOptions struct {
+	Temperature *float64 `yaml:"temperature,omitempty"` // 0-2, lower is more deterministic
+	TopP        *float64 `yaml:"top_p,omitempty"`       // 0-1, nucleus sampling cutoff
+	NumPredict  int      `yaml:"num_predict,omitempty"` // maximum tokens to generate (0 = model default)
+	Seed        *int     `yaml:"seed,omitempty"`        // fixed seed for reproducible output
+}
+
+// TimeoutFor returns the timeout for a prompt type, falling back to Timeout
+// when the type has no override
+func (c *Config) TimeoutFor(promptType PromptType) time.Duration {
+	if timeout, ok := c.Timeouts[string(promptType)]; ok && timeout > 0 {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// toAPIOptions converts the options into the map expected by the Ollama API
+func (o ModelOptions) toAPIOptions() map[string]interface{} {
+	options := make(map[string]interface{})
+	if o.Temperature != nil {
+		options["temperature"] = *o.Temperature
+	}
+	if o.TopP != nil {
+		options["top_p"] = *o.TopP
+	}
+	if o.NumPredict != 0 {
+		options["num_predict"] = o.NumPredict
+	}
+	if o.Seed != nil {
+		options["seed"] = *o.Seed
+	}
+	
+	if len(options) == 0 {
+		return nil
+	}
+	return options
 }
 
 // Context holds the current session context for AI analysis
@@ -74,6 +162,18 @@ type Explanation struct {
 	Timestamp   time.Time              `json:"timestamp"`
 }
 
+// defaultPromptTemplates are the built-in task instructions, used when
+// neither a provider template nor Config.CustomPrompts covers a prompt type
+var defaultPromptTemplates = map[PromptType]string{
+	PromptAnalyze: "Analyze the session. Give 3-5 key findings and immediate next steps. Use bullets. Be concise.",
+	PromptSuggest: "Suggest 3-5 specific commands to run next. Focus on actionable steps. Use bullets and code examples.",
+	PromptExplain: "Explain this concept concisely. Include key risks and 2-3 practical examples. Keep it brief.",
+	PromptDebug:   "Troubleshoot this problem. Name the most likely cause from the errors and failed commands, then give numbered steps to confirm and fix it, with exact commands.",
+}
+
+// DefaultMaxRetries is how many times a failed query is retried by default
+const DefaultMaxRetries = 3
+
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -82,13 +182,11 @@ func DefaultConfig() *Config {
 		Proactive:    false,
 		ContextDepth: 10,
 		SystemPrompt: "You are a concise CLI assistant. Respond like a skilled colleague - brief, direct, actionable. No fluff.",
-		CustomPrompts: map[string]string{
-			"analyze": "Analyze the session. Give 3-5 key findings and immediate next steps. Use bullets. Be concise.",
-			"suggest": "Suggest 3-5 specific commands to run next. Focus on actionable steps. Use bullets and code examples.",
-			"explain": "Explain this concept concisely. Include key risks and 2-3 practical examples. Keep it brief.",
-		},
-		OllamaURL: "http://localhost:11434",
+		CustomPrompts: map[string]string{},
+		OllamaURL: ollamaURLFromEnv(),
 		Timeout:   30 * time.Second,
+		TypingDelay: DefaultTypingDelay,
+		MaxRetries:  DefaultMaxRetries,
 	}
 }
 
@@ -98,16 +196,17 @@ func New(appName string, config *Config) *IntelSystem {
 		config = DefaultConfig()
 	}
 
+	// Pick up user-supplied model metadata before validating the model
+	modelsErr := loadUserModels(appName)
+
 	// Validate and normalize configuration
 	validator := NewConfigValidator()
-	if err := validator.ValidateAndNormalize(config); err != nil {
-		// Log validation error but don't fail - use defaults
-		fmt.Printf("%s⚠️  Config validation warning: %s%s\n", 
-			output.YellowColor, err.Error(), output.Reset)
+	configErr := validator.ValidateAndNormalize(config)
+	if configErr != nil {
 		config = DefaultConfig()
 	}
 
-	return &IntelSystem{
+	system := &IntelSystem{
 		appName:        appName,
 		config:         config,
 		ollamaManager:  NewOllamaManager(),
@@ -118,7 +217,39 @@ func New(appName string, config *Config) *IntelSystem {
 			StartTime:     time.Now(),
 		},
 		providers: make([]ContextProvider, 0),
+		weights:   make(map[string]float64),
+		postProcessors: DefaultPostProcessors(),
+	}
+
+	if modelsErr != nil {
+		fmt.Fprintf(system.Output(), "%s⚠️  %s%s\n", output.YellowColor, modelsErr.Error(), output.Reset)
+	}
+	if configErr != nil {
+		// Log validation error but don't fail - use defaults
+		fmt.Fprintf(system.Output(), "%s⚠️  Config validation warning: %s%s\n",
+			output.YellowColor, configErr.Error(), output.Reset)
+	}
+
+	if config.KeepFiller {
+		system.postProcessors.Remove(PostProcessFiller)
+	}
+
+	system.contextManager.SetTokenizer(NewTokenizer(config.Model))
+
+	if config.CacheSize > 0 {
+		system.cache = newResponseCache(config.CacheSize)
 	}
+
+	if config.MaxQueriesPerMinute > 0 {
+		system.limiter = newRateLimiter(config.MaxQueriesPerMinute)
+	}
+
+	if config.AuditLog != "" {
+		system.audit = newAuditLogger(config.AuditLog, config.AuditLogMaxSize)
+		system.tokens.previous = loadAuditUsage(config.AuditLog)
+	}
+
+	return system
 }
 
 // Initialize sets up the Intel system and connects to Ollama
@@ -130,28 +261,60 @@ func (i *IntelSystem) Initialize() error {
 		return nil
 	}
 
-	// Ensure Ollama is available (install/start if needed)
-	if err := i.ollamaManager.EnsureOllamaAvailable(); err != nil {
-		intelErr := HandleError(err)
-		intelErr.Display()
-		return intelErr
+	// Any failure below leaves the system in offline mode
+	defer func() {
+		i.offline = !i.initialized
+	}()
+
+	// Hosted backends need no local Ollama install or model download
+	if isHostedBackend(i.config.Backend) {
+		backend, err := newHostedBackend(i.config)
+		if err != nil {
+			return err
+		}
+		if i.config.SemanticContext {
+			fmt.Fprintf(i.Output(), "%s⚠️  Semantic context needs a local Ollama model; using keyword ranking%s\n", output.YellowColor, output.Reset)
+		}
+		i.backend = backend
+		i.initialized = true
+		return nil
+	}
+
+	// Ensure a local Ollama is available (install/start if needed); remote
+	// servers are managed by someone else
+	if isLocalURL(i.config.OllamaURL) {
+		i.ollamaManager.serviceURL = i.config.OllamaURL
+		if err := i.ollamaManager.WithWriter(i.Output()).EnsureOllamaAvailable(); err != nil {
+			intelErr := HandleError(err)
+			intelErr.DisplayTo(i.Output())
+			return intelErr
+		}
 	}
 
 	// Create Ollama client
-	client, err := api.ClientFromEnvironment()
+	client, err := newOllamaClient(i.config)
 	if err != nil {
 		intelErr := NewOllamaError("client_creation_failed", "Failed to create Ollama client", err)
 		return intelErr
 	}
 
 	i.client = client
+	i.backend = client
+
+	if i.config.SemanticContext {
+		i.contextManager.SetEmbedder(&ollamaEmbedder{
+			client:  client,
+			model:   i.config.Model,
+			timeout: i.config.Timeout,
+		})
+	}
 
 	// Validate system requirements for model
 	validator := NewConfigValidator()
 	if err := validator.ValidateSystemRequirements(i.config.Model); err != nil {
 		if intelErr, ok := err.(*IntelError); ok {
 			// Show warning but continue with model download
-			fmt.Printf("%s⚠️  %s%s\n", output.YellowColor, intelErr.Message, output.Reset)
+			fmt.Fprintf(i.Output(), "%s⚠️  %s%s\n", output.YellowColor, intelErr.Message, output.Reset)
 		}
 	}
 
@@ -163,15 +326,55 @@ func (i *IntelSystem) Initialize() error {
 		}
 	}
 
+	// Warming a model that is unloaded after every query is wasted work
+	if i.config.Prewarm && (i.config.KeepAlive == nil || *i.config.KeepAlive != 0) {
+		go i.prewarm(client)
+	}
+
 	i.initialized = true
 	return nil
 }
 
-// RegisterProvider adds a context provider to the system
+// prewarm asks Ollama to load the model into memory by sending a generate
+// request with no prompt. It runs in the background and failures are
+// ignored; the first real query just loads the model itself.
+func (i *IntelSystem) prewarm(client *api.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), i.config.TimeoutFor(PromptAnalyze))
+	defer cancel()
+
+	client.Generate(ctx, &api.GenerateRequest{Model: i.config.Model, KeepAlive: i.keepAlive()}, func(api.GenerateResponse) error {
+		return nil
+	})
+}
+
+// RegisterProvider adds a context provider to the system with the default weight
 func (i *IntelSystem) RegisterProvider(provider ContextProvider) {
+	i.RegisterProviderWithWeight(provider, DefaultProviderWeight)
+}
+
+// RegisterProviderWithWeight adds a context provider whose domain knowledge gets
+// a share of the token budget proportional to weight. Non-positive weights use
+// the default.
+func (i *IntelSystem) RegisterProviderWithWeight(provider ContextProvider, weight float64) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
+	
+	if weight <= 0 {
+		weight = DefaultProviderWeight
+	}
 	i.providers = append(i.providers, provider)
+	i.weights[provider.Name()] = weight
+}
+
+// GetProviderWeight returns the budget weight of a registered provider
+func (i *IntelSystem) GetProviderWeight(name string) float64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	
+	if weight, ok := i.weights[name]; ok {
+		return weight
+	}
+	return DefaultProviderWeight
 }
 
 // IsInitialized returns whether the system is initialized
@@ -181,19 +384,39 @@ func (i *IntelSystem) IsInitialized() bool {
 	return i.initialized
 }
 
+// IsOffline reports whether initialization failed and heuristic output is
+// used in place of the model
+func (i *IntelSystem) IsOffline() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.offline
+}
+
 // Analyze performs AI analysis of the current session
 func (i *IntelSystem) Analyze(userPrompt string) (*Response, error) {
-	if !i.IsInitialized() {
-		return nil, fmt.Errorf("Intel system not initialized")
+	return i.analyze(context.Background(), userPrompt, nil)
+}
+
+// AnalyzeStream performs AI analysis of the current session, passing each
+// token to onToken as it arrives, and returns the full text. onToken may be
+// nil. Nothing is written to Output, so embedding frontends can render the
+// tokens themselves.
+func (i *IntelSystem) AnalyzeStream(ctx context.Context, userPrompt string, onToken func(string)) (string, error) {
+	response, err := i.analyze(ctx, userPrompt, onToken)
+	if err != nil {
+		return "", err
 	}
+	return response.Content, nil
+}
 
-	prompt := i.buildPrompt(userPrompt, PromptAnalyze)
-	content, err := i.queryModel(prompt)
+// analyze queries the model for an analysis and records it for reports
+func (i *IntelSystem) analyze(ctx context.Context, userPrompt string, onToken func(string)) (*Response, error) {
+	content, usage, err := i.streamPrompt(ctx, userPrompt, PromptAnalyze, onToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Response{
+	response := &Response{
 		Content:   content,
 		Type:      "analysis",
 		Timestamp: time.Now(),
@@ -202,69 +425,140 @@ func (i *IntelSystem) Analyze(userPrompt string) (*Response, error) {
 			"prompt_type":  "analyze",
 			"provider_count": len(i.providers),
 		},
-	}, nil
+	}
+	usage.addTo(response.Metadata)
+	i.recordAnalysis(userPrompt, response)
+
+	return response, nil
 }
 
 // Suggest provides AI-generated suggestions for next steps
-func (i *IntelSystem) Suggest(context string) (*Suggestions, error) {
-	if !i.IsInitialized() {
-		return nil, fmt.Errorf("Intel system not initialized")
-	}
-
-	prompt := i.buildPrompt(context, PromptSuggest)
-	content, err := i.queryModel(prompt)
+func (i *IntelSystem) Suggest(userContext string) (*Suggestions, error) {
+	content, usage, err := i.streamPrompt(context.Background(), userContext, PromptSuggest, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// Parse suggestions from the response
-	suggestions := &Suggestions{
-		NextSteps: []string{},
-		Commands:  []string{},
-		Tips:      []string{},
-		Warnings:  []string{},
-		Timestamp: time.Now(),
-		Metadata: map[string]interface{}{
-			"model":       i.config.Model,
-			"prompt_type": "suggest",
-		},
+	suggestions := ParseSuggestions(content)
+	suggestions.Timestamp = time.Now()
+	suggestions.Metadata = map[string]interface{}{
+		"model":       i.config.Model,
+		"prompt_type": "suggest",
 	}
-
-	// For now, put the entire response in NextSteps
-	// TODO: Parse structured output when implementing specific response formats
-	suggestions.NextSteps = append(suggestions.NextSteps, content)
+	usage.addTo(suggestions.Metadata)
 
 	return suggestions, nil
 }
 
+// SuggestStream is the token-streaming form of Suggest. It returns the raw
+// response text; ParseSuggestions turns it into Suggestions.
+func (i *IntelSystem) SuggestStream(ctx context.Context, userContext string, onToken func(string)) (string, error) {
+	content, _, err := i.streamPrompt(ctx, userContext, PromptSuggest, onToken)
+	return content, err
+}
+
 // Explain provides detailed explanations of concepts or findings
 func (i *IntelSystem) Explain(topic string) (*Explanation, error) {
-	if !i.IsInitialized() {
-		return nil, fmt.Errorf("Intel system not initialized")
+	content, usage, err := i.streamPrompt(context.Background(), topic, PromptExplain, nil)
+	if err != nil {
+		return nil, err
 	}
+	return newExplanation(topic, content, i.config.Model, "explain", usage), nil
+}
 
-	prompt := i.buildPrompt(topic, PromptExplain)
-	content, err := i.queryModel(prompt)
+// ExplainStream is the token-streaming form of Explain
+func (i *IntelSystem) ExplainStream(ctx context.Context, topic string, onToken func(string)) (string, error) {
+	content, _, err := i.streamPrompt(ctx, topic, PromptExplain, onToken)
+	return content, err
+}
+
+// Debug asks the model to troubleshoot an error or symptom. The prompt
+// includes recent failed commands with their full output.
+func (i *IntelSystem) Debug(symptom string) (*Explanation, error) {
+	content, usage, err := i.streamPrompt(context.Background(), symptom, PromptDebug, nil)
 	if err != nil {
 		return nil, err
 	}
+	return newExplanation(symptom, content, i.config.Model, "debug", usage), nil
+}
+
+// DebugStream is the token-streaming form of Debug
+func (i *IntelSystem) DebugStream(ctx context.Context, symptom string, onToken func(string)) (string, error) {
+	content, _, err := i.streamPrompt(ctx, symptom, PromptDebug, onToken)
+	return content, err
+}
+
+// streamPrompt builds a prompt of the given type around input and queries
+// the model, passing tokens to onToken
+func (i *IntelSystem) streamPrompt(ctx context.Context, input string, promptType PromptType, onToken func(string)) (string, TokenUsage, error) {
+	if !i.IsInitialized() {
+		return "", TokenUsage{}, fmt.Errorf("Intel system not initialized")
+	}
 
-	return &Explanation{
-		Topic:     topic,
-		Summary:   content, // TODO: Parse structured response
-		Details:   content,
-		Examples:  []string{},
+	prompt := i.buildPrompt(input, promptType)
+	return i.queryModel(ctx, prompt, promptType, onToken)
+}
+
+// newExplanation wraps a model response as an Explanation
+func newExplanation(topic, content, model, promptType string, usage TokenUsage) *Explanation {
+	explanation := &Explanation{
+		Topic:      topic,
+		Summary:    content, // TODO: Parse structured response
+		Details:    content,
+		Examples:   []string{},
 		References: []string{},
-		Timestamp: time.Now(),
+		Timestamp:  time.Now(),
 		Metadata: map[string]interface{}{
-			"model":       i.config.Model,
-			"prompt_type": "explain",
+			"model":       model,
+			"prompt_type": promptType,
 		},
-	}, nil
+	}
+	usage.addTo(explanation.Metadata)
+	return explanation
+}
+
+// recentFailures describes up to limit of the latest failed actions with
+// their output, newest last
+func (i *IntelSystem) recentFailures(limit int) string {
+	i.context.mu.RLock()
+	defer i.context.mu.RUnlock()
+
+	var failed []Action
+	for n := len(i.context.RecentActions) - 1; n >= 0 && len(failed) < limit; n-- {
+		if action := i.context.RecentActions[n]; !action.Success {
+			failed = append(failed, action)
+		}
+	}
+	if len(failed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Recent failures:\n")
+	for n := len(failed) - 1; n >= 0; n-- {
+		action := failed[n]
+		fmt.Fprintf(&b, "- ✗ %s %s (%s)\n", action.Command, strings.Join(action.Args, " "),
+			action.Timestamp.Format("15:04:05"))
+		if result := strings.TrimSpace(action.Result); result != "" {
+			fmt.Fprintf(&b, "  Error: %s\n", strings.ReplaceAll(result, "\n", "\n  "))
+		}
+	}
+	return b.String()
 }
 
-// AddAction records a command action for context
+// AddAction records a command action for context. In proactive mode it may
+// follow the command with a short suggestion.
 func (i *IntelSystem) AddAction(command string, args []string, result string, success bool) {
+	i.recordAction(command, args, result, success)
+
+	if hint := i.proactiveHint(command, success); hint != "" {
+		i.showProactiveHint(hint)
+	}
+}
+
+// recordAction appends an action to the recent history
+func (i *IntelSystem) recordAction(command string, args []string, result string, success bool) {
 	i.context.mu.Lock()
 	defer i.context.mu.Unlock()
 
@@ -295,23 +589,36 @@ func (i *IntelSystem) ensureModel() error {
 	}
 
 	// Check if our model is available
-	for _, model := range listResp.Models {
-		if model.Name == i.config.Model {
-			return nil // Model is available
-		}
+	if hasModel(listResp, i.config.Model) {
+		return nil
 	}
 
 	// Model not found, attempt to pull it
-	ShowPersonalityMessage("downloading")
-	fmt.Printf("📥 Downloading model %s...\n", i.config.Model)
+	return i.pullModel(ctx, i.client)
+}
+
+// hasModel reports whether list contains name; "phi3" matches "phi3:latest"
+func hasModel(list *api.ListResponse, name string) bool {
+	for _, model := range list.Models {
+		if model.Name == name || model.Name == name+":latest" {
+			return true
+		}
+	}
+	return false
+}
+
+// pullModel downloads the configured model with a progress display
+func (i *IntelSystem) pullModel(ctx context.Context, client *api.Client) error {
+	ShowPersonalityMessageTo(i.Output(), "downloading")
+	fmt.Fprintf(i.Output(), "📥 Downloading model %s...\n", i.config.Model)
 	
 	pullReq := &api.PullRequest{
 		Name: i.config.Model,
 	}
 
 	// Enhanced progress reporting with download tracker
-	tracker := NewDownloadTracker()
-	err = i.client.Pull(ctx, pullReq, func(resp api.ProgressResponse) error {
+	tracker := NewDownloadTracker().WithWriter(i.Output())
+	err := client.Pull(ctx, pullReq, func(resp api.ProgressResponse) error {
 		tracker.Update(resp)
 		return nil
 	})
@@ -320,103 +627,240 @@ func (i *IntelSystem) ensureModel() error {
 	}
 	
 	tracker.Complete()
-	fmt.Printf("✅ Model %s downloaded successfully\n", i.config.Model)
+	fmt.Fprintf(i.Output(), "✅ Model %s downloaded successfully\n", i.config.Model)
 	return nil
 }
 
-// queryModel sends a query to the LLM and returns the response with retry logic
-func (i *IntelSystem) queryModel(prompt string) (string, error) {
-	const maxRetries = 3
-	
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		ctx, cancel := context.WithTimeout(context.Background(), i.config.Timeout)
-		
-		req := &api.ChatRequest{
-			Model: i.config.Model,
-			Messages: []api.Message{
-				{
-					Role:    "user",
-					Content: prompt,
-				},
+// newChatRequest builds a chat request for a prompt using the configured model and options
+func (i *IntelSystem) newChatRequest(prompt string) *api.ChatRequest {
+	return &api.ChatRequest{
+		Model: i.config.Model,
+		Messages: []api.Message{
+			{
+				Role:    "user",
+				Content: prompt,
 			},
+		},
+		Options: i.config.Options.toAPIOptions(),
+		KeepAlive: i.keepAlive(),
+	}
+}
+
+// keepAlive converts Config.KeepAlive for Ollama requests
+func (i *IntelSystem) keepAlive() *api.Duration {
+	if i.config.KeepAlive == nil {
+		return nil
+	}
+	return &api.Duration{Duration: *i.config.KeepAlive}
+}
+
+// Unload asks Ollama to evict the configured model from memory now, by
+// sending a generate request with no prompt and a zero keep-alive. It works
+// whether or not the system has been initialized.
+func (i *IntelSystem) Unload() error {
+	if isHostedBackend(i.config.Backend) {
+		return NewConfigError("unload_unsupported",
+			fmt.Sprintf("The %s backend has no local model to unload", i.config.Backend), nil)
+	}
+
+	client := i.client
+	if client == nil {
+		var err error
+		if client, err = newOllamaClient(i.config); err != nil {
+			return NewOllamaError("client_creation_failed", "Failed to create Ollama client", err)
 		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), i.config.Timeout)
+	defer cancel()
+
+	req := &api.GenerateRequest{Model: i.config.Model, KeepAlive: &api.Duration{Duration: 0}}
+	if err := client.Generate(ctx, req, func(api.GenerateResponse) error { return nil }); err != nil {
+		return HandleError(err)
+	}
+	return nil
+}
+
+// queryModel sends a query to the LLM and returns the response and its
+// token counts, adding them to the usage totals and recording the query in
+// the audit log when one is configured. onToken, when set, receives the
+// response as it streams in.
+func (i *IntelSystem) queryModel(ctx context.Context, prompt string, promptType PromptType, onToken func(string)) (string, TokenUsage, error) {
+	start := time.Now()
+	content, cached, err := i.runQuery(ctx, prompt, promptType, onToken)
+	usage := i.countUsage(prompt, content, cached, err)
+	i.recordUsage(usage, err, start)
+	i.auditQuery(promptType, prompt, content, start, usage, err)
+	return content, usage, err
+}
+
+// runQuery serves a prompt from the cache or the model, retrying retryable
+// failures up to Config.MaxRetries times. cached reports whether the
+// response came from the cache, which reaches onToken in one piece. Once
+// tokens have been streamed a failed attempt can't be taken back, so it is
+// no longer retried.
+func (i *IntelSystem) runQuery(ctx context.Context, prompt string, promptType PromptType, onToken func(string)) (content string, cached bool, err error) {
+	// Serve repeated prompts from the cache when enabled
+	var key string
+	if i.cache != nil {
+		key = cacheKey(i.config.Model, prompt)
+		if content, ok := i.cache.Get(key); ok {
+			if onToken != nil {
+				onToken(content)
+			}
+			return content, true, nil
+		}
+	}
+	
+	// The prompt type's timeout bounds the whole operation, including retries
+	ctx, cancel := context.WithTimeout(ctx, i.config.TimeoutFor(promptType))
+	defer cancel()
+	
+	if err := i.acquireQuery(ctx); err != nil {
+		return "", false, err
+	}
+	
+	retriedEmpty := false
+	for retry := 0; ; retry++ {
+		req := i.newChatRequest(prompt)
 
 		var response strings.Builder
-		err := i.client.Chat(ctx, req, func(resp api.ChatResponse) error {
+		err := i.backend.Chat(ctx, req, func(resp api.ChatResponse) error {
 			response.WriteString(resp.Message.Content)
+			if onToken != nil && resp.Message.Content != "" {
+				onToken(resp.Message.Content)
+			}
 			return nil
 		})
-		
-		cancel()
+
+		// An empty answer gets one immediate retry of its own
+		if err == nil && strings.TrimSpace(response.String()) == "" {
+			if !retriedEmpty && ctx.Err() == nil {
+				retriedEmpty = true
+				retry--
+				continue
+			}
+			return "", false, i.emptyResponseError()
+		}
 
 		if err == nil {
-			return response.String(), nil
+			if i.cache != nil {
+				i.cache.Put(key, response.String())
+			}
+			return response.String(), false, nil
 		}
 		
 		// Handle error with retry logic
 		intelErr := HandleError(err)
-		if !intelErr.RetryableError() || attempt == maxRetries {
-			return "", intelErr
+		streamed := onToken != nil && response.Len() > 0
+		if !intelErr.RetryableError() || streamed || retry >= i.config.MaxRetries || ctx.Err() != nil {
+			return "", false, intelErr
 		}
 		
-		// Wait before retry
-		delay := time.Duration(intelErr.GetRetryDelay()) * time.Second
-		fmt.Printf("%s⏳ Retrying in %v... (attempt %d/%d)%s\n", 
-			output.YellowColor, delay, attempt, maxRetries, output.Reset)
-		time.Sleep(delay)
+		// Back off exponentially, but never past the overall deadline
+		delay := intelErr.BackoffDelay(retry + 1)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return "", false, intelErr
+		}
+		i.reportRetry(intelErr, delay, retry+1)
+		
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", false, intelErr
+		}
 	}
+}
 
-	return "", NewNetworkError("max_retries", "Maximum retry attempts exceeded", nil)
+// reportRetry tells the user a failed query is about to be retried, unless
+// Config.QuietRetries is set or output is JSON
+func (i *IntelSystem) reportRetry(err *IntelError, delay time.Duration, retry int) {
+	if i.config.QuietRetries || output.CurrentMode == output.ModeJSON {
+		return
+	}
+	fmt.Fprintf(i.Output(), "%s⏳ %s, retrying in %v (retry %d/%d)%s\n",
+		output.YellowColor, err.Message, delay.Round(100*time.Millisecond), retry, i.config.MaxRetries, output.Reset)
 }
 
-// queryModelWithStreaming sends a query to the LLM and streams the response with formatting
-func (i *IntelSystem) queryModelWithStreaming(prompt string, onToken func(string), onComplete func(string)) error {
-	ctx, cancel := context.WithTimeout(context.Background(), i.config.Timeout)
-	defer cancel()
+// emptyResponseError reports a model that answered with nothing, which
+// happens with some quantized models or when generation is cut short
+func (i *IntelSystem) emptyResponseError() *IntelError {
+	return NewModelError("empty_response", "model returned empty response", nil).
+		WithContext("model", i.config.Model)
+}
 
-	req := &api.ChatRequest{
-		Model: i.config.Model,
-		Messages: []api.Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-	}
+// buildPrompt constructs an intelligent prompt based on context and providers.
+// Scoring, updating and building hold contextMu together, so concurrent
+// queries such as a batch analysis each see a consistent context.
+func (i *IntelSystem) buildPrompt(userQuery string, promptType PromptType) string {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	return i.buildPromptWith(i.contextManager, userQuery, promptType)
+}
 
-	var response strings.Builder
-	err := i.client.Chat(ctx, req, func(resp api.ChatResponse) error {
-		if resp.Message.Content != "" {
-			response.WriteString(resp.Message.Content)
-			if onToken != nil {
-				onToken(resp.Message.Content)
-			}
-		}
-		return nil
-	})
+// buildPromptWith builds a prompt using cm, scoring and updating its context
+func (i *IntelSystem) buildPromptWith(cm *ContextManager, userQuery string, promptType PromptType) string {
+	// Score existing context against the query so optimization keeps relevant items
+	cm.ScoreQuery(userQuery)
+	
+	// Update context manager with current information
+	i.updateContextManager(cm, promptType)
+	
+	// Use context manager to build optimized prompt
+	return cm.BuildPrompt(userQuery, promptType)
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to query model: %w", err)
-	}
+// DumpPrompt builds the full prompt for a query without sending it to the
+// model. It works on a copy of the context, so the next real query is
+// unaffected. Values of sensitive state keys are masked in the returned text.
+func (i *IntelSystem) DumpPrompt(userQuery string, promptType PromptType) string {
+	i.contextMu.Lock()
+	cm := i.contextManager.clone()
+	i.contextMu.Unlock()
+	return i.maskSensitiveStateLines(i.buildPromptWith(cm, userQuery, promptType))
+}
 
-	if onComplete != nil {
-		onComplete(response.String())
+// maskSensitiveStateLines masks "- key: value" lines whose key is sensitive
+// under the attached state's rules (see SetState)
+func (i *IntelSystem) maskSensitiveStateLines(prompt string) string {
+	lines := strings.Split(prompt, "\n")
+	for idx, line := range lines {
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(line, "- "), ": ", 2)
+		if len(parts) == 2 && i.isSensitiveKey(parts[0]) {
+			lines[idx] = fmt.Sprintf("- %s: %s", parts[0], utils.MaskString(parts[1], 4, 4))
+		}
 	}
+	return strings.Join(lines, "\n")
+}
 
-	return nil
+// SetState attaches the application state. Its sensitive keys, including
+// patterns added with AddSensitivePattern, are masked in prompt dumps, the
+// audit log and exported context. RegisterIntelCommands attaches the
+// console's state.
+func (i *IntelSystem) SetState(state *config.State) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.state = state
 }
 
-// buildPrompt constructs an intelligent prompt based on context and providers
-func (i *IntelSystem) buildPrompt(userQuery string, promptType PromptType) string {
-	// Update context manager with current information
-	i.updateContextManager(promptType)
+// isSensitiveKey checks key against the attached state, or the built-in
+// patterns when there is none
+func (i *IntelSystem) isSensitiveKey(key string) bool {
+	i.mu.RLock()
+	state := i.state
+	i.mu.RUnlock()
 	
-	// Use context manager to build optimized prompt
-	return i.contextManager.BuildPrompt(userQuery, promptType)
+	if state != nil {
+		return state.IsSensitive(key)
+	}
+	return config.IsSensitiveKey(key)
 }
 
 // updateContextManager updates the context manager with current information
-func (i *IntelSystem) updateContextManager(promptType PromptType) {
+func (i *IntelSystem) updateContextManager(cm *ContextManager, promptType PromptType) {
 	// 1. Add system prompt
 	systemPrompt := i.config.SystemPrompt + `
 
@@ -439,20 +883,18 @@ FORMATTING RULES:
 - Use numbered lists for steps
 - Avoid excessive technical jargon`
 
-	i.contextManager.AddContext("system", ContextTypeSystem, systemPrompt, true)
+	cm.AddContext("system", ContextTypeSystem, systemPrompt, true)
 	
-	// 2. Add domain knowledge from providers
+	// 2. Add domain knowledge from providers, budgeted by weight
+	knowledge := make([]DomainKnowledge, 0, len(i.providers))
 	for _, provider := range i.providers {
-		knowledge := provider.GetDomainKnowledge()
-		if knowledge != "" {
-			i.contextManager.AddContext(
-				fmt.Sprintf("domain-%s", provider.Name()),
-				ContextTypeDomain,
-				knowledge,
-				true,
-			)
-		}
+		knowledge = append(knowledge, DomainKnowledge{
+			Provider:  provider.Name(),
+			Knowledge: provider.GetDomainKnowledge(),
+			Weight:    i.GetProviderWeight(provider.Name()),
+		})
 	}
+	cm.AddDomainKnowledge(knowledge)
 	
 	// 3. Add current state (only key information)
 	for _, provider := range i.providers {
@@ -469,7 +911,8 @@ FORMATTING RULES:
 				}
 			}
 			
-			i.contextManager.AddContext(
+			cm.AddProviderContext(
+				provider.Name(),
 				fmt.Sprintf("state-%s", provider.Name()),
 				ContextTypeState,
 				stateStr.String(),
@@ -498,7 +941,7 @@ FORMATTING RULES:
 				status, action.Command, action.Args))
 		}
 		
-		i.contextManager.AddContext(
+		cm.AddContext(
 			"history",
 			ContextTypeHistory,
 			historyStr.String(),
@@ -507,10 +950,17 @@ FORMATTING RULES:
 	}
 	i.context.mu.RUnlock()
 	
-	// 5. Add custom prompt template if available
-	if template, exists := i.config.CustomPrompts[string(promptType)]; exists {
+	// Debug prompts get every recent failure with its full output
+	if promptType == PromptDebug {
+		if failures := i.recentFailures(5); failures != "" {
+			cm.AddContext("failures", ContextTypeHistory, failures, true)
+		}
+	}
+	
+	// 5. Add the task template: provider, then config, then built-in
+	if template := i.EffectivePrompt(promptType); template != "" {
 		promptStr := fmt.Sprintf("Task: %s", template)
-		i.contextManager.AddContext(
+		cm.AddContext(
 			fmt.Sprintf("prompt-%s", promptType),
 			ContextTypePrompt,
 			promptStr,
@@ -519,7 +969,12 @@ FORMATTING RULES:
 	}
 	
 	// Periodic cleanup
-	i.contextManager.PruneHistory()
+	cm.PruneHistory()
+}
+
+// UsesHostedBackend reports whether queries go to a hosted API instead of Ollama
+func (i *IntelSystem) UsesHostedBackend() bool {
+	return isHostedBackend(i.config.Backend)
 }
 
 // GetOllamaStatus returns the current Ollama status
@@ -534,24 +989,168 @@ func (i *IntelSystem) GetOllamaManager() *OllamaManager {
 
 // GetContextStats returns current context statistics
 func (i *IntelSystem) GetContextStats() map[string]interface{} {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
 	return i.contextManager.GetStats()
 }
 
 // GetContextSummary returns a summary of current context
 func (i *IntelSystem) GetContextSummary() string {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
 	return i.contextManager.GetContextSummary()
 }
 
 // ClearContext clears all context
 func (i *IntelSystem) ClearContext() {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
 	i.contextManager.Clear()
 }
 
+// GetContextItems returns a copy of all context items
+func (i *IntelSystem) GetContextItems() []ContextItem {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	return i.contextManager.GetItems()
+}
+
+// SearchContext returns context items containing query, most relevant first
+func (i *IntelSystem) SearchContext(query string) []ContextItem {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	return i.contextManager.Search(query)
+}
+
+// PinContext pins a context item so it survives pruning
+func (i *IntelSystem) PinContext(id string) error {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	return i.contextManager.Pin(id)
+}
+
+// UnpinContext makes a pinned context item prunable again
+func (i *IntelSystem) UnpinContext(id string) error {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	return i.contextManager.Unpin(id)
+}
+
+// GetCacheStats returns response cache statistics
+func (i *IntelSystem) GetCacheStats() map[string]interface{} {
+	if i.cache == nil {
+		return map[string]interface{}{"enabled": false}
+	}
+	return i.cache.Stats()
+}
+
+// ClearCache removes all cached responses
+func (i *IntelSystem) ClearCache() {
+	if i.cache != nil {
+		i.cache.Clear()
+	}
+}
+
 // SetMaxTokens updates the maximum token limit
 func (i *IntelSystem) SetMaxTokens(maxTokens int) {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
 	i.contextManager.SetMaxTokens(maxTokens)
 }
 
+// SetOutput sets where streamed responses and spinners are written. Call it
+// before the console runs. RegisterIntelCommands points it at the console's
+// Stdout() so output goes through readline instead of racing the prompt
+// redraw.
+func (i *IntelSystem) SetOutput(w io.Writer) {
+	i.out = w
+}
+
+// Output returns the writer set with SetOutput, or os.Stdout
+func (i *IntelSystem) Output() io.Writer {
+	if i.out == nil {
+		return os.Stdout
+	}
+	return i.out
+}
+
+// newFormatter returns a formatter writing to Output with the configured
+// typing delay
+func (i *IntelSystem) newFormatter() *StreamingFormatter {
+	return NewStreamingFormatter().
+		WithWriter(i.Output()).
+		WithTypingDelay(i.typingDelay()).
+		WithPostProcessors(i.postProcessors.Clone())
+}
+
+// AddPostProcessor registers a cleanup step run on responses before they
+// are displayed. A name already in the chain is replaced in place, which
+// also overrides one of the defaults.
+func (i *IntelSystem) AddPostProcessor(name string, processor PostProcessor) {
+	i.postProcessors.Add(name, processor)
+}
+
+// RemovePostProcessor disables a cleanup step, such as PostProcessFiller,
+// and reports whether it was registered
+func (i *IntelSystem) RemovePostProcessor(name string) bool {
+	return i.postProcessors.Remove(name)
+}
+
+// PostProcessors returns the names of the cleanup steps run on responses,
+// in order
+func (i *IntelSystem) PostProcessors() []string {
+	return i.postProcessors.Names()
+}
+
+// typingDelay is Config.TypingDelay, or 0 when output isn't watched by a
+// person: stdout is not a terminal or JSON output is on
+func (i *IntelSystem) typingDelay() time.Duration {
+	if output.CurrentMode == output.ModeJSON || !output.IsTerminal(os.Stdout) {
+		return 0
+	}
+	return i.config.TypingDelay
+}
+
+// SetContextCompaction controls whether context pruned to fit the token
+// budget is summarized into a single history item (the default) or dropped
+func (i *IntelSystem) SetContextCompaction(enabled bool) {
+	i.contextMu.Lock()
+	defer i.contextMu.Unlock()
+	i.contextManager.SetCompaction(enabled)
+}
+
+// EffectivePrompt returns the task template used for promptType. A template
+// set by a provider with SetPromptTemplate wins over Config.CustomPrompts,
+// which wins over the built-in default; providers are consulted in the order
+// they were added. It returns "" when no template applies.
+func (i *IntelSystem) EffectivePrompt(promptType PromptType) string {
+	template, _ := i.resolvePrompt(promptType)
+	return template
+}
+
+// PromptSource reports where EffectivePrompt's template comes from:
+// "provider <name>", "config", "default" or "" when there is none
+func (i *IntelSystem) PromptSource(promptType PromptType) string {
+	_, source := i.resolvePrompt(promptType)
+	return source
+}
+
+// resolvePrompt walks the template override chain for promptType
+func (i *IntelSystem) resolvePrompt(promptType PromptType) (template, source string) {
+	for _, provider := range i.providers {
+		if template := provider.GetPromptTemplates()[string(promptType)]; strings.TrimSpace(template) != "" {
+			return template, "provider " + provider.Name()
+		}
+	}
+	if template := i.config.CustomPrompts[string(promptType)]; strings.TrimSpace(template) != "" {
+		return template, "config"
+	}
+	if template, exists := defaultPromptTemplates[promptType]; exists {
+		return template, "default"
+	}
+	return "", ""
+}
+
 // getRelevantStateKeys returns state keys relevant to the current prompt type
 func (i *IntelSystem) getRelevantStateKeys(promptType PromptType) []string {
 	switch promptType {
@@ -561,70 +1160,42 @@ func (i *IntelSystem) getRelevantStateKeys(promptType PromptType) []string {
 		return []string{"target_url", "authenticated", "discoveries_count", "schema_discovered"}
 	case PromptExplain:
 		return []string{"target_url", "authenticated"} // Minimal context for explanations
+	case PromptDebug:
+		return []string{"target_url", "authenticated", "last_error", "last_command", "proxy", "timeout"}
 	default:
 		return []string{"target_url", "authenticated", "total_discoveries"}
 	}
 }
 
-// AnalyzeWithStreaming performs AI analysis with streaming output
+// AnalyzeWithStreaming performs AI analysis and displays the formatted
+// response on Output
 func (i *IntelSystem) AnalyzeWithStreaming(userPrompt string) error {
-	if !i.IsInitialized() {
-		return fmt.Errorf("Intel system not initialized")
-	}
-
-	prompt := i.buildPrompt(userPrompt, PromptAnalyze)
-	
-	// Use the regular query method and format the result
-	content, err := i.queryModel(prompt)
-	if err != nil {
-		return err
-	}
-	
-	// Format and display the response properly
-	formatter := NewStreamingFormatter()
-	formatter.FormatAndDisplayResponse(content)
-	
-	return nil
+	return i.display(i.AnalyzeStream(context.Background(), userPrompt, nil))
 }
 
-// SuggestWithStreaming provides AI-generated suggestions with streaming output
-func (i *IntelSystem) SuggestWithStreaming(context string) error {
-	if !i.IsInitialized() {
-		return fmt.Errorf("Intel system not initialized")
-	}
+// SuggestWithStreaming provides AI-generated suggestions and displays the
+// formatted response on Output
+func (i *IntelSystem) SuggestWithStreaming(userContext string) error {
+	return i.display(i.SuggestStream(context.Background(), userContext, nil))
+}
 
-	prompt := i.buildPrompt(context, PromptSuggest)
-	
-	// Use the regular query method and format the result
-	content, err := i.queryModel(prompt)
-	if err != nil {
-		return err
-	}
-	
-	// Format and display the response properly
-	formatter := NewStreamingFormatter()
-	formatter.FormatAndDisplayResponse(content)
-	
-	return nil
+// DebugWithStreaming troubleshoots an error or symptom and displays the
+// formatted response
+func (i *IntelSystem) DebugWithStreaming(symptom string) error {
+	return i.display(i.DebugStream(context.Background(), symptom, nil))
 }
 
-// ExplainWithStreaming provides detailed explanations with streaming output
+// ExplainWithStreaming provides detailed explanations and displays the
+// formatted response on Output
 func (i *IntelSystem) ExplainWithStreaming(topic string) error {
-	if !i.IsInitialized() {
-		return fmt.Errorf("Intel system not initialized")
-	}
+	return i.display(i.ExplainStream(context.Background(), topic, nil))
+}
 
-	prompt := i.buildPrompt(topic, PromptExplain)
-	
-	// Use the regular query method and format the result
-	content, err := i.queryModel(prompt)
+// display formats a complete response onto Output
+func (i *IntelSystem) display(content string, err error) error {
 	if err != nil {
 		return err
 	}
-	
-	// Format and display the response properly
-	formatter := NewStreamingFormatter()
-	formatter.FormatAndDisplayResponse(content)
-	
+	i.newFormatter().FormatAndDisplayResponse(content)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,98 @@
+package intel
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// Tokenizer counts tokens for a piece of text
+type Tokenizer interface {
+	CountTokens(text string) int
+	Name() string
+}
+
+// heuristicTokenizer estimates tokens as ~4 characters per token
+type heuristicTokenizer struct{}
+
+// CountTokens returns a rough token estimate
+func (heuristicTokenizer) CountTokens(text string) int {
+	return len(text) / 4
+}
+
+// Name returns the tokenizer name
+func (heuristicTokenizer) Name() string {
+	return "heuristic"
+}
+
+// bpeTokenizer counts tokens with a tiktoken-compatible BPE encoding
+type bpeTokenizer struct {
+	encoding string
+	enc      *tiktoken.Tiktoken
+}
+
+// CountTokens returns the exact number of BPE tokens in text
+func (t *bpeTokenizer) CountTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return len(t.enc.EncodeOrdinary(text))
+}
+
+// Name returns the encoding used by the tokenizer
+func (t *bpeTokenizer) Name() string {
+	return t.encoding
+}
+
+var (
+	loaderOnce sync.Once
+	encodingMu sync.Mutex
+	encodings  = make(map[string]*tiktoken.Tiktoken)
+)
+
+// loadEncoding returns a cached BPE encoding, loading it from the embedded ranks on first use
+func loadEncoding(name string) (*tiktoken.Tiktoken, error) {
+	loaderOnce.Do(func() {
+		tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+	})
+
+	encodingMu.Lock()
+	defer encodingMu.Unlock()
+
+	if enc, ok := encodings[name]; ok {
+		return enc, nil
+	}
+
+	enc, err := tiktoken.GetEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	encodings[name] = enc
+	return enc, nil
+}
+
+// encodingForModel picks the BPE encoding that best approximates a model family
+func encodingForModel(model string) string {
+	model = strings.ToLower(model)
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"), strings.HasPrefix(model, "o1"), strings.HasPrefix(model, "o3"):
+		return tiktoken.MODEL_O200K_BASE
+	default:
+		// Llama 3, Qwen, Phi-3 and Gemma all use large byte-level BPE vocabularies
+		// that track cl100k far more closely than a character heuristic
+		return tiktoken.MODEL_CL100K_BASE
+	}
+}
+
+// NewTokenizer returns a tokenizer for the given model family, falling back
+// to the character heuristic if no BPE encoding can be loaded
+func NewTokenizer(model string) Tokenizer {
+	name := encodingForModel(model)
+	enc, err := loadEncoding(name)
+	if err != nil {
+		return heuristicTokenizer{}
+	}
+	return &bpeTokenizer{encoding: name, enc: enc}
+}
\ No newline at end of file
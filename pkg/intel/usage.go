@@ -0,0 +1,151 @@
+package intel
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// TokenUsage is the token count of a single query
+type TokenUsage struct {
+	PromptTokens   int  `json:"prompt_tokens"`
+	ResponseTokens int  `json:"response_tokens"`
+	Cached         bool `json:"cached,omitempty"` // served from the cache; no tokens were spent
+}
+
+// TotalTokens is the sum of prompt and response tokens
+func (u TokenUsage) TotalTokens() int {
+	return u.PromptTokens + u.ResponseTokens
+}
+
+// addTo records the counts in a response's metadata
+func (u TokenUsage) addTo(metadata map[string]interface{}) {
+	metadata["prompt_tokens"] = u.PromptTokens
+	metadata["response_tokens"] = u.ResponseTokens
+	metadata["total_tokens"] = u.TotalTokens()
+	if u.Cached {
+		metadata["cached"] = true
+	}
+}
+
+// Usage is accumulated query and token counts. Tokens are counted with the
+// model's tokenizer; queries served from the cache spend none.
+type Usage struct {
+	Queries        int       `json:"queries"`
+	CachedQueries  int       `json:"cached_queries"`
+	FailedQueries  int       `json:"failed_queries"`
+	PromptTokens   int       `json:"prompt_tokens"`
+	ResponseTokens int       `json:"response_tokens"`
+	Since          time.Time `json:"since"`
+}
+
+// TotalTokens is the sum of prompt and response tokens
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.ResponseTokens
+}
+
+// EstimatedCost prices the tokens at ratePer1K per thousand tokens
+func (u Usage) EstimatedCost(ratePer1K float64) float64 {
+	return float64(u.TotalTokens()) / 1000 * ratePer1K
+}
+
+// add counts one query
+func (u *Usage) add(query TokenUsage, failed bool, at time.Time) {
+	if u.Since.IsZero() || at.Before(u.Since) {
+		u.Since = at
+	}
+	u.Queries++
+	switch {
+	case failed:
+		u.FailedQueries++
+	case query.Cached:
+		u.CachedQueries++
+	}
+	u.PromptTokens += query.PromptTokens
+	u.ResponseTokens += query.ResponseTokens
+}
+
+// merge adds the counts of other
+func (u Usage) merge(other Usage) Usage {
+	if u.Since.IsZero() || (!other.Since.IsZero() && other.Since.Before(u.Since)) {
+		u.Since = other.Since
+	}
+	u.Queries += other.Queries
+	u.CachedQueries += other.CachedQueries
+	u.FailedQueries += other.FailedQueries
+	u.PromptTokens += other.PromptTokens
+	u.ResponseTokens += other.ResponseTokens
+	return u
+}
+
+// usageTracker holds this session's usage and the totals of earlier
+// sessions read from the audit log
+type usageTracker struct {
+	session  Usage
+	previous Usage
+	mu       sync.Mutex
+}
+
+// Usage returns the query and token counts of this session
+func (i *IntelSystem) Usage() Usage {
+	i.tokens.mu.Lock()
+	defer i.tokens.mu.Unlock()
+	return i.tokens.session
+}
+
+// TotalUsage returns this session's usage plus that of earlier sessions
+// recorded in the audit log. Without an audit log it equals Usage.
+func (i *IntelSystem) TotalUsage() Usage {
+	i.tokens.mu.Lock()
+	defer i.tokens.mu.Unlock()
+	return i.tokens.previous.merge(i.tokens.session)
+}
+
+// recordUsage counts a finished query
+func (i *IntelSystem) recordUsage(query TokenUsage, err error, at time.Time) {
+	i.tokens.mu.Lock()
+	defer i.tokens.mu.Unlock()
+	i.tokens.session.add(query, err != nil, at)
+}
+
+// countUsage counts the tokens of a query with the model's tokenizer.
+// Cached and failed queries count no tokens.
+func (i *IntelSystem) countUsage(prompt, response string, cached bool, err error) TokenUsage {
+	if cached || err != nil {
+		return TokenUsage{Cached: cached}
+	}
+	return TokenUsage{
+		PromptTokens:   i.contextManager.CountTokens(prompt),
+		ResponseTokens: i.contextManager.CountTokens(response),
+	}
+}
+
+// loadAuditUsage totals the queries recorded in the audit log at path and
+// its rotated copy. Missing or unreadable files count as empty.
+func loadAuditUsage(path string) Usage {
+	var usage Usage
+	for _, name := range []string{path + ".1", path} {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var record AuditRecord
+			if json.Unmarshal(scanner.Bytes(), &record) != nil {
+				continue
+			}
+			usage.add(TokenUsage{
+				PromptTokens:   record.PromptTokens,
+				ResponseTokens: record.ResponseTokens,
+				Cached:         record.Cached,
+			}, !record.Success, record.Timestamp)
+		}
+		f.Close()
+	}
+	return usage
+}
\ No newline at end of file
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"io"
+	"regexp"
+)
+
+// ansiRegex matches ANSI control sequences (colors, cursor movement, line
+// clearing) and OSC sequences such as the OSC 8 markers around hyperlinks
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x1b\a]*(?:\x1b\\|\a)`)
+
+// StripANSI removes escape sequences from s, leaving only the visible text.
+// Hyperlinks keep their text and lose the URL.
+func StripANSI(s string) string {
+	return ansiRegex.ReplaceAllString(s, "")
+}
+
+// StripANSIWriter returns a writer that removes escape sequences before
+// writing to w. A sequence split across two writes is passed through.
+func StripANSIWriter(w io.Writer) io.Writer {
+	return stripWriter{w}
+}
+
+// stripWriter is the writer returned by StripANSIWriter
+type stripWriter struct {
+	w io.Writer
+}
+
+// Write writes p without escape sequences, reporting all of p as written
+func (s stripWriter) Write(p []byte) (int, error) {
+	if _, err := s.w.Write(ansiRegex.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
\ No newline at end of file
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "GET /api/users 200 OK", "GET /api/users 200 OK"},
+		{"plain text with symbols", "50% done; [x] ok ~ 3", "50% done; [x] ok ~ 3"},
+		{"empty", "", ""},
+		{"unicode", "✓ ready — 日本語", "✓ ready — 日本語"},
+		{"single sgr", "\x1b[31mred\x1b[0m", "red"},
+		{"sgr with several parameters", "\x1b[1;4;38;5;208mbold orange\x1b[0m", "bold orange"},
+		{"truecolor sgr", "\x1b[38;2;255;100;0mwarm\x1b[39m", "warm"},
+		{"bare reset", "a\x1b[mb", "ab"},
+		{"adjacent sequences", "\x1b[1m\x1b[32m\x1b[4mok\x1b[0m\x1b[0m", "ok"},
+		{"nested color and reset", "\x1b[1mbold \x1b[31mred\x1b[39m bold\x1b[22m plain", "bold red bold plain"},
+		{"cursor and erase", "\x1b[2K\rline\x1b[1A", "\rline"},
+		{"private mode", "\x1b[?25lhidden cursor\x1b[?25h", "hidden cursor"},
+		{"osc hyperlink with st", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"osc title with bel", "\x1b]0;window title\atext", "text"},
+		{"osc around colored text", "\x1b]8;;http://x\a\x1b[34mblue\x1b[0m\x1b]8;;\a!", "blue!"},
+	}
+	for _, tt := range tests {
+		if got := StripANSI(tt.in); got != tt.want {
+			t.Errorf("%s: StripANSI(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStripANSIWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := StripANSIWriter(&buf)
+
+	in := "\x1b[1;32mpass\x1b[0m plain\n"
+	n, err := w.Write([]byte(in))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(in) {
+		t.Errorf("Write reported %d bytes, want %d", n, len(in))
+	}
+	if got := buf.String(); got != "pass plain\n" {
+		t.Errorf("wrote %q, want %q", got, "pass plain\n")
+	}
+}
\ No newline at end of file
@@ -69,7 +69,7 @@ func CenterText(text string, width int) string {
 // GenerateConsoleBanner creates a banner for console applications
 func GenerateConsoleBanner(appName, description string) string {
 	banner := CreateBoxBanner(appName, description)
-	return CyanColor + banner + Reset
+	return CurrentTheme().Primary + banner + Reset
 }
 
 // PrintWelcome prints a welcome message with app info
@@ -85,11 +85,15 @@ func PrintWelcome(appName, version, description string) {
 		CenterText("v"+version, 39),
 		CenterText(description, 39))
 	
-	fmt.Println(CyanColor + banner + Reset)
+	fmt.Println(CurrentTheme().Primary + banner + Reset)
 }
 
-// PrintSeparator prints a visual separator
+// PrintSeparator prints a visual separator. A length of 0 or less spans the
+// terminal width.
 func PrintSeparator(char string, length int, color string) {
+	if length <= 0 {
+		length = TerminalWidth()
+	}
 	separator := strings.Repeat(char, length)
 	if color != "" {
 		fmt.Println(color + separator + Reset)
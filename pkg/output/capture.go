@@ -0,0 +1,61 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// captureMu serializes captures since they swap the process-wide os.Stdout
+var captureMu sync.Mutex
+
+// capturing is set while CaptureStdout runs
+var capturing atomic.Bool
+
+// Capturing reports whether CaptureStdout is running. Writers that bypass
+// os.Stdout, such as a console's readline output, check it so captured
+// commands don't leak to the terminal.
+func Capturing() bool {
+	return capturing.Load()
+}
+
+// CaptureStdout runs fn with os.Stdout redirected to a buffer and returns
+// what it printed, with escape sequences stripped, along with fn's error.
+// Output written by other goroutines during the call is captured too.
+func CaptureStdout(fn func() error) (string, error) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+
+	original := os.Stdout
+	os.Stdout = writer
+	capturing.Store(true)
+
+	// Drain the pipe while fn runs so large output can't block it
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, reader)
+		close(done)
+	}()
+
+	runErr := func() error {
+		// Restore stdout even if fn panics
+		defer func() {
+			capturing.Store(false)
+			os.Stdout = original
+			writer.Close()
+		}()
+		return fn()
+	}()
+
+	<-done
+	reader.Close()
+	return StripANSI(buf.String()), runErr
+}
\ No newline at end of file
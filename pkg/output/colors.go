@@ -8,10 +8,18 @@ const (
 	YellowColor = "\033[33m"
 	CyanColor   = "\033[36m"
 	BoldColor   = "\033[1m"
+	DimColor    = "\033[2m"
 )
 
+// ColorEnabled controls whether Colorize and the helpers built on it emit
+// ANSI escape codes. Disable it when writing to files or pipes.
+var ColorEnabled = true
+
 // Colorize wraps text with the specified color
 func Colorize(text, color string) string {
+	if !ColorEnabled {
+		return text
+	}
 	return color + text + Reset
 }
 
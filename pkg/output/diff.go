@@ -0,0 +1,164 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is how many unchanged lines Diff shows around each change
+const diffContext = 3
+
+// diffLine is one line of a line-by-line comparison: ' ' unchanged,
+// '-' only in the old text, '+' only in the new text
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// Diff returns a unified diff from old to new, with deletions in red,
+// additions in green and hunk headers in cyan when colors are enabled.
+// Identical texts return an empty string.
+func Diff(old, new string) string {
+	lines := diffLines(splitLines(old), splitLines(new))
+
+	var b strings.Builder
+	for start := 0; start < len(lines); {
+		// Find the next change and extend the hunk while changes are close
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		end := first
+		for n := first; n < len(lines); n++ {
+			if lines[n].op != ' ' {
+				end = n + 1
+			} else if n-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(end+diffContext, len(lines))
+		writeHunk(&b, lines, from, to)
+		start = to
+	}
+	return b.String()
+}
+
+// writeHunk writes lines[from:to] with its @@ header
+func writeHunk(b *strings.Builder, lines []diffLine, from, to int) {
+	oldStart, newStart := 1, 1
+	for _, line := range lines[:from] {
+		if line.op != '+' {
+			oldStart++
+		}
+		if line.op != '-' {
+			newStart++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, line := range lines[from:to] {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+	}
+
+	// An empty side is numbered from the line before it, as diff -u does
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	b.WriteString(Cyan(fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)))
+	b.WriteString("\n")
+	for _, line := range lines[from:to] {
+		switch line.op {
+		case '-':
+			b.WriteString(Red("-" + line.text))
+		case '+':
+			b.WriteString(Green("+" + line.text))
+		default:
+			b.WriteString(" " + line.text)
+		}
+		b.WriteString("\n")
+	}
+}
+
+// splitLines splits text into lines, ignoring a final newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines aligns a and b on their longest common subsequence of lines
+func diffLines(a, b []string) []diffLine {
+	// Common prefix and suffix don't need the quadratic table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]diffLine, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		result = append(result, diffLine{' ', text})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the common subsequence length of midA[i:] and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) && j < len(midB) {
+		switch {
+		case midA[i] == midB[j]:
+			result = append(result, diffLine{' ', midA[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, diffLine{'-', midA[i]})
+			i++
+		default:
+			result = append(result, diffLine{'+', midB[j]})
+			j++
+		}
+	}
+	for ; i < len(midA); i++ {
+		result = append(result, diffLine{'-', midA[i]})
+	}
+	for ; j < len(midB); j++ {
+		result = append(result, diffLine{'+', midB[j]})
+	}
+
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, diffLine{' ', text})
+	}
+	return result
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HyperlinksEnabled controls whether Hyperlink emits OSC 8 escape sequences.
+// It is detected from the environment at startup and may be overridden.
+var HyperlinksEnabled = DetectHyperlinks()
+
+// hyperlinkTerminals are $TERM_PROGRAM values of terminals known to
+// support OSC 8 links
+var hyperlinkTerminals = []string{"iterm.app", "wezterm", "vscode", "hyper", "ghostty", "tabby"}
+
+// DetectHyperlinks reports whether the terminal is known to render OSC 8
+// hyperlinks, based on $TERM_PROGRAM, $TERM, $VTE_VERSION and $WT_SESSION
+func DetectHyperlinks() bool {
+	program := strings.ToLower(os.Getenv("TERM_PROGRAM"))
+	for _, known := range hyperlinkTerminals {
+		if program == known {
+			return true
+		}
+	}
+
+	switch {
+	case os.Getenv("WT_SESSION") != "": // Windows Terminal
+		return true
+	case strings.Contains(os.Getenv("TERM"), "kitty"):
+		return true
+	}
+
+	// GNOME Terminal and other VTE-based terminals since 0.50
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	return false
+}
+
+// Hyperlink returns text as a clickable link to url. Terminals without OSC 8
+// support, or output with colors disabled, get "text (url)" instead.
+func Hyperlink(text, url string) string {
+	if !HyperlinksEnabled || !ColorEnabled {
+		if text == url || text == "" {
+			return url
+		}
+		return text + " (" + url + ")"
+	}
+	if text == "" {
+		text = url
+	}
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
\ No newline at end of file
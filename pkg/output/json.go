@@ -0,0 +1,49 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Mode selects how commands present their results
+type Mode int
+
+const (
+	// ModeText is human-readable, colored output (the default)
+	ModeText Mode = iota
+	// ModeJSON asks commands to print machine-readable JSON instead
+	ModeJSON
+	// ModeTable renders tabular command results as tables even when they
+	// also carry a text summary
+	ModeTable
+)
+
+// CurrentMode is the output mode commands should honor. Console.SetOutputMode
+// sets it for the whole session; a --json argument overrides it per command.
+var CurrentMode = ModeText
+
+// JSON prints v as indented JSON on stdout
+func JSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// WantsJSON reports whether a command should emit JSON, either because the
+// session is in JSON mode or because args contain --json. It returns args
+// with the flag removed.
+func WantsJSON(args []string) ([]string, bool) {
+	wants := CurrentMode == ModeJSON
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--json" {
+			wants = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, wants
+}
\ No newline at end of file
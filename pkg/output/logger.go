@@ -0,0 +1,159 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the level name
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// color returns the ANSI color used for the level tag
+func (l Level) color() string {
+	theme := CurrentTheme()
+	switch l {
+	case LevelDebug:
+		return theme.Primary
+	case LevelInfo:
+		return theme.Success
+	case LevelWarn:
+		return theme.Warning
+	default:
+		return theme.Error
+	}
+}
+
+// ParseLevel converts a name such as "debug" or "warn" to a Level
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level: %s", name)
+	}
+}
+
+// Logger writes leveled, color-tagged log lines. Pass a console's Stdout()
+// as the writer so log lines don't clobber the REPL input line.
+type Logger struct {
+	out        io.Writer
+	level      Level
+	timestamps bool
+	timeFormat string
+	mu         sync.Mutex
+}
+
+// NewLogger creates a logger that writes messages at or above level to w
+func NewLogger(w io.Writer, level Level) *Logger {
+	return &Logger{
+		out:        w,
+		level:      level,
+		timeFormat: "15:04:05",
+	}
+}
+
+// WithTimestamps enables timestamps using the given time layout ("" keeps the default)
+func (l *Logger) WithTimestamps(layout string) *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timestamps = true
+	if layout != "" {
+		l.timeFormat = layout
+	}
+	return l
+}
+
+// SetLevel changes the minimum level that is written
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// GetLevel returns the minimum level that is written
+func (l *Logger) GetLevel() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetOutput changes the writer log lines go to
+func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = w
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.log(LevelDebug, format, args...)
+}
+
+// Info logs an informational message
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.log(LevelInfo, format, args...)
+}
+
+// Warn logs a warning
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.log(LevelWarn, format, args...)
+}
+
+// Error logs an error
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.log(LevelError, format, args...)
+}
+
+// log formats and writes a message if level passes the filter
+func (l *Logger) log(level Level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	var line strings.Builder
+	if l.timestamps {
+		line.WriteString(time.Now().Format(l.timeFormat))
+		line.WriteString(" ")
+	}
+	line.WriteString(Colorize(fmt.Sprintf("[%-5s]", level.String()), level.color()))
+	line.WriteString(" ")
+	line.WriteString(strings.TrimRight(fmt.Sprintf(format, args...), "\n"))
+	line.WriteString("\n")
+
+	io.WriteString(l.out, line.String())
+}
\ No newline at end of file
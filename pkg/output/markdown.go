@@ -0,0 +1,170 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	numberedItemRegex = regexp.MustCompile(`^(\s*)(\d+\.)(\s*)(.*)$`)
+	boldRegex         = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicRegex       = regexp.MustCompile(`(?:\*\*)?\*([^*]+)\*(?:\*\*)?`)
+	inlineCodeRegex   = regexp.MustCompile("`([^`]+)`")
+)
+
+// MarkdownRenderer converts markdown to colored terminal text with
+// box-drawn headers and code blocks. It is stateful across lines so fenced
+// code blocks can be rendered incrementally with RenderLine.
+type MarkdownRenderer struct {
+	// WrapWidth is the width long lines are wrapped to (0 disables wrapping)
+	WrapWidth int
+
+	// Preprocess, if set, is applied to inline text before formatting.
+	// Use it for content-specific cleanup that plain markdown shouldn't get.
+	Preprocess func(string) string
+
+	inCodeBlock bool
+}
+
+// NewMarkdownRenderer creates a renderer that wraps lines at the terminal width
+func NewMarkdownRenderer() *MarkdownRenderer {
+	return &MarkdownRenderer{WrapWidth: TerminalWidth()}
+}
+
+// RenderMarkdown renders a complete markdown document for the terminal
+func RenderMarkdown(md string) string {
+	return NewMarkdownRenderer().Render(md)
+}
+
+// Render renders a complete markdown document
+func (r *MarkdownRenderer) Render(md string) string {
+	var result strings.Builder
+	for _, line := range strings.Split(md, "\n") {
+		rendered := r.RenderLine(line)
+		result.WriteString(rendered)
+		if !strings.HasSuffix(rendered, "\n") {
+			result.WriteString("\n")
+		}
+	}
+	return result.String()
+}
+
+// RenderLine renders a single line, tracking code block state between calls
+func (r *MarkdownRenderer) RenderLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+
+	// Handle code blocks
+	if strings.HasPrefix(trimmed, "```") {
+		r.inCodeBlock = !r.inCodeBlock
+		if r.inCodeBlock {
+			return "\n" + Colorize("╭─ Code Block ──────────────────────────────────────────────────╮", CurrentTheme().Primary)
+		}
+		return Colorize("╰──────────────────────────────────────────────────────────────╯", CurrentTheme().Primary)
+	}
+
+	if r.inCodeBlock {
+		return Colorize("│ "+line, CurrentTheme().Primary)
+	}
+
+	// Handle headers with box drawing
+	if strings.HasPrefix(trimmed, "## ") {
+		return r.renderHeader(strings.TrimSpace(strings.TrimPrefix(trimmed, "## ")), "╭─", "─╮", "│", "╰─", "─╯", "─")
+	}
+
+	if strings.HasPrefix(trimmed, "# ") {
+		return r.renderHeader(strings.TrimSpace(strings.TrimPrefix(trimmed, "# ")), "╔═", "═╗", "║", "╚═", "═╝", "═")
+	}
+
+	// Handle bullet points
+	if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+		text := strings.TrimSpace(trimmed[2:])
+		return "  " + Colorize("▸", CurrentTheme().Accent) + " " + r.RenderInline(text)
+	}
+
+	// Handle numbered lists
+	if matches := numberedItemRegex.FindStringSubmatch(line); len(matches) == 5 && matches[3] != "" {
+		indent, number, space, text := matches[1], matches[2], matches[3], matches[4]
+		return indent + Colorize(number, CurrentTheme().Success) + space + " " + r.RenderInline(text)
+	}
+
+	// Handle long lines by wrapping them
+	if r.WrapWidth > 0 && len(line) > r.WrapWidth {
+		return r.wrapLine(line)
+	}
+
+	// Regular line with inline formatting
+	return r.RenderInline(line)
+}
+
+// renderHeader draws a header inside a box using the given border pieces
+func (r *MarkdownRenderer) renderHeader(text, topLeft, topRight, side, bottomLeft, bottomRight, fill string) string {
+	length := len(text)
+	if length > 60 {
+		length = 60
+	}
+	border := strings.Repeat(fill, length+4)
+
+	return "\n" + Colorize(topLeft+border+topRight, CurrentTheme().Primary) + "\n" +
+		Colorize(side+" ", CurrentTheme().Primary) + Colorize(text, BoldColor) + Colorize(" "+side, CurrentTheme().Primary) + "\n" +
+		Colorize(bottomLeft+border+bottomRight, CurrentTheme().Primary)
+}
+
+// RenderInline formats **bold**, *italic* and `code` spans
+func (r *MarkdownRenderer) RenderInline(text string) string {
+	if r.Preprocess != nil {
+		text = r.Preprocess(text)
+	}
+
+	text = boldRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return Colorize(strings.Trim(match, "*"), BoldColor)
+	})
+
+	// Italic text *text*, avoiding conflicts with bold
+	text = italicRegex.ReplaceAllStringFunc(text, func(match string) string {
+		if strings.Contains(match, "**") {
+			return match
+		}
+		return Colorize(strings.Trim(match, "*"), CurrentTheme().Primary)
+	})
+
+	text = inlineCodeRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return Colorize(strings.Trim(match, "`"), CurrentTheme().Accent)
+	})
+
+	return text
+}
+
+// wrapLine wraps a long line at WrapWidth, formatting each wrapped segment
+func (r *MarkdownRenderer) wrapLine(line string) string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return line
+	}
+
+	var result strings.Builder
+	var currentLine strings.Builder
+	currentLength := 0
+
+	for _, word := range words {
+		// If adding this word would exceed the width, start a new line
+		if currentLength > 0 && currentLength+len(word)+1 > r.WrapWidth {
+			result.WriteString(r.RenderInline(currentLine.String()))
+			result.WriteString("\n")
+			currentLine.Reset()
+			currentLength = 0
+		}
+
+		if currentLength > 0 {
+			currentLine.WriteString(" ")
+			currentLength++
+		}
+		currentLine.WriteString(word)
+		currentLength += len(word)
+	}
+
+	if currentLength > 0 {
+		result.WriteString(r.RenderInline(currentLine.String()))
+	}
+
+	return result.String()
+}
\ No newline at end of file
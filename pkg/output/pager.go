@@ -0,0 +1,65 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// PagerEnabled makes Page send long output through a pager. It is off by
+// default; Console.WithPager turns it on.
+var PagerEnabled = false
+
+// DefaultPager is run when $PAGER is unset. -R keeps colors.
+const DefaultPager = "less -R"
+
+// NeedsPaging reports whether Page would use the pager for content: paging
+// is enabled, stdout is a terminal and content is taller than the window
+func NeedsPaging(content string) bool {
+	if !PagerEnabled || !IsTerminal(os.Stdout) {
+		return false
+	}
+
+	width, rows := TerminalWidth(), 0
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		// Long lines wrap onto several rows
+		rows++
+		if n := visibleLen(line); n > width {
+			rows += (n - 1) / width
+		}
+	}
+	return rows >= TerminalHeight()
+}
+
+// Page shows content through $PAGER (default "less -R") when NeedsPaging
+// says it won't fit on screen, and prints it directly otherwise. If the
+// pager can't be started the content is printed instead.
+func Page(content string) error {
+	if !NeedsPaging(content) {
+		fmt.Print(content)
+		return nil
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = strings.Fields(DefaultPager)
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		// Keep colors and leave the text on screen after quitting
+		cmd.Env = append(os.Environ(), "LESS=RX")
+	}
+
+	if err := cmd.Run(); err != nil {
+		if _, isExit := err.(*exec.ExitError); isExit {
+			return fmt.Errorf("pager failed: %w", err)
+		}
+		fmt.Print(content)
+	}
+	return nil
+}
\ No newline at end of file
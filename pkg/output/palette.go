@@ -0,0 +1,147 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ColorLevel is how many colors the terminal can display
+type ColorLevel int
+
+const (
+	// ColorLevelBasic is the 16 standard ANSI colors
+	ColorLevelBasic ColorLevel = iota
+	// ColorLevel256 is the xterm 256-color palette
+	ColorLevel256
+	// ColorLevelTrueColor is 24-bit RGB
+	ColorLevelTrueColor
+)
+
+// ColorDepth is the color level RGB and Color256 render for. It is detected
+// from $COLORTERM and $TERM at startup and may be overridden.
+var ColorDepth = DetectColorLevel()
+
+// DetectColorLevel reports the terminal's color support from the environment
+func DetectColorLevel() ColorLevel {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorLevelTrueColor
+	}
+	if strings.Contains(strings.ToLower(os.Getenv("TERM")), "256color") {
+		return ColorLevel256
+	}
+	return ColorLevelBasic
+}
+
+// basicPalette holds the xterm RGB values of the 16 standard colors
+var basicPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// RGB returns the escape sequence for a 24-bit foreground color, downgraded
+// to the nearest 256-color or basic color when the terminal lacks support
+func RGB(r, g, b uint8) string {
+	switch ColorDepth {
+	case ColorLevelTrueColor:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
+	case ColorLevel256:
+		return fmt.Sprintf("\033[38;5;%dm", nearest256(r, g, b))
+	}
+	return basicColor(nearestBasic(r, g, b))
+}
+
+// Color256 returns the escape sequence for a color of the xterm 256-color
+// palette, downgraded to the nearest basic color when needed
+func Color256(n uint8) string {
+	if ColorDepth >= ColorLevel256 {
+		return fmt.Sprintf("\033[38;5;%dm", n)
+	}
+	if n < 16 {
+		return basicColor(int(n))
+	}
+	r, g, b := paletteRGB(n)
+	return basicColor(nearestBasic(r, g, b))
+}
+
+// basicColor returns the escape sequence of one of the 16 standard colors
+func basicColor(n int) string {
+	if n < 8 {
+		return fmt.Sprintf("\033[%dm", 30+n)
+	}
+	return fmt.Sprintf("\033[%dm", 90+n-8)
+}
+
+// paletteRGB returns the RGB value of a 256-color palette entry
+func paletteRGB(n uint8) (uint8, uint8, uint8) {
+	switch {
+	case n < 16:
+		c := basicPalette[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	gray := 8 + 10*(n-232)
+	return gray, gray, gray
+}
+
+// nearest256 picks the closest color cube or grayscale entry
+func nearest256(r, g, b uint8) uint8 {
+	cube := func(v uint8) uint8 {
+		best := uint8(0)
+		for n, level := range cubeLevels {
+			if absDiff(v, level) < absDiff(v, cubeLevels[best]) {
+				best = uint8(n)
+			}
+		}
+		return best
+	}
+	cubeIndex := 16 + 36*cube(r) + 6*cube(g) + cube(b)
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayStep := (avg - 3) / 10
+	if grayStep < 0 {
+		grayStep = 0
+	} else if grayStep > 23 {
+		grayStep = 23
+	}
+	grayIndex := uint8(232 + grayStep)
+
+	if distance(r, g, b, grayIndex) < distance(r, g, b, cubeIndex) {
+		return grayIndex
+	}
+	return cubeIndex
+}
+
+// nearestBasic picks the closest of the 16 standard colors
+func nearestBasic(r, g, b uint8) int {
+	best := 0
+	for n := 1; n < 16; n++ {
+		if distance(r, g, b, uint8(n)) < distance(r, g, b, uint8(best)) {
+			best = n
+		}
+	}
+	return best
+}
+
+// distance is the squared RGB distance to a palette entry
+func distance(r, g, b, n uint8) int {
+	pr, pg, pb := paletteRGB(n)
+	dr, dg, db := int(absDiff(r, pr)), int(absDiff(g, pg)), int(absDiff(b, pb))
+	return dr*dr + dg*dg + db*db
+}
+
+// absDiff returns |a-b|
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
\ No newline at end of file
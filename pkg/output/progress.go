@@ -2,14 +2,28 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"os"
 	"sync/atomic"
 	"time"
 )
 
+// Spinner frame presets for ProgressSpinner.WithFrames
+var (
+	SpinnerLine   = []string{"|", "/", "-", "\\"}
+	SpinnerDots   = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerCircle = []string{"◐", "◓", "◑", "◒"}
+	SpinnerArrows = []string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}
+)
+
 // ProgressSpinner displays a spinning progress indicator
 // Extracted and adapted from firescan's progress display logic
 type ProgressSpinner struct {
 	message    string
+	frames     []string
+	color      string // empty uses the theme's primary color
+	out        io.Writer // nil writes to os.Stdout
 	isRunning  *int32
 	done       chan bool
 }
@@ -19,11 +33,41 @@ func NewSpinner(message string) *ProgressSpinner {
 	var running int32
 	return &ProgressSpinner{
 		message:   message,
+		frames:    SpinnerLine,
 		isRunning: &running,
 		done:      make(chan bool),
 	}
 }
 
+// WithFrames sets the animation frames, e.g. SpinnerDots. Call before Start.
+func (p *ProgressSpinner) WithFrames(frames []string) *ProgressSpinner {
+	if len(frames) > 0 {
+		p.frames = frames
+	}
+	return p
+}
+
+// WithColor sets the color of the spinner frames. Call before Start.
+func (p *ProgressSpinner) WithColor(color string) *ProgressSpinner {
+	p.color = color
+	return p
+}
+
+// WithWriter sends the spinner to w, such as a console's Stdout() so it
+// doesn't clobber the prompt. Call before Start.
+func (p *ProgressSpinner) WithWriter(w io.Writer) *ProgressSpinner {
+	p.out = w
+	return p
+}
+
+// writer returns where the spinner draws
+func (p *ProgressSpinner) writer() io.Writer {
+	if p.out == nil {
+		return os.Stdout
+	}
+	return p.out
+}
+
 // Start begins the spinner animation
 func (p *ProgressSpinner) Start() {
 	atomic.StoreInt32(p.isRunning, 1)
@@ -36,7 +80,7 @@ func (p *ProgressSpinner) Stop() {
 		atomic.StoreInt32(p.isRunning, 0)
 		p.done <- true
 		// Clear the line
-		fmt.Printf("\r%80s\r", "")
+		fmt.Fprintf(p.writer(), "\r%*s\r", TerminalWidth()-1, "")
 	}
 }
 
@@ -47,7 +91,10 @@ func (p *ProgressSpinner) UpdateMessage(message string) {
 
 // spin runs the spinner animation
 func (p *ProgressSpinner) spin() {
-	spinners := []rune{'|', '/', '-', '\\'}
+	color := p.color
+	if color == "" {
+		color = CurrentTheme().Primary
+	}
 	i := 0
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
@@ -58,7 +105,7 @@ func (p *ProgressSpinner) spin() {
 			return
 		case <-ticker.C:
 			if atomic.LoadInt32(p.isRunning) == 1 {
-				fmt.Printf("\r[%s%c%s] %s", CyanColor, spinners[i%len(spinners)], Reset, p.message)
+				fmt.Fprintf(p.writer(), "\r[%s] %s", Colorize(p.frames[i%len(p.frames)], color), p.message)
 				i++
 			}
 		}
@@ -71,6 +118,7 @@ type ProgressCounter struct {
 	current     *int64
 	total       int64
 	found       *int32
+	rate        uint64 // smoothed items/sec, as float64 bits
 	isRunning   *int32
 	done        chan bool
 }
@@ -103,7 +151,7 @@ func (p *ProgressCounter) Stop() {
 		atomic.StoreInt32(p.isRunning, 0)
 		p.done <- true
 		// Clear the line
-		fmt.Printf("\r%80s\r", "")
+		fmt.Printf("\r%*s\r", TerminalWidth()-1, "")
 	}
 }
 
@@ -127,29 +175,112 @@ func (p *ProgressCounter) GetFound() int32 {
 	return atomic.LoadInt32(p.found)
 }
 
+// GetRate returns the smoothed throughput in items per second. It is
+// updated while the counter is running.
+func (p *ProgressCounter) GetRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&p.rate))
+}
+
 // display runs the progress display
 func (p *ProgressCounter) display() {
-	spinners := []rune{'|', '/', '-', '\\'}
 	i := 0
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
+	sampler := NewRateSampler(10)
+	lastCount := atomic.LoadInt64(p.current)
+	lastTick := time.Now()
+
 	for {
 		select {
 		case <-p.done:
 			return
-		case <-ticker.C:
+		case now := <-ticker.C:
 			if atomic.LoadInt32(p.isRunning) == 1 {
 				currentChecked := atomic.LoadInt64(p.current)
 				currentFound := atomic.LoadInt32(p.found)
-				fmt.Printf("\r[%s%c%s] %s [Checked: %d/%d | Found: %d]", 
-					CyanColor, spinners[i%len(spinners)], Reset, p.message, currentChecked, p.total, currentFound)
+
+				// Throughput over the last tick, smoothed across recent ticks
+				if elapsed := now.Sub(lastTick).Seconds(); elapsed > 0 {
+					rate := sampler.Add(float64(currentChecked-lastCount) / elapsed)
+					atomic.StoreUint64(&p.rate, math.Float64bits(rate))
+				}
+				lastCount, lastTick = currentChecked, now
+
+				fmt.Printf("\r[%s] %s [Checked: %d/%d | Found: %d | %s]", 
+					Colorize(SpinnerLine[i%len(SpinnerLine)], CurrentTheme().Primary), p.message, currentChecked, p.total, currentFound,
+					p.rateStatus(currentChecked))
 				i++
 			}
 		}
 	}
 }
 
+// rateStatus formats the throughput and the estimated time remaining
+func (p *ProgressCounter) rateStatus(current int64) string {
+	rate := p.GetRate()
+	if rate <= 0 || p.total <= 0 {
+		return fmt.Sprintf("%.1f/s", rate)
+	}
+	remaining := float64(p.total - current)
+	if remaining < 0 {
+		remaining = 0
+	}
+	eta := time.Duration(remaining / rate * float64(time.Second))
+	return fmt.Sprintf("%.1f/s | ETA: %s", rate, FormatETA(eta))
+}
+
+// RateSampler smooths a throughput measurement by averaging its most
+// recent samples
+type RateSampler struct {
+	samples []float64
+	max     int
+}
+
+// NewRateSampler creates a sampler averaging over the last max samples
+func NewRateSampler(max int) *RateSampler {
+	if max < 1 {
+		max = 1
+	}
+	return &RateSampler{max: max}
+}
+
+// Add records a sample and returns the new average
+func (r *RateSampler) Add(rate float64) float64 {
+	r.samples = append(r.samples, rate)
+	if len(r.samples) > r.max {
+		r.samples = r.samples[1:]
+	}
+	return r.Average()
+}
+
+// Average returns the mean of the recorded samples, or 0 without any
+func (r *RateSampler) Average() float64 {
+	if len(r.samples) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, s := range r.samples {
+		sum += s
+	}
+	return sum / float64(len(r.samples))
+}
+
+// Reset discards all samples
+func (r *RateSampler) Reset() {
+	r.samples = r.samples[:0]
+}
+
+// FormatETA formats a remaining duration compactly, e.g. "42s", "3.5m", "1.2h"
+func FormatETA(eta time.Duration) string {
+	if eta > time.Hour {
+		return fmt.Sprintf("%.1fh", eta.Hours())
+	} else if eta > time.Minute {
+		return fmt.Sprintf("%.1fm", eta.Minutes())
+	}
+	return fmt.Sprintf("%.0fs", eta.Seconds())
+}
+
 // SimpleProgress displays a simple percentage progress bar
 func SimpleProgress(current, total int64, message string) {
 	if total == 0 {
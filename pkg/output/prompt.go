@@ -0,0 +1,172 @@
+package output
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// LineReader reads one line of input after displaying prompt
+type LineReader func(prompt string) (string, error)
+
+var (
+	inputMu      sync.RWMutex
+	inputReader  LineReader
+	secretReader LineReader
+)
+
+// SetInputReader installs the reader used by Confirm. The console sets one
+// backed by its readline instance while the REPL runs so prompts don't fight
+// the command line; pass nil to restore the default.
+func SetInputReader(reader LineReader) {
+	inputMu.Lock()
+	defer inputMu.Unlock()
+	inputReader = reader
+}
+
+// SetSecretReader installs the reader used by ReadSecret, which must not
+// echo what is typed. Pass nil to restore the default.
+func SetSecretReader(reader LineReader) {
+	inputMu.Lock()
+	defer inputMu.Unlock()
+	secretReader = reader
+}
+
+// ReadSecret prompts for a value such as a password or token without
+// echoing it. Ctrl-C aborts the prompt with an error and leaves the
+// terminal as it was. Without an interactive terminal an error is returned.
+func ReadSecret(prompt string) (string, error) {
+	inputMu.RLock()
+	reader := secretReader
+	inputMu.RUnlock()
+
+	if reader != nil {
+		return reader(prompt)
+	}
+	if !IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("cannot read secret: no interactive terminal")
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.GetState(fd)
+	if err != nil {
+		return "", fmt.Errorf("cannot read secret: %w", err)
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	type result struct {
+		secret []byte
+		err    error
+	}
+	done := make(chan result, 1)
+
+	fmt.Print(prompt)
+	go func() {
+		secret, err := term.ReadPassword(fd)
+		done <- result{secret, err}
+	}()
+
+	select {
+	case r := <-done:
+		fmt.Println()
+		if r.err != nil {
+			return "", fmt.Errorf("failed to read secret: %w", r.err)
+		}
+		return string(r.secret), nil
+	case <-interrupts:
+		// The pending read can't be cancelled; restore echo so the
+		// terminal is usable whatever happens to it
+		term.Restore(fd, state)
+		fmt.Println()
+		return "", fmt.Errorf("interrupted")
+	}
+}
+
+// ErrNotInteractive is returned by prompts that need an answer when there
+// is no interactive user to give one
+var ErrNotInteractive = errors.New("no interactive terminal")
+
+// readLine reads an answer through the installed reader, falling back to
+// stdin. ok is false when there is no interactive user to ask.
+func readLine(prompt string) (line string, ok bool, err error) {
+	inputMu.RLock()
+	reader := inputReader
+	inputMu.RUnlock()
+
+	if reader != nil {
+		line, err = reader(prompt)
+		return line, true, err
+	}
+	if !IsTerminal(os.Stdin) {
+		return "", false, nil
+	}
+
+	fmt.Print(prompt)
+	line, err = bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", true, err
+	}
+	return strings.TrimRight(line, "\r\n"), true, nil
+}
+
+// Confirm asks a yes/no question that defaults to no. See ConfirmDefault.
+func Confirm(prompt string) (bool, error) {
+	return ConfirmDefault(prompt, false)
+}
+
+// ConfirmDefault asks a yes/no question, accepting y/yes/n/no in any case.
+// An empty answer picks def, as does running without an interactive
+// terminal. Other answers ask again.
+func ConfirmDefault(prompt string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	question := fmt.Sprintf("%s %s ", strings.TrimSpace(prompt), hint)
+
+	for {
+		answer, interactive, err := readLine(question)
+		if !interactive {
+			return def, nil
+		}
+		if err != nil {
+			return def, fmt.Errorf("failed to read answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Println(Colorize("Please answer yes or no.", CurrentTheme().Warning))
+	}
+}
+
+// ConfirmTyped asks the user to type token exactly before a destructive
+// action. Surrounding spaces are ignored but case is not. Unlike Confirm
+// there is no safe default, so ErrNotInteractive is returned when there is
+// no interactive terminal to ask.
+func ConfirmTyped(prompt, token string) (bool, error) {
+	question := fmt.Sprintf("%s\nType '%s' to continue: ", strings.TrimSpace(prompt), token)
+
+	answer, interactive, err := readLine(question)
+	if !interactive {
+		return false, ErrNotInteractive
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(answer) == token, nil
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package output
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize calls onResize whenever the terminal window changes size
+func watchResize(onResize func()) bool {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	go func() {
+		for range signals {
+			onResize()
+		}
+	}()
+	return true
+}
\ No newline at end of file
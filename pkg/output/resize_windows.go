@@ -0,0 +1,8 @@
+//go:build windows
+
+package output
+
+// watchResize is unavailable on Windows, so the width is measured on every call
+func watchResize(onResize func()) bool {
+	return false
+}
\ No newline at end of file
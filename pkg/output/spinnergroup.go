@@ -0,0 +1,174 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SpinnerGroup renders several labeled spinners on consecutive lines so
+// parallel tasks don't fight over a single terminal line. A single goroutine
+// redraws the block by moving the cursor back to its first line each frame.
+// When stdout isn't a terminal, updates are printed as plain sequential lines.
+type SpinnerGroup struct {
+	out      io.Writer
+	tty      bool
+	spinners []*GroupSpinner
+	rendered int // lines drawn by the last frame
+	frame    int
+	running  bool
+	done     chan struct{}
+	stopped  chan struct{}
+	mu       sync.Mutex
+}
+
+// GroupSpinner is a handle to one spinner in a SpinnerGroup
+type GroupSpinner struct {
+	group   *SpinnerGroup
+	label   string
+	message string
+	state   spinnerState
+}
+
+// spinnerState tracks whether a group spinner is still running
+type spinnerState int
+
+const (
+	spinnerRunning spinnerState = iota
+	spinnerDone
+	spinnerFailed
+)
+
+// NewSpinnerGroup creates a spinner group writing to stdout
+func NewSpinnerGroup() *SpinnerGroup {
+	return &SpinnerGroup{
+		out: os.Stdout,
+		tty: IsTerminal(os.Stdout),
+	}
+}
+
+// Add registers a new spinner and starts rendering if needed
+func (g *SpinnerGroup) Add(label string) *GroupSpinner {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := &GroupSpinner{group: g, label: label}
+	g.spinners = append(g.spinners, s)
+
+	if !g.tty {
+		fmt.Fprintf(g.out, "[*] %s: started\n", label)
+		return s
+	}
+
+	if !g.running {
+		g.running = true
+		g.done = make(chan struct{})
+		g.stopped = make(chan struct{})
+		go g.render()
+	}
+	return s
+}
+
+// Stop draws the final state of every spinner and stops rendering
+func (g *SpinnerGroup) Stop() {
+	g.mu.Lock()
+	if !g.running {
+		g.mu.Unlock()
+		return
+	}
+	g.running = false
+	close(g.done)
+	g.mu.Unlock()
+
+	<-g.stopped
+}
+
+// Update changes the spinner's status message
+func (s *GroupSpinner) Update(message string) {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+
+	s.message = message
+	if !s.group.tty {
+		fmt.Fprintf(s.group.out, "[*] %s: %s\n", s.label, message)
+	}
+}
+
+// Done marks the spinner as successfully finished
+func (s *GroupSpinner) Done(message string) {
+	s.finish(spinnerDone, message)
+}
+
+// Fail marks the spinner as finished with an error
+func (s *GroupSpinner) Fail(message string) {
+	s.finish(spinnerFailed, message)
+}
+
+// finish records the final state of the spinner
+func (s *GroupSpinner) finish(state spinnerState, message string) {
+	s.group.mu.Lock()
+	defer s.group.mu.Unlock()
+
+	if s.state != spinnerRunning {
+		return
+	}
+	s.state = state
+	s.message = message
+	if !s.group.tty {
+		fmt.Fprintln(s.group.out, s.line(0))
+	}
+}
+
+// line formats the spinner for the given animation frame
+func (s *GroupSpinner) line(frame int) string {
+	text := s.label
+	if s.message != "" {
+		text += ": " + s.message
+	}
+
+	switch s.state {
+	case spinnerDone:
+		return fmt.Sprintf("[%s] %s", Colorize("✓", CurrentTheme().Success), text)
+	case spinnerFailed:
+		return fmt.Sprintf("[%s] %s", Colorize("✗", CurrentTheme().Error), text)
+	default:
+		return fmt.Sprintf("[%s] %s", Colorize(SpinnerLine[frame%len(SpinnerLine)], CurrentTheme().Primary), text)
+	}
+}
+
+// render redraws the spinner block until the group is stopped
+func (g *SpinnerGroup) render() {
+	defer close(g.stopped)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.done:
+			g.mu.Lock()
+			g.draw()
+			g.mu.Unlock()
+			return
+		case <-ticker.C:
+			g.mu.Lock()
+			g.draw()
+			g.frame++
+			g.mu.Unlock()
+		}
+	}
+}
+
+// draw writes one frame; the caller must hold the lock
+func (g *SpinnerGroup) draw() {
+	// Return to the first line of the previous frame
+	if g.rendered > 0 {
+		fmt.Fprintf(g.out, "\033[%dA", g.rendered)
+	}
+	for _, s := range g.spinners {
+		fmt.Fprintf(g.out, "\r\033[K%s\n", s.line(g.frame))
+	}
+	g.rendered = len(g.spinners)
+}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table renders rows as aligned columns inside a box-drawn border.
+// Cells may contain color codes; widths are based on visible text.
+type Table struct {
+	headers []string
+	rows    [][]string
+	align   map[int]bool // columns that are right aligned
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{
+		headers: headers,
+		align:   make(map[int]bool),
+	}
+}
+
+// AddRow appends a row. Missing cells are left blank and extra cells dropped.
+func (t *Table) AddRow(cells ...string) *Table {
+	row := make([]string, len(t.headers))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+	return t
+}
+
+// AlignRight right-aligns the given columns, useful for numbers
+func (t *Table) AlignRight(columns ...int) *Table {
+	for _, col := range columns {
+		t.align[col] = true
+	}
+	return t
+}
+
+// Render returns the table as a string
+func (t *Table) Render() string {
+	widths := make([]int, len(t.headers))
+	for col, header := range t.headers {
+		widths[col] = visibleLen(header)
+	}
+	for _, row := range t.rows {
+		for col, cell := range row {
+			if w := visibleLen(cell); w > widths[col] {
+				widths[col] = w
+			}
+		}
+	}
+
+	// Shrink the widest columns until the table fits the terminal
+	fitWidths(widths, TerminalWidth())
+	fit := func(cell string, col int) string {
+		if visibleLen(cell) > widths[col] {
+			return truncateVisible(cell, widths[col])
+		}
+		return cell
+	}
+
+	border := func(left, mid, right string) string {
+		parts := make([]string, len(widths))
+		for col, w := range widths {
+			parts[col] = strings.Repeat("─", w+2)
+		}
+		return Colorize(left+strings.Join(parts, mid)+right, CurrentTheme().Primary) + "\n"
+	}
+	line := func(cells []string, bold bool) string {
+		var b strings.Builder
+		b.WriteString(Colorize("│", CurrentTheme().Primary))
+		for col, cell := range cells {
+			cell = fit(cell, col)
+			padding := strings.Repeat(" ", widths[col]-visibleLen(cell))
+			if bold {
+				cell = Colorize(cell, BoldColor)
+			}
+			if t.align[col] {
+				cell = padding + cell
+			} else {
+				cell += padding
+			}
+			b.WriteString(" " + cell + " " + Colorize("│", CurrentTheme().Primary))
+		}
+		return b.String() + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(border("╭", "┬", "╮"))
+	b.WriteString(line(t.headers, true))
+	b.WriteString(border("├", "┼", "┤"))
+	for _, row := range t.rows {
+		b.WriteString(line(row, false))
+	}
+	b.WriteString(border("╰", "┴", "╯"))
+	return b.String()
+}
+
+// Print writes the table to stdout
+func (t *Table) Print() {
+	fmt.Print(t.Render())
+}
+
+// visibleLen returns the display length of text, ignoring color codes
+func visibleLen(text string) int {
+	return utf8.RuneCountInString(StripANSI(text))
+}
+
+// minColumnWidth is the narrowest a column is shrunk to when fitting
+const minColumnWidth = 3
+
+// fitWidths narrows the widest columns so the rendered table, including
+// borders and padding, is no wider than maxWidth
+func fitWidths(widths []int, maxWidth int) {
+	total := 1
+	for _, w := range widths {
+		total += w + 3
+	}
+	for total > maxWidth {
+		widest := 0
+		for col, w := range widths {
+			if w > widths[widest] {
+				widest = col
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncateVisible shortens text to width visible characters, ending with an
+// ellipsis. Color codes are dropped from truncated text.
+func truncateVisible(text string, width int) string {
+	runes := []rune(StripANSI(text))
+	if len(runes) <= width {
+		return string(runes)
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
\ No newline at end of file
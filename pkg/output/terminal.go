@@ -0,0 +1,72 @@
+package output
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// Defaults used when the terminal size can't be determined
+const (
+	DefaultTerminalWidth  = 80
+	DefaultTerminalHeight = 24
+)
+
+var (
+	resizeOnce    sync.Once
+	resizeWatched bool
+	cachedWidth   atomic.Int64 // 0 until measured, reset on resize
+)
+
+// IsTerminal reports whether f is connected to a terminal rather than a pipe or file
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalWidth returns the width of the terminal on stdout. When stdout is
+// not a terminal $COLUMNS is used, then DefaultTerminalWidth. Where SIGWINCH
+// is available the width is cached and re-measured after a resize.
+func TerminalWidth() int {
+	resizeOnce.Do(func() {
+		resizeWatched = watchResize(func() { cachedWidth.Store(0) })
+	})
+	if width := cachedWidth.Load(); width > 0 {
+		return int(width)
+	}
+
+	width := measureWidth()
+	if resizeWatched {
+		cachedWidth.Store(int64(width))
+	}
+	return width
+}
+
+// measureWidth queries the terminal size
+func measureWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DefaultTerminalWidth
+}
+
+// TerminalHeight returns the number of rows of the terminal on stdout,
+// falling back to $LINES and then DefaultTerminalHeight
+func TerminalHeight() int {
+	if _, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil && height > 0 {
+		return height
+	}
+	if height, err := strconv.Atoi(os.Getenv("LINES")); err == nil && height > 0 {
+		return height
+	}
+	return DefaultTerminalHeight
+}
\ No newline at end of file
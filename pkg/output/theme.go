@@ -0,0 +1,155 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Theme is the palette the banner, table, progress, markdown and Intel
+// styling helpers draw from. Each field holds an ANSI escape sequence.
+type Theme struct {
+	Name    string
+	Primary string // headers, borders, spinners
+	Accent  string // bullets, inline code, highlights
+	Success string
+	Warning string
+	Error   string
+	Muted   string // secondary text
+}
+
+// DefaultTheme suits dark terminal backgrounds
+var DefaultTheme = Theme{
+	Name:    "default",
+	Primary: CyanColor,
+	Accent:  YellowColor,
+	Success: GreenColor,
+	Warning: YellowColor,
+	Error:   RedColor,
+	Muted:   DimColor,
+}
+
+// LightTheme avoids colors that are hard to read on light backgrounds
+var LightTheme = Theme{
+	Name:    "light",
+	Primary: "\033[34m", // blue
+	Accent:  "\033[35m", // magenta
+	Success: GreenColor,
+	Warning: "\033[35m",
+	Error:   RedColor,
+	Muted:   "\033[90m", // dark gray
+}
+
+var (
+	themeMu     sync.RWMutex
+	activeTheme = DefaultTheme
+)
+
+// SetTheme makes t the active theme. Empty fields keep the default color.
+func SetTheme(t Theme) {
+	defaults := DefaultTheme
+	fill := func(field *string, def string) {
+		if *field == "" {
+			*field = def
+		}
+	}
+	fill(&t.Primary, defaults.Primary)
+	fill(&t.Accent, defaults.Accent)
+	fill(&t.Success, defaults.Success)
+	fill(&t.Warning, defaults.Warning)
+	fill(&t.Error, defaults.Error)
+	fill(&t.Muted, defaults.Muted)
+
+	themeMu.Lock()
+	activeTheme = t
+	themeMu.Unlock()
+}
+
+// CurrentTheme returns the active theme
+func CurrentTheme() Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return activeTheme
+}
+
+// ThemeByName returns a built-in theme ("default" or "light")
+func ThemeByName(name string) (Theme, bool) {
+	switch strings.ToLower(name) {
+	case "default", "dark", "":
+		return DefaultTheme, true
+	case "light":
+		return LightTheme, true
+	}
+	return Theme{}, false
+}
+
+// ThemeFromSpec builds a theme from a map such as a config section. The
+// "base" key picks the built-in theme to start from; the other keys
+// (primary, accent, success, warning, error, muted) accept the colors
+// understood by ParseColor. Empty values are ignored.
+func ThemeFromSpec(spec map[string]string) (Theme, error) {
+	theme, ok := ThemeByName(spec["base"])
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme: %s", spec["base"])
+	}
+
+	fields := map[string]*string{
+		"primary": &theme.Primary,
+		"accent":  &theme.Accent,
+		"success": &theme.Success,
+		"warning": &theme.Warning,
+		"error":   &theme.Error,
+		"muted":   &theme.Muted,
+	}
+	for key, value := range spec {
+		if key == "base" || key == "name" || value == "" {
+			continue
+		}
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme color: %s", key)
+		}
+		color, err := ParseColor(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %s: %w", key, err)
+		}
+		*field = color
+	}
+
+	if name := spec["name"]; name != "" {
+		theme.Name = name
+	}
+	return theme, nil
+}
+
+// namedColors maps color names to their basic palette index
+var namedColors = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"gray": 8, "grey": 8, "bright-red": 9, "bright-green": 10, "bright-yellow": 11,
+	"bright-blue": 12, "bright-magenta": 13, "bright-cyan": 14, "bright-white": 15,
+}
+
+// ParseColor converts a color name ("cyan", "bright-blue", "bold", "dim"),
+// a 256-color index ("208") or a hex value ("#ff8800") to an escape sequence
+func ParseColor(spec string) (string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "bold":
+		return BoldColor, nil
+	case "dim":
+		return DimColor, nil
+	}
+	if n, ok := namedColors[spec]; ok {
+		return basicColor(n), nil
+	}
+	if n, err := strconv.Atoi(spec); err == nil && n >= 0 && n <= 255 {
+		return Color256(uint8(n)), nil
+	}
+	if hex := strings.TrimPrefix(spec, "#"); len(hex) == 6 && hex != spec {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+		}
+	}
+	return "", fmt.Errorf("invalid color: %q", spec)
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TreeNode is a labeled node of a Tree
+type TreeNode struct {
+	Label    string
+	children []*TreeNode
+}
+
+// AddChild appends a child node and returns it so it can be nested further
+func (n *TreeNode) AddChild(label string) *TreeNode {
+	child := &TreeNode{Label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+// AddChildren appends a leaf for each label and returns n for chaining
+func (n *TreeNode) AddChildren(labels ...string) *TreeNode {
+	for _, label := range labels {
+		n.AddChild(label)
+	}
+	return n
+}
+
+// count returns the number of nodes below n
+func (n *TreeNode) count() int {
+	total := len(n.children)
+	for _, child := range n.children {
+		total += child.count()
+	}
+	return total
+}
+
+// Tree renders hierarchical data with box-drawing guides:
+//
+//	schema
+//	├── types
+//	│   └── User
+//	└── queries
+type Tree struct {
+	*TreeNode
+	maxDepth int // 0 renders every level
+}
+
+// NewTree creates a tree with the given root label
+func NewTree(root string) *Tree {
+	return &Tree{TreeNode: &TreeNode{Label: root}}
+}
+
+// SetMaxDepth collapses subtrees below depth levels into a summary line.
+// Children of the root are at depth 1; 0 shows everything.
+func (t *Tree) SetMaxDepth(depth int) *Tree {
+	t.maxDepth = depth
+	return t
+}
+
+// Render writes the tree to w
+func (t *Tree) Render(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, Colorize(t.Label, BoldColor)); err != nil {
+		return err
+	}
+	return t.renderChildren(w, t.TreeNode, "", 1)
+}
+
+// renderChildren writes the children of node, each prefixed by the guides
+// of its ancestors
+func (t *Tree) renderChildren(w io.Writer, node *TreeNode, prefix string, depth int) error {
+	muted := CurrentTheme().Muted
+	for n, child := range node.children {
+		last := n == len(node.children)-1
+		branch, guide := "├── ", "│   "
+		if last {
+			branch, guide = "└── ", "    "
+		}
+
+		if _, err := fmt.Fprintln(w, Colorize(prefix+branch, muted)+child.Label); err != nil {
+			return err
+		}
+
+		if len(child.children) == 0 {
+			continue
+		}
+		if t.maxDepth > 0 && depth >= t.maxDepth {
+			summary := fmt.Sprintf("… %d more", child.count())
+			if _, err := fmt.Fprintln(w, Colorize(prefix+guide+"└── "+summary, muted)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := t.renderChildren(w, child, prefix+guide, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns the rendered tree
+func (t *Tree) String() string {
+	var b strings.Builder
+	t.Render(&b)
+	return b.String()
+}
+
+// Print writes the tree to stdout
+func (t *Tree) Print() {
+	fmt.Print(t.String())
+}
\ No newline at end of file
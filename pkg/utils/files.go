@@ -2,18 +2,68 @@ package utils
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
-// LoadWordlist loads a wordlist from a file, returning a slice of words
-// Extracted and adapted from firescan's loadWordlist function
-func LoadWordlist(filePath string) ([]string, error) {
+// gzipMagic is the two-byte header that starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// openWordlist opens a wordlist file, transparently decompressing gzip files
+// detected by a .gz extension or the gzip magic bytes
+func openWordlist(filePath string) (io.ReadCloser, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("could not open file: %w", err)
 	}
+
+	buffered := bufio.NewReader(file)
+	header, _ := buffered.Peek(len(gzipMagic))
+	if !strings.HasSuffix(strings.ToLower(filePath), ".gz") && !bytes.Equal(header, gzipMagic) {
+		return &wordlistReader{Reader: buffered, file: file}, nil
+	}
+
+	gz, err := gzip.NewReader(buffered)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not read gzip file: %w", err)
+	}
+	return &wordlistReader{Reader: gz, gz: gz, file: file}, nil
+}
+
+// wordlistReader closes both the gzip stream (if any) and the underlying file
+type wordlistReader struct {
+	io.Reader
+	gz   *gzip.Reader
+	file *os.File
+}
+
+// Close releases the gzip reader and file
+func (r *wordlistReader) Close() error {
+	if r.gz != nil {
+		r.gz.Close()
+	}
+	return r.file.Close()
+}
+
+// LoadWordlist loads a wordlist from a file, returning a slice of words.
+// Gzip-compressed files are decompressed transparently.
+// Extracted and adapted from firescan's loadWordlist function
+func LoadWordlist(filePath string) ([]string, error) {
+	file, err := openWordlist(filePath)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
 	var words []string
@@ -32,16 +82,63 @@ func LoadWordlist(filePath string) ([]string, error) {
 	return words, nil
 }
 
-// SaveWordlist saves a wordlist to a file
+// StreamWordlist reads a wordlist one line at a time, skipping empty lines,
+// so huge lists can be processed with bounded memory. Gzip files are handled
+// as in LoadWordlist. The words channel is closed when the file is exhausted,
+// ctx is cancelled, or an error occurs; at most one error is then sent on the
+// error channel before it is closed.
+func StreamWordlist(ctx context.Context, filePath string) (<-chan string, <-chan error) {
+	words := make(chan string, 256)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(words)
+
+		file, err := openWordlist(filePath)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" { // Skip empty lines
+				continue
+			}
+
+			select {
+			case words <- line:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("error reading file: %w", err)
+		}
+	}()
+
+	return words, errs
+}
+
+// SaveWordlist saves a wordlist to a file. The file is replaced atomically.
 func SaveWordlist(filePath string, words []string) error {
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("could not create file: %w", err)
+	if err := WriteFileAtomic(filePath, joinLines(words), 0644); err != nil {
+		return fmt.Errorf("could not write file: %w", err)
 	}
-	defer file.Close()
+	return nil
+}
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
+// SaveWordlistGz saves a wordlist to a gzip-compressed file. The file is
+// replaced atomically.
+func SaveWordlistGz(filePath string, words []string) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	writer := bufio.NewWriter(gz)
 
 	for _, word := range words {
 		if _, err := writer.WriteString(word + "\n"); err != nil {
@@ -49,9 +146,65 @@ func SaveWordlist(filePath string, words []string) error {
 		}
 	}
 
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error compressing file: %w", err)
+	}
+	if err := WriteFileAtomic(filePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write file: %w", err)
+	}
 	return nil
 }
 
+// WriteFileAtomic writes data to a temporary file in the same directory and
+// renames it over path, so readers and crashes never see a partial file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Leave no temp file behind on failure
+	success := false
+	defer func() {
+		if !success {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	success = true
+	return nil
+}
+
+// joinLines returns lines as newline-terminated file content
+func joinLines(lines []string) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
 // FileExists checks if a file exists
 func FileExists(filePath string) bool {
 	_, err := os.Stat(filePath)
@@ -93,6 +246,101 @@ func GetDefaultConfigPath(appName string) (string, error) {
 	return filepath.Join(configDir, "config.yaml"), nil
 }
 
+// ExpandHome replaces a leading ~ in path with the user's home directory
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+	home, err := GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// Glob returns the sorted paths matching pattern. It extends filepath.Glob
+// with a leading ~ for the home directory and ** to match any number of
+// directories, so "wordlists/**/*.txt" finds text files at any depth.
+func Glob(pattern string) ([]string, error) {
+	pattern, err := ExpandHome(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+
+	// Walk from the deepest directory without wildcards
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	fixed := 0
+	for fixed < len(segments) && !strings.ContainsAny(segments[fixed], "*?[") {
+		fixed++
+	}
+	root := filepath.FromSlash(strings.Join(segments[:fixed], "/"))
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		}
+	}
+	for _, segment := range segments[fixed:] {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil // skip unreadable directories
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		if matchSegments(segments[fixed:], strings.Split(filepath.ToSlash(rel), "/")) {
+			if root == "." {
+				matches = append(matches, rel)
+			} else {
+				matches = append(matches, path)
+			}
+		}
+		return nil
+	})
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more whole segments
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(path); skip++ {
+			if matchSegments(pattern[1:], path[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
+
 // ReadLines reads all lines from a file
 func ReadLines(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
@@ -110,34 +358,118 @@ func ReadLines(filePath string) ([]string, error) {
 	return lines, scanner.Err()
 }
 
-// WriteLines writes lines to a file
+// WriteLines writes lines to a file, replacing it atomically
 func WriteLines(filePath string, lines []string) error {
-	file, err := os.Create(filePath)
+	return WriteFileAtomic(filePath, joinLines(lines), 0644)
+}
+
+// AppendToFile appends text to a file
+func AppendToFile(filePath, text string) error {
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
+	_, err = file.WriteString(text)
+	return err
+}
 
-	for _, line := range lines {
-		if _, err := writer.WriteString(line + "\n"); err != nil {
-			return err
-		}
-	}
+// DefaultFlushInterval is how often a SafeAppender flushes buffered lines
+const DefaultFlushInterval = time.Second
 
-	return nil
+// SafeAppender appends lines to one file from many goroutines. Each line is
+// written whole, so concurrent results never interleave. Lines are buffered
+// and flushed every flush interval and on Close.
+type SafeAppender struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	done   chan struct{}
+	eager  bool // flush after every line
+	closed bool
 }
 
-// AppendToFile appends text to a file
-func AppendToFile(filePath, text string) error {
+// NewSafeAppender opens filePath for appending, creating it if needed, and
+// flushes buffered lines every DefaultFlushInterval
+func NewSafeAppender(filePath string) (*SafeAppender, error) {
+	return NewSafeAppenderInterval(filePath, DefaultFlushInterval)
+}
+
+// NewSafeAppenderInterval is NewSafeAppender with a custom flush interval;
+// 0 or less flushes after every line
+func NewSafeAppenderInterval(filePath string, interval time.Duration) (*SafeAppender, error) {
 	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
+		return nil, err
+	}
+
+	a := &SafeAppender{file: file, writer: bufio.NewWriter(file), done: make(chan struct{})}
+	if interval > 0 {
+		go a.flushEvery(interval)
+	} else {
+		a.eager = true
+	}
+	return a, nil
+}
+
+// flushEvery flushes on a timer until Close
+func (a *SafeAppender) flushEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+			a.Flush()
+		}
+	}
+}
+
+// Append writes line followed by a newline, unless it already ends in one
+func (a *SafeAppender) Append(line string) error {
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return fmt.Errorf("append to closed file: %s", a.file.Name())
+	}
+	if _, err := a.writer.WriteString(line); err != nil {
 		return err
 	}
-	defer file.Close()
+	if a.eager {
+		return a.writer.Flush()
+	}
+	return nil
+}
 
-	_, err = file.WriteString(text)
+// Flush writes buffered lines to the file
+func (a *SafeAppender) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	return a.writer.Flush()
+}
+
+// Close flushes remaining lines and closes the file. Further appends fail.
+func (a *SafeAppender) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+	close(a.done)
+
+	err := a.writer.Flush()
+	if closeErr := a.file.Close(); err == nil {
+		err = closeErr
+	}
 	return err
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"strings"
+	"unicode"
 )
 
 // MaskString hides the middle of a string for secure display
@@ -20,18 +21,116 @@ func GenerateCaseVariations(word string) []string {
 		return []string{}
 	}
 	
-	variationsSet := make(map[string]bool)
-	variationsSet[strings.ToLower(word)] = true
-	variationsSet[strings.ToUpper(string(word[0]))+strings.ToLower(word[1:])] = true
-	variationsSet[strings.ToUpper(word)] = true
-	
-	result := make([]string, 0, len(variationsSet))
-	for v := range variationsSet {
-		result = append(result, v)
+	// Keep the documented order so wordlists built from it are repeatable
+	return dedupe([]string{
+		strings.ToLower(word),
+		strings.ToUpper(string(word[0])) + strings.ToLower(word[1:]),
+		strings.ToUpper(word),
+	})
+}
+
+// CaseStyle selects how GenerateCaseVariationsExt joins the words of its input
+type CaseStyle int
+
+const (
+	CaseLower          CaseStyle = iota // userid
+	CaseUpper                           // USERID
+	CasePascal                          // UserId
+	CaseCamel                           // userId
+	CaseSnake                           // user_id
+	CaseKebab                           // user-id
+	CaseScreamingSnake                  // USER_ID
+)
+
+// AllCaseStyles lists every CaseStyle, in the order variations are generated
+var AllCaseStyles = []CaseStyle{CaseLower, CaseUpper, CasePascal, CaseCamel, CaseSnake, CaseKebab, CaseScreamingSnake}
+
+// GenerateCaseVariationsExt splits word on spaces, underscores, hyphens and
+// camelCase boundaries and recombines it in each of the given styles, or in
+// all of them when none are given. Duplicates are dropped, so "user id"
+// yields userid, USERID, UserId, userId, user_id, user-id and USER_ID.
+func GenerateCaseVariationsExt(word string, styles ...CaseStyle) []string {
+	words := splitWords(word)
+	if len(words) == 0 {
+		return []string{}
+	}
+	if len(styles) == 0 {
+		styles = AllCaseStyles
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, style := range styles {
+		variation := applyCaseStyle(words, style)
+		if variation != "" && !seen[variation] {
+			seen[variation] = true
+			result = append(result, variation)
+		}
 	}
 	return result
 }
 
+// applyCaseStyle joins lowercase words in the given style
+func applyCaseStyle(words []string, style CaseStyle) string {
+	switch style {
+	case CaseLower:
+		return strings.Join(words, "")
+	case CaseUpper:
+		return strings.ToUpper(strings.Join(words, ""))
+	case CasePascal:
+		return joinCapitalized(words, 0)
+	case CaseCamel:
+		return joinCapitalized(words, 1)
+	case CaseSnake:
+		return strings.Join(words, "_")
+	case CaseKebab:
+		return strings.Join(words, "-")
+	case CaseScreamingSnake:
+		return strings.ToUpper(strings.Join(words, "_"))
+	}
+	return ""
+}
+
+// joinCapitalized joins words, capitalizing each from index from onwards
+func joinCapitalized(words []string, from int) string {
+	var sb strings.Builder
+	for n, w := range words {
+		if n >= from {
+			runes := []rune(w)
+			w = strings.ToUpper(string(runes[0])) + string(runes[1:])
+		}
+		sb.WriteString(w)
+	}
+	return sb.String()
+}
+
+// splitWords breaks s into lowercase words at separators and at the start
+// of an uppercase run in camelCase input
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for n, r := range runes {
+		switch {
+		case r == ' ' || r == '_' || r == '-' || r == '\t':
+			flush()
+			continue
+		case unicode.IsUpper(r) && n > 0 && unicode.IsLower(runes[n-1]):
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
 // Truncate truncates a string to a maximum length with optional suffix
 func Truncate(s string, maxLen int, suffix string) string {
 	if len(s) <= maxLen {
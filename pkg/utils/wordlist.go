@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"strconv"
+)
+
+// WordTransform maps one word to the words it expands into. Transforms are
+// applied in sequence by TransformWordlist.
+type WordTransform func(word string) []string
+
+// TransformWordlist runs words through each transform in turn, feeding the
+// output of one into the next, and returns the result without duplicates in
+// first-seen order. With no transforms it just deduplicates words.
+//
+//	utils.TransformWordlist(words,
+//		utils.KeepOriginal(utils.CaseVariants()),
+//		utils.KeepOriginal(utils.AppendRange(1, 3)),
+//	)
+//
+// expands "admin" into admin, admin1..admin3, Admin, Admin1..Admin3 and so on.
+func TransformWordlist(words []string, transforms ...WordTransform) []string {
+	result := dedupe(words)
+	for _, transform := range transforms {
+		var next []string
+		for _, word := range result {
+			next = append(next, transform(word)...)
+		}
+		result = dedupe(next)
+	}
+	return result
+}
+
+// KeepOriginal wraps a transform so each word is kept alongside its
+// transformed forms
+func KeepOriginal(transform WordTransform) WordTransform {
+	return func(word string) []string {
+		return append([]string{word}, transform(word)...)
+	}
+}
+
+// AddPrefix puts each prefix in front of the word
+func AddPrefix(prefixes ...string) WordTransform {
+	return func(word string) []string {
+		result := make([]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			result = append(result, prefix+word)
+		}
+		return result
+	}
+}
+
+// AddSuffix appends each suffix to the word, e.g. ".bak" or "_old"
+func AddSuffix(suffixes ...string) WordTransform {
+	return func(word string) []string {
+		result := make([]string, 0, len(suffixes))
+		for _, suffix := range suffixes {
+			result = append(result, word+suffix)
+		}
+		return result
+	}
+}
+
+// CaseVariants produces the word in the given case styles, as
+// GenerateCaseVariationsExt does. With no styles it produces lowercase,
+// PascalCase and UPPERCASE like GenerateCaseVariations.
+func CaseVariants(styles ...CaseStyle) WordTransform {
+	return func(word string) []string {
+		if len(styles) == 0 {
+			return GenerateCaseVariations(word)
+		}
+		return GenerateCaseVariationsExt(word, styles...)
+	}
+}
+
+// AppendRange appends each number from start to end inclusive, so
+// AppendRange(1, 3) turns "user" into user1, user2 and user3
+func AppendRange(start, end int) WordTransform {
+	return func(word string) []string {
+		if end < start {
+			return nil
+		}
+		result := make([]string, 0, end-start+1)
+		for n := start; n <= end; n++ {
+			result = append(result, word+strconv.Itoa(n))
+		}
+		return result
+	}
+}
+
+// dedupe drops repeated and empty words, keeping the first occurrence
+func dedupe(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	result := make([]string, 0, len(words))
+	for _, word := range words {
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		result = append(result, word)
+	}
+	return result
+}
\ No newline at end of file